@@ -9,7 +9,7 @@ import (
 
 // 0 means error, 1 means not exist, 2 means general account, 3 means contract account.
 func (c *chain) AccountType(address *types.Address) (uint64, error) {
-	if types.IsPrecompiledContractAddress(*address) {
+	if c.isPrecompiledContractAddress(*address) {
 		return ledger.AccountTypeContract, nil
 	}
 
@@ -26,6 +26,9 @@ func (c *chain) completeBlock(block *ledger.AccountBlock, account *ledger.Accoun
 }
 
 func (c *chain) InsertAccountBlocks(vmAccountBlocks []*vm_context.VmAccountBlock) error {
+	c.writeLock.RLock()
+	defer c.writeLock.RUnlock()
+
 	batch := new(leveldb.Batch)
 	monitor.LogEventNum("chain", "insert", len(vmAccountBlocks))
 	trieSaveCallback := make([]func(), 0)
@@ -569,6 +572,9 @@ func (c *chain) GetUnConfirmAccountBlocks(addr *types.Address) []*ledger.Account
 }
 
 func (c *chain) DeleteAccountBlocks(addr *types.Address, toHeight uint64) (map[types.Address][]*ledger.AccountBlock, error) {
+	c.writeLock.RLock()
+	defer c.writeLock.RUnlock()
+
 	account, accountErr := c.chainDb.Account.GetAccountByAddress(addr)
 	if accountErr != nil {
 		c.log.Error("GetAccountByAddress failed, error is "+accountErr.Error(), "method", "DeleteAccountBlocks", "addr", addr, "toHeight", toHeight)
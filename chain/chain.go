@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vitelabs/go-vite/chain/index"
+	"github.com/vitelabs/go-vite/chain/pruner"
 	"github.com/vitelabs/go-vite/chain/sender"
 	"github.com/vitelabs/go-vite/chain/trie_gc"
 	"github.com/vitelabs/go-vite/chain_db"
@@ -18,6 +20,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 )
 
 type chain struct {
@@ -46,8 +49,12 @@ type chain struct {
 	globalCfg   *config.Config
 	kafkaSender *sender.KafkaSender
 	trieGc      trie_gc.Collector
+	index       *index.Index
+	pruner      *pruner.Pruner
 
 	saveTrieLock sync.RWMutex
+	// the inserts and deletes hold the read lock, the pruner holds the write lock when it deletes the blocks
+	writeLock sync.RWMutex
 
 	saveTrieStatus     uint8
 	saveTrieStatusLock sync.Mutex
@@ -98,17 +105,35 @@ func (c *chain) Init() {
 	c.trieGc = trie_gc.NewCollector(c, c.cfg.LedgerGcRetain)
 
 	// compressor
-	compressor := compress.NewCompressor(c, c.dataDir)
+	codec, getCodecErr := compress.GetCodec(c.cfg.LedgerFilesCodec)
+	if getCodecErr != nil {
+		c.log.Crit("GetCodec failed, error is "+getCodecErr.Error(), "method", "Init")
+	}
+	compressor := compress.NewCompressor(c, c.dataDir, codec, c.cfg.LedgerFilesChunkSize, time.Duration(c.cfg.LedgerFilesInterval)*time.Second, c.cfg.LedgerFilesKeepOrphans)
 	c.compressor = compressor
 
 	// kafka sender
-	if len(c.cfg.KafkaProducers) > 0 {
+	if len(c.cfg.KafkaProducers) > 0 || len(c.cfg.EventSinks) > 0 {
 		var newKafkaErr error
 		c.kafkaSender, newKafkaErr = sender.NewKafkaSender(c, filepath.Join(c.dataDir, "ledger_mq"))
 		if newKafkaErr != nil {
 			c.log.Crit("NewKafkaSender failed, error is " + newKafkaErr.Error())
 		}
 	}
+
+	// index
+	if c.cfg.OpenLogIndex || c.cfg.OpenTxIndex {
+		var newIndexErr error
+		c.index, newIndexErr = index.NewIndex(c, filepath.Join(c.dataDir, "ledger_index"), c.cfg.OpenLogIndex, c.cfg.OpenTxIndex)
+		if newIndexErr != nil {
+			c.log.Crit("NewIndex failed, error is " + newIndexErr.Error())
+		}
+	}
+
+	// pruner
+	if c.cfg.LedgerPruneRetain > 0 {
+		c.pruner = pruner.NewPruner(c, c.pruneRetain(), c.prunableHeight, &c.writeLock)
+	}
 	// Finish initialize
 	c.log.Info("Chain module initialized")
 }
@@ -255,18 +280,46 @@ func (c *chain) Start() {
 				c.log.Crit("Start kafka sender failed, error is " + startErr.Error())
 			}
 		}
+		for _, sink := range c.cfg.EventSinks {
+			startErr := c.kafkaSender.StartSink(sink.Type, sink.Target)
+			if startErr != nil {
+				c.log.Crit("Start event sink failed, error is " + startErr.Error())
+			}
+		}
 	}
 
 	// trie gc
 	c.trieGc.Start()
 
+	// index
+	if c.index != nil {
+		c.RegisterInsertSnapshotBlocksSuccess(c.index.InsertSnapshotBlocks)
+		c.RegisterDeleteSnapshotBlocksSuccess(c.index.DeleteSnapshotBlocks)
+		c.index.Start()
+	}
+
+	// pruner
+	if c.pruner != nil {
+		c.pruner.Start()
+	}
+
 	c.log.Info("Chain module started")
 }
 
 func (c *chain) Stop() {
+	// pruner
+	if c.pruner != nil {
+		c.pruner.Stop()
+	}
+
 	// trie gc
 	c.trieGc.Stop()
 
+	// index
+	if c.index != nil {
+		c.index.Stop()
+	}
+
 	// Stop compress
 	c.log.Info("Stop chain module")
 
@@ -290,6 +343,12 @@ func (c *chain) Destroy() {
 	c.chainDb.Db().Close()
 	c.chainDb = nil
 
+	// index
+	if c.index != nil {
+		c.index.Close()
+		c.index = nil
+	}
+
 	// compressor
 	c.compressor = nil
 
@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"github.com/vitelabs/go-vite/vm/contracts"
 	"github.com/vitelabs/go-vite/vm/contracts/abi"
 	"math/big"
 
@@ -10,6 +11,16 @@ import (
 	"github.com/vitelabs/go-vite/vm_context"
 )
 
+// isPrecompiledContractAddress checks the address at the latest snapshot height,
+// the contracts activated at a fork height are ordinary accounts before it
+func (c *chain) isPrecompiledContractAddress(addr types.Address) bool {
+	var height uint64
+	if latestSnapshotBlock := c.GetLatestSnapshotBlock(); latestSnapshotBlock != nil {
+		height = latestSnapshotBlock.Height
+	}
+	return contracts.IsPrecompiledContractAddress(addr, height)
+}
+
 func (c *chain) GetContractGidByAccountBlock(block *ledger.AccountBlock) (*types.Gid, error) {
 	if block == nil {
 		return nil, nil
@@ -24,7 +35,7 @@ func (c *chain) GetContractGid(addr *types.Address) (*types.Gid, error) {
 		return nil, nil
 	}
 
-	if types.IsPrecompiledContractAddress(*addr) {
+	if c.isPrecompiledContractAddress(*addr) {
 		return &types.DELEGATE_GID, nil
 	}
 
@@ -0,0 +1,299 @@
+// Package export writes the ledger to csv files which can be loaded by the analytics tools. Every table is split into
+// partitions by the snapshot height, the account blocks, the vm logs and the token transfers are put into the
+// partition of the snapshot block which confirms them.
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vitelabs/go-vite/common/helper"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+const (
+	TableSnapshotBlocks = "snapshot_blocks"
+	TableAccountBlocks  = "account_blocks"
+	TableVmLogs         = "vm_logs"
+	TableTokenTransfers = "token_transfers"
+)
+
+const DefaultPartitionSize = uint64(10000)
+
+var tableHeaders = map[string][]string{
+	TableSnapshotBlocks: {"height", "hash", "prev_hash", "producer", "timestamp", "state_hash", "account_block_count"},
+	TableAccountBlocks: {"snapshot_height", "hash", "block_type", "account_address", "height", "prev_hash",
+		"to_address", "from_block_hash", "token_id", "amount", "fee", "quota", "data", "log_hash", "timestamp"},
+	TableVmLogs:         {"snapshot_height", "block_hash", "log_index", "address", "topics", "data"},
+	TableTokenTransfers: {"snapshot_height", "hash", "from_address", "to_address", "token_id", "amount", "timestamp"},
+}
+
+// GetVmLogListFunc returns the vm logs of the log hash, it's nil when the vm logs are not available.
+type GetVmLogListFunc func(logHash *types.Hash) (ledger.VmLogList, error)
+
+type partition struct {
+	index  uint64
+	file   *os.File
+	writer *csv.Writer
+}
+
+type Exporter struct {
+	dir           string
+	partitionSize uint64
+
+	partitions map[string]*partition
+}
+
+func NewExporter(dir string, partitionSize uint64) (*Exporter, error) {
+	if partitionSize <= 0 {
+		partitionSize = DefaultPartitionSize
+	}
+
+	for table := range tableHeaders {
+		if err := os.MkdirAll(filepath.Join(dir, table), 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Exporter{
+		dir:           dir,
+		partitionSize: partitionSize,
+		partitions:    make(map[string]*partition),
+	}, nil
+}
+
+// PartitionFileName returns the file name of the partition which contains the snapshot height.
+func (e *Exporter) PartitionFileName(table string, snapshotHeight uint64) string {
+	index := (snapshotHeight - 1) / e.partitionSize
+	return filepath.Join(e.dir, table, fmt.Sprintf("%d-%d.csv", index*e.partitionSize+1, (index+1)*e.partitionSize))
+}
+
+func (e *Exporter) write(table string, snapshotHeight uint64, record []string) error {
+	index := (snapshotHeight - 1) / e.partitionSize
+
+	p := e.partitions[table]
+	if p == nil || p.index != index {
+		if p != nil {
+			if err := p.close(); err != nil {
+				return err
+			}
+		}
+
+		file, err := os.Create(e.PartitionFileName(table, snapshotHeight))
+		if err != nil {
+			return err
+		}
+		p = &partition{
+			index:  index,
+			file:   file,
+			writer: csv.NewWriter(file),
+		}
+		e.partitions[table] = p
+
+		if err := p.writer.Write(tableHeaders[table]); err != nil {
+			return err
+		}
+	}
+
+	return p.writer.Write(record)
+}
+
+func (p *partition) close() error {
+	p.writer.Flush()
+	if err := p.writer.Error(); err != nil {
+		p.file.Close()
+		return err
+	}
+	return p.file.Close()
+}
+
+func (e *Exporter) Close() error {
+	var closeErr error
+	for table, p := range e.partitions {
+		if err := p.close(); err != nil && closeErr == nil {
+			closeErr = err
+		}
+		delete(e.partitions, table)
+	}
+	return closeErr
+}
+
+// WriteSubLedger writes the snapshot blocks and the account blocks confirmed by them, the account blocks
+// which are not confirmed by the snapshot blocks are skipped. The snapshot blocks must be ahead of the
+// snapshot blocks of the former calls.
+func (e *Exporter) WriteSubLedger(snapshotBlocks []*ledger.SnapshotBlock, accountBlocks map[types.Address][]*ledger.AccountBlock,
+	getVmLogList GetVmLogListFunc) error {
+	return e.writeSubLedger(snapshotBlocks, accountBlocks, getVmLogList, 0, helper.MaxUint64)
+}
+
+// writeSubLedger writes the snapshot blocks between fromHeight and toHeight, the snapshot blocks out of the range
+// are still used to find out which account blocks are confirmed by them.
+func (e *Exporter) writeSubLedger(snapshotBlocks []*ledger.SnapshotBlock, accountBlocks map[types.Address][]*ledger.AccountBlock,
+	getVmLogList GetVmLogListFunc, fromHeight, toHeight uint64) error {
+	sort.Slice(snapshotBlocks, func(i, j int) bool {
+		return snapshotBlocks[i].Height < snapshotBlocks[j].Height
+	})
+
+	confirmed := confirmAccountBlocks(snapshotBlocks, accountBlocks)
+
+	for _, snapshotBlock := range snapshotBlocks {
+		if snapshotBlock.Height < fromHeight || snapshotBlock.Height > toHeight {
+			continue
+		}
+
+		if err := e.write(TableSnapshotBlocks, snapshotBlock.Height, []string{
+			strconv.FormatUint(snapshotBlock.Height, 10),
+			snapshotBlock.Hash.String(),
+			snapshotBlock.PrevHash.String(),
+			snapshotBlock.Producer().String(),
+			formatTimestamp(snapshotBlock.Timestamp.Unix()),
+			snapshotBlock.StateHash.String(),
+			strconv.Itoa(len(snapshotBlock.SnapshotContent)),
+		}); err != nil {
+			return err
+		}
+
+		for _, block := range confirmed[snapshotBlock.Height] {
+			if err := e.writeAccountBlock(snapshotBlock.Height, block, getVmLogList); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) writeAccountBlock(snapshotHeight uint64, block *ledger.AccountBlock, getVmLogList GetVmLogListFunc) error {
+	height := strconv.FormatUint(snapshotHeight, 10)
+	timestamp := ""
+	if block.Timestamp != nil {
+		timestamp = formatTimestamp(block.Timestamp.Unix())
+	}
+
+	var toAddress, tokenId, amount, fromBlockHash string
+	if block.IsSendBlock() {
+		toAddress = block.ToAddress.String()
+		tokenId = block.TokenId.String()
+		amount = formatAmount(block.Amount)
+	} else {
+		fromBlockHash = block.FromBlockHash.String()
+	}
+
+	logHash := ""
+	if block.LogHash != nil {
+		logHash = block.LogHash.String()
+	}
+
+	if err := e.write(TableAccountBlocks, snapshotHeight, []string{
+		height,
+		block.Hash.String(),
+		strconv.Itoa(int(block.BlockType)),
+		block.AccountAddress.String(),
+		strconv.FormatUint(block.Height, 10),
+		block.PrevHash.String(),
+		toAddress,
+		fromBlockHash,
+		tokenId,
+		amount,
+		formatAmount(block.Fee),
+		strconv.FormatUint(block.Quota, 10),
+		hex.EncodeToString(block.Data),
+		logHash,
+		timestamp,
+	}); err != nil {
+		return err
+	}
+
+	if block.IsSendBlock() && block.Amount != nil && block.Amount.Sign() > 0 {
+		if err := e.write(TableTokenTransfers, snapshotHeight, []string{
+			height,
+			block.Hash.String(),
+			block.AccountAddress.String(),
+			toAddress,
+			tokenId,
+			amount,
+			timestamp,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if block.LogHash == nil || getVmLogList == nil {
+		return nil
+	}
+	logList, err := getVmLogList(block.LogHash)
+	if err != nil {
+		return err
+	}
+	for index, vmLog := range logList {
+		topics := make([]string, 0, len(vmLog.Topics))
+		for _, topic := range vmLog.Topics {
+			topics = append(topics, topic.String())
+		}
+
+		if err := e.write(TableVmLogs, snapshotHeight, []string{
+			height,
+			block.Hash.String(),
+			strconv.Itoa(index),
+			block.AccountAddress.String(),
+			strings.Join(topics, ";"),
+			hex.EncodeToString(vmLog.Data),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// confirmAccountBlocks groups the account blocks by the height of the snapshot block which confirms them,
+// a snapshot block confirms the block in its snapshot content and all the unconfirmed blocks before it.
+func confirmAccountBlocks(snapshotBlocks []*ledger.SnapshotBlock, accountBlocks map[types.Address][]*ledger.AccountBlock) map[uint64][]*ledger.AccountBlock {
+	for _, blocks := range accountBlocks {
+		sort.Slice(blocks, func(i, j int) bool {
+			return blocks[i].Height < blocks[j].Height
+		})
+	}
+
+	confirmed := make(map[uint64][]*ledger.AccountBlock)
+	nextIndex := make(map[types.Address]int)
+	for _, snapshotBlock := range snapshotBlocks {
+		addrList := make([]types.Address, 0, len(snapshotBlock.SnapshotContent))
+		for addr := range snapshotBlock.SnapshotContent {
+			addrList = append(addrList, addr)
+		}
+		sort.Slice(addrList, func(i, j int) bool {
+			return bytes.Compare(addrList[i].Bytes(), addrList[j].Bytes()) < 0
+		})
+
+		for _, addr := range addrList {
+			blocks := accountBlocks[addr]
+			confirmedHeight := snapshotBlock.SnapshotContent[addr].Height
+
+			index := nextIndex[addr]
+			for ; index < len(blocks) && blocks[index].Height <= confirmedHeight; index++ {
+				confirmed[snapshotBlock.Height] = append(confirmed[snapshotBlock.Height], blocks[index])
+			}
+			nextIndex[addr] = index
+		}
+	}
+	return confirmed
+}
+
+func formatTimestamp(unix int64) string {
+	return strconv.FormatInt(unix, 10)
+}
+
+func formatAmount(amount *big.Int) string {
+	if amount == nil {
+		return "0"
+	}
+	return amount.String()
+}
@@ -0,0 +1,166 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/compress"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+func newTestSubLedger() ([]*ledger.SnapshotBlock, map[types.Address][]*ledger.AccountBlock) {
+	addr, _ := types.BytesToAddress([]byte("12345678901234567890"))
+	to, _ := types.BytesToAddress([]byte("09876543210987654321"))
+	now := time.Unix(1541000000, 0)
+
+	var accountBlocks []*ledger.AccountBlock
+	for height := uint64(1); height <= 3; height++ {
+		block := &ledger.AccountBlock{
+			BlockType:      ledger.BlockTypeSendCall,
+			AccountAddress: addr,
+			ToAddress:      to,
+			TokenId:        ledger.ViteTokenId,
+			Amount:         big.NewInt(int64(height - 1)),
+			Fee:            big.NewInt(0),
+			Height:         height,
+			Timestamp:      &now,
+		}
+		block.Hash = block.ComputeHash()
+		accountBlocks = append(accountBlocks, block)
+	}
+	logHash := types.DataHash([]byte("log"))
+	accountBlocks[2].LogHash = &logHash
+
+	var snapshotBlocks []*ledger.SnapshotBlock
+	for height := uint64(1); height <= 3; height++ {
+		snapshotBlock := &ledger.SnapshotBlock{
+			Height:          height,
+			Timestamp:       &now,
+			SnapshotContent: ledger.SnapshotContent{},
+		}
+		if height > 1 {
+			snapshotBlock.SnapshotContent[addr] = &ledger.HashHeight{
+				Height: height,
+				Hash:   accountBlocks[height-1].Hash,
+			}
+		}
+		snapshotBlock.Hash = snapshotBlock.ComputeHash()
+		snapshotBlocks = append(snapshotBlocks, snapshotBlock)
+	}
+
+	return snapshotBlocks, map[types.Address][]*ledger.AccountBlock{addr: accountBlocks}
+}
+
+func readCsv(t *testing.T, filename string) [][]string {
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestExporter_WriteSubLedger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ledger_export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	exporter, err := NewExporter(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotBlocks, accountBlocks := newTestSubLedger()
+	getVmLogList := func(logHash *types.Hash) (ledger.VmLogList, error) {
+		return ledger.VmLogList{{Topics: []types.Hash{*logHash}, Data: []byte{1, 2}}}, nil
+	}
+	if err := exporter.WriteSubLedger(snapshotBlocks, accountBlocks, getVmLogList); err != nil {
+		t.Fatal(err)
+	}
+	if err := exporter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if records := readCsv(t, exporter.PartitionFileName(TableSnapshotBlocks, 1)); len(records) != 3 {
+		t.Fatalf("snapshot blocks of partition 1 should be 2, got %d", len(records)-1)
+	}
+
+	// blocks 1 and 2 are confirmed by snapshot block 2, block 3 by snapshot block 3
+	records := readCsv(t, exporter.PartitionFileName(TableAccountBlocks, 1))
+	if len(records) != 3 || records[1][0] != "2" || records[2][0] != "2" {
+		t.Fatalf("unexpected account blocks of partition 1: %v", records)
+	}
+	records = readCsv(t, filepath.Join(dir, TableAccountBlocks, "3-4.csv"))
+	if len(records) != 2 || records[1][0] != "3" || records[1][4] != "3" {
+		t.Fatalf("unexpected account blocks of partition 2: %v", records)
+	}
+
+	// the send block of zero amount is not a transfer
+	records = readCsv(t, exporter.PartitionFileName(TableTokenTransfers, 1))
+	if len(records) != 2 || records[1][5] != "1" {
+		t.Fatalf("unexpected token transfers: %v", records)
+	}
+
+	records = readCsv(t, exporter.PartitionFileName(TableVmLogs, 3))
+	if len(records) != 2 || records[1][5] != "0102" {
+		t.Fatalf("unexpected vm logs: %v", records)
+	}
+}
+
+func TestReadLedgerFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ledger_export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	snapshotBlocks, accountBlocks := newTestSubLedger()
+	var blocks []ledger.Block
+	for _, snapshotBlock := range snapshotBlocks {
+		blocks = append(blocks, snapshotBlock)
+	}
+	for _, accountChain := range accountBlocks {
+		for _, accountBlock := range accountChain {
+			blocks = append(blocks, accountBlock)
+		}
+	}
+
+	filename := filepath.Join(dir, "subgraph_1_3")
+	file, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := compress.BlockFormatter(file, func(uint64, uint64) ([]ledger.Block, error) {
+		return blocks, io.EOF
+	}); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	readSnapshotBlocks, readAccountBlocks, err := readLedgerFile(filename, 6, compress.CodecNone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(readSnapshotBlocks) != 3 || len(readAccountBlocks) != 1 {
+		t.Fatalf("unexpected blocks, %d snapshot blocks, %d accounts", len(readSnapshotBlocks), len(readAccountBlocks))
+	}
+
+	confirmed := confirmAccountBlocks(readSnapshotBlocks, readAccountBlocks)
+	if len(confirmed[2]) != 2 || len(confirmed[3]) != 1 {
+		t.Fatalf("unexpected confirmed blocks: %v", confirmed)
+	}
+}
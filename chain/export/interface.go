@@ -0,0 +1,12 @@
+package export
+
+import (
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+type Chain interface {
+	GetLatestSnapshotBlock() *ledger.SnapshotBlock
+	GetConfirmSubLedger(fromHeight uint64, toHeight uint64) ([]*ledger.SnapshotBlock, map[types.Address][]*ledger.AccountBlock, error)
+	GetVmLogList(logListHash *types.Hash) (ledger.VmLogList, error)
+}
@@ -0,0 +1,105 @@
+package export
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/compress"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+// chainBatchLen is the count of snapshot blocks read from the chain db at one time
+const chainBatchLen = uint64(1000)
+
+// ExportChain exports the ledger between the snapshot heights from the chain db, the vm logs are included.
+func ExportChain(chain Chain, exporter *Exporter, fromHeight, toHeight uint64) error {
+	if latestHeight := chain.GetLatestSnapshotBlock().Height; toHeight == 0 || toHeight > latestHeight {
+		toHeight = latestHeight
+	}
+	if fromHeight <= 0 {
+		fromHeight = 1
+	}
+
+	for height := fromHeight; height <= toHeight; height += chainBatchLen {
+		endHeight := height + chainBatchLen - 1
+		if endHeight > toHeight {
+			endHeight = toHeight
+		}
+
+		snapshotBlocks, accountBlocks, err := chain.GetConfirmSubLedger(height, endHeight)
+		if err != nil {
+			return err
+		}
+		if err := exporter.WriteSubLedger(snapshotBlocks, accountBlocks, chain.GetVmLogList); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportLedgerFiles exports the ledger between the snapshot heights from the ledger files saved by the compressor,
+// the vm logs are not in the ledger files, so they are exported only if getVmLogList is not nil.
+func ExportLedgerFiles(ledgerFilesDir string, exporter *Exporter, fromHeight, toHeight uint64, getVmLogList GetVmLogListFunc) error {
+	// the files are only read
+	indexer := compress.NewIndexer(ledgerFilesDir, true)
+	defer indexer.Clear()
+
+	if latestHeight := indexer.LatestHeight(); toHeight == 0 || toHeight > latestHeight {
+		toHeight = latestHeight
+	}
+	if fromHeight <= 0 {
+		fromHeight = 1
+	}
+
+	for _, fileMeta := range indexer.Get(fromHeight, toHeight) {
+		snapshotBlocks, accountBlocks, err := readLedgerFile(filepath.Join(ledgerFilesDir, fileMeta.Filename), fileMeta.BlockNumbers, fileMeta.Codec)
+		if err != nil {
+			return err
+		}
+		if err := exporter.writeSubLedger(snapshotBlocks, accountBlocks, getVmLogList, fromHeight, toHeight); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readLedgerFile(filename string, blockNumbers uint64, codecName string) ([]*ledger.SnapshotBlock, map[types.Address][]*ledger.AccountBlock, error) {
+	codec, err := compress.GetCodec(codecName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file := compress.NewFileReader(filename, codec)
+	if file == nil {
+		return nil, nil, errors.New(fmt.Sprintf("open ledger file %s failed", filename))
+	}
+	defer file.Close()
+
+	var snapshotBlocks []*ledger.SnapshotBlock
+	accountBlocks := make(map[types.Address][]*ledger.AccountBlock)
+
+	var parseErr error
+	compress.BlockParser(file, blockNumbers, func(block ledger.Block, err error) {
+		if parseErr != nil {
+			return
+		}
+		if err != nil {
+			parseErr = err
+			return
+		}
+
+		switch block.(type) {
+		case *ledger.SnapshotBlock:
+			snapshotBlocks = append(snapshotBlocks, block.(*ledger.SnapshotBlock))
+		case *ledger.AccountBlock:
+			accountBlock := block.(*ledger.AccountBlock)
+			accountBlocks[accountBlock.AccountAddress] = append(accountBlocks[accountBlock.AccountAddress], accountBlock)
+		}
+	})
+	if parseErr != nil {
+		return nil, nil, errors.New(fmt.Sprintf("parse ledger file %s failed, error is %s", filename, parseErr))
+	}
+	return snapshotBlocks, accountBlocks, nil
+}
@@ -0,0 +1,10 @@
+package index
+
+const (
+	DBKP_INDEXED_HEIGHT = byte(1)
+	DBKP_HEIGHT_KEYS    = byte(2)
+	DBKP_LOG_ADDR       = byte(3)
+	DBKP_LOG_TOPIC      = byte(4)
+	DBKP_TX_ADDR        = byte(5)
+	DBKP_TX_TOKEN       = byte(6)
+)
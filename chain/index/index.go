@@ -0,0 +1,244 @@
+package index
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/log15"
+)
+
+// Index maintains the optional secondary indexes of the confirmed account blocks. It is kept in its own db
+// and follows the snapshot chain by height, so it can be removed and rebuilt from the ledger at any time.
+type Index struct {
+	chain Chain
+	db    *leveldb.DB
+
+	openLogIndex bool
+	openTxIndex  bool
+
+	lock             sync.Mutex
+	deleteFromHeight uint64
+
+	notify   chan struct{}
+	terminal chan struct{}
+	wg       sync.WaitGroup
+
+	log log15.Logger
+}
+
+func NewIndex(chain Chain, dirName string, openLogIndex bool, openTxIndex bool) (*Index, error) {
+	// create directory
+	if _, err := os.Stat(dirName); os.IsNotExist(err) {
+		os.Mkdir(dirName, 0755)
+	}
+
+	db, openDbErr := leveldb.OpenFile(dirName, nil)
+	if openDbErr != nil {
+		return nil, openDbErr
+	}
+
+	return &Index{
+		chain:        chain,
+		db:           db,
+		openLogIndex: openLogIndex,
+		openTxIndex:  openTxIndex,
+		notify:       make(chan struct{}, 1),
+		log:          log15.New("module", "chain/index"),
+	}, nil
+}
+
+func (idx *Index) Start() {
+	idx.terminal = make(chan struct{})
+	idx.wg.Add(1)
+	go idx.loop()
+
+	// catch up with the snapshot chain
+	idx.trigger()
+}
+
+func (idx *Index) Stop() {
+	close(idx.terminal)
+	idx.wg.Wait()
+}
+
+func (idx *Index) Close() error {
+	return idx.db.Close()
+}
+
+// InsertSnapshotBlocks is registered as the insert snapshot blocks success event of the chain.
+func (idx *Index) InsertSnapshotBlocks(snapshotBlocks []*ledger.SnapshotBlock) {
+	idx.trigger()
+}
+
+// DeleteSnapshotBlocks is registered as the delete snapshot blocks success event of the chain.
+func (idx *Index) DeleteSnapshotBlocks(snapshotBlocks []*ledger.SnapshotBlock) {
+	idx.lock.Lock()
+	for _, snapshotBlock := range snapshotBlocks {
+		if idx.deleteFromHeight == 0 || snapshotBlock.Height < idx.deleteFromHeight {
+			idx.deleteFromHeight = snapshotBlock.Height
+		}
+	}
+	idx.lock.Unlock()
+
+	idx.trigger()
+}
+
+func (idx *Index) trigger() {
+	select {
+	case idx.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (idx *Index) loop() {
+	defer idx.wg.Done()
+	for {
+		select {
+		case <-idx.terminal:
+			return
+		case <-idx.notify:
+			if err := idx.sync(); err != nil {
+				idx.log.Error("sync failed, error is "+err.Error(), "method", "loop")
+			}
+		}
+	}
+}
+
+func (idx *Index) popDeleteFromHeight() uint64 {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	height := idx.deleteFromHeight
+	idx.deleteFromHeight = 0
+	return height
+}
+
+func (idx *Index) sync() error {
+	for {
+		if height := idx.popDeleteFromHeight(); height > 0 {
+			if err := idx.deleteFrom(height); err != nil {
+				return err
+			}
+		}
+
+		indexedHeight, err := idx.IndexedHeight()
+		if err != nil {
+			return err
+		}
+
+		latestHeight := idx.chain.GetLatestSnapshotBlock().Height
+		if indexedHeight > latestHeight {
+			// the snapshot chain is rolled back while the index is not running
+			if err := idx.deleteFrom(latestHeight + 1); err != nil {
+				return err
+			}
+			continue
+		}
+		if indexedHeight == latestHeight {
+			return nil
+		}
+
+		select {
+		case <-idx.terminal:
+			return nil
+		default:
+		}
+
+		if err := idx.indexHeight(indexedHeight + 1); err != nil {
+			return err
+		}
+	}
+}
+
+// IndexedHeight returns the height of the latest snapshot block which is indexed.
+func (idx *Index) IndexedHeight() (uint64, error) {
+	value, err := idx.db.Get([]byte{DBKP_INDEXED_HEIGHT}, nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+func (idx *Index) writeIndexedHeight(batch *leveldb.Batch, height uint64) {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, height)
+	batch.Put([]byte{DBKP_INDEXED_HEIGHT}, value)
+}
+
+func (idx *Index) indexHeight(height uint64) error {
+	snapshotBlocks, subLedger, err := idx.chain.GetConfirmSubLedger(height, height)
+	if err != nil {
+		return err
+	}
+	if len(snapshotBlocks) <= 0 {
+		return errors.New("snapshot block is not found")
+	}
+
+	batch := new(leveldb.Batch)
+	var keys [][]byte
+	if idx.openLogIndex {
+		logKeys, err := idx.writeLogIndex(batch, height, subLedger)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, logKeys...)
+	}
+	if idx.openTxIndex {
+		keys = append(keys, idx.writeTxIndex(batch, height, subLedger)...)
+	}
+
+	// keys of the height are recorded to delete them when the snapshot block is rolled back
+	var value []byte
+	for _, key := range keys {
+		value = append(value, byte(len(key)))
+		value = append(value, key...)
+	}
+	heightKey, _ := database.EncodeKey(DBKP_HEIGHT_KEYS, height)
+	batch.Put(heightKey, value)
+
+	idx.writeIndexedHeight(batch, height)
+	return idx.db.Write(batch, nil)
+}
+
+func (idx *Index) deleteFrom(height uint64) error {
+	indexedHeight, err := idx.IndexedHeight()
+	if err != nil {
+		return err
+	}
+	if indexedHeight < height {
+		return nil
+	}
+
+	batch := new(leveldb.Batch)
+	startKey, _ := database.EncodeKey(DBKP_HEIGHT_KEYS, height)
+	iter := idx.db.NewIterator(&util.Range{Start: startKey, Limit: []byte{DBKP_HEIGHT_KEYS + 1}}, nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		value := iter.Value()
+		for len(value) > 0 {
+			keyLen := int(value[0])
+			if len(value) < keyLen+1 {
+				return errors.New("keys of the height are broken")
+			}
+			batch.Delete(value[1 : keyLen+1])
+			value = value[keyLen+1:]
+		}
+		batch.Delete(append([]byte(nil), iter.Key()...))
+	}
+	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+		return err
+	}
+
+	idx.writeIndexedHeight(batch, height-1)
+	return idx.db.Write(batch, nil)
+}
@@ -0,0 +1,18 @@
+package index
+
+import (
+	"time"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+type Chain interface {
+	GetLatestSnapshotBlock() *ledger.SnapshotBlock
+	GetSnapshotBlockBeforeTime(blockCreatedTime *time.Time) (*ledger.SnapshotBlock, error)
+	GetConfirmSubLedger(fromHeight uint64, toHeight uint64) ([]*ledger.SnapshotBlock, map[types.Address][]*ledger.AccountBlock, error)
+	GetAccountBlockByHash(blockHash *types.Hash) (*ledger.AccountBlock, error)
+	GetAccountBlockByHeight(addr *types.Address, height uint64) (*ledger.AccountBlock, error)
+	GetAccountBlockMetaByHash(hash *types.Hash) (*ledger.AccountBlockMeta, error)
+	GetVmLogList(logListHash *types.Hash) (ledger.VmLogList, error)
+}
@@ -0,0 +1,288 @@
+package index
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+// only the first maxIndexedTopics topics of a log are indexed
+const maxIndexedTopics = 4
+
+var (
+	ErrLogIndexNotOpen    = errors.New("log index is not open")
+	ErrLogFilterNoAddress = errors.New("addresses of the log filter are empty")
+	ErrInvalidCursor      = errors.New("invalid cursor")
+)
+
+// LogFilter selects the logs of the addresses which are confirmed by snapshot blocks between FromHeight and ToHeight,
+// a ToHeight of 0 means the latest indexed height. Topics match by position, an empty position matches any topic.
+type LogFilter struct {
+	FromHeight uint64
+	ToHeight   uint64
+	Addresses  []types.Address
+	Topics     [][]types.Hash
+}
+
+type LogRecord struct {
+	Log              *ledger.VmLog
+	Addr             types.Address
+	AccountBlockHash types.Hash
+	AccountHeight    uint64
+	SnapshotHeight   uint64
+	LogIndex         uint64
+}
+
+type logRef struct {
+	addr           types.Address
+	snapshotHeight uint64
+	blockHash      types.Hash
+	logIndex       uint64
+}
+
+func (idx *Index) writeLogIndex(batch *leveldb.Batch, height uint64, subLedger map[types.Address][]*ledger.AccountBlock) ([][]byte, error) {
+	var keys [][]byte
+	for addr, blocks := range subLedger {
+		for _, block := range blocks {
+			if block.LogHash == nil {
+				continue
+			}
+
+			logList, err := idx.chain.GetVmLogList(block.LogHash)
+			if err != nil {
+				return nil, err
+			}
+
+			for i, log := range logList {
+				key, _ := database.EncodeKey(DBKP_LOG_ADDR, addr.Bytes(), height, block.Hash.Bytes(), uint64(i))
+				batch.Put(key, nil)
+				keys = append(keys, key)
+
+				for pos, topic := range log.Topics {
+					if pos >= maxIndexedTopics {
+						break
+					}
+					key, _ := database.EncodeKey(DBKP_LOG_TOPIC, addr.Bytes(), []byte{byte(pos)}, topic.Bytes(), height, block.Hash.Bytes(), uint64(i))
+					batch.Put(key, nil)
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+	return keys, nil
+}
+
+// the tail of a log index key is the snapshot height, the block hash and the log index, it orders the logs
+const logRefSize = 8 + types.HashSize + 8
+
+// logIterator walks the log index keys under a prefix, the keys of a prefix are in the order of their tails
+type logIterator struct {
+	addr   types.Address
+	iter   iterator.Iterator
+	tail   []byte
+	closed bool
+}
+
+func (it *logIterator) next() {
+	for it.iter.Next() {
+		key := it.iter.Key()
+		if len(key) < logRefSize {
+			continue
+		}
+		it.tail = append(it.tail[:0], key[len(key)-logRefSize:]...)
+		return
+	}
+	it.closed = true
+}
+
+// GetLogs returns at most count logs which match the filter after the cursor, in the order of snapshot height.
+// The cursor is the nextCursor returned by the previous call, the nextCursor is nil on the last page.
+func (idx *Index) GetLogs(filter *LogFilter, cursor []byte, count int) ([]*LogRecord, []byte, error) {
+	if !idx.openLogIndex {
+		return nil, nil, ErrLogIndexNotOpen
+	}
+	if len(filter.Addresses) <= 0 {
+		return nil, nil, ErrLogFilterNoAddress
+	}
+	if len(cursor) > 0 && len(cursor) != logRefSize {
+		return nil, nil, ErrInvalidCursor
+	}
+
+	indexedHeight, err := idx.IndexedHeight()
+	if err != nil {
+		return nil, nil, err
+	}
+	toHeight := filter.ToHeight
+	if toHeight == 0 || toHeight > indexedHeight {
+		toHeight = indexedHeight
+	}
+	if filter.FromHeight > toHeight {
+		return nil, nil, nil
+	}
+
+	// scan the topic position with the fewest choices, other positions are checked against the log
+	pos := -1
+	for i, topics := range filter.Topics {
+		if i >= maxIndexedTopics {
+			break
+		}
+		if len(topics) > 0 && (pos < 0 || len(topics) < len(filter.Topics[pos])) {
+			pos = i
+		}
+	}
+
+	start := make([]byte, 8)
+	binary.BigEndian.PutUint64(start, filter.FromHeight)
+	if bytes.Compare(cursor, start) > 0 {
+		start = cursor
+	}
+
+	var iters []*logIterator
+	defer func() {
+		for _, it := range iters {
+			it.iter.Release()
+		}
+	}()
+	for _, addr := range filter.Addresses {
+		var prefixList [][]byte
+		if pos < 0 {
+			prefix, _ := database.EncodeKey(DBKP_LOG_ADDR, addr.Bytes())
+			prefixList = append(prefixList, prefix)
+		} else {
+			for _, topic := range filter.Topics[pos] {
+				prefix, _ := database.EncodeKey(DBKP_LOG_TOPIC, addr.Bytes(), []byte{byte(pos)}, topic.Bytes())
+				prefixList = append(prefixList, prefix)
+			}
+		}
+
+		for _, prefix := range prefixList {
+			startKey, _ := database.EncodeKey(prefix[0], prefix[1:], start)
+			limitKey, _ := database.EncodeKey(prefix[0], prefix[1:], toHeight+1)
+			it := &logIterator{
+				addr: addr,
+				iter: idx.db.NewIterator(&util.Range{Start: startKey, Limit: limitKey}, nil),
+			}
+			iters = append(iters, it)
+			it.next()
+		}
+	}
+
+	records := make([]*LogRecord, 0)
+	var nextCursor []byte
+	var logListCache *cachedLogList
+	for {
+		// merge the iterators by the tails of the keys, a log found by several iterators is returned once
+		var first *logIterator
+		for _, it := range iters {
+			if !it.closed && (first == nil || bytes.Compare(it.tail, first.tail) < 0) {
+				first = it
+			}
+		}
+		if first == nil {
+			break
+		}
+		tail := append([]byte(nil), first.tail...)
+		ref := parseLogRef(first.addr, tail)
+		for _, it := range iters {
+			if !it.closed && bytes.Equal(it.tail, tail) {
+				it.next()
+			}
+		}
+		if len(cursor) > 0 && bytes.Compare(tail, cursor) <= 0 {
+			continue
+		}
+
+		if logListCache == nil || logListCache.blockHash != ref.blockHash {
+			if logListCache, err = idx.getCachedLogList(ref.blockHash); err != nil {
+				return nil, nil, err
+			}
+		}
+		if logListCache.block == nil || ref.logIndex >= uint64(len(logListCache.logList)) ||
+			!matchTopics(logListCache.logList[ref.logIndex], filter.Topics) {
+			continue
+		}
+
+		if len(records) >= count {
+			return records, nextCursor, nil
+		}
+		records = append(records, &LogRecord{
+			Log:              logListCache.logList[ref.logIndex],
+			Addr:             ref.addr,
+			AccountBlockHash: ref.blockHash,
+			AccountHeight:    logListCache.block.Height,
+			SnapshotHeight:   ref.snapshotHeight,
+			LogIndex:         ref.logIndex,
+		})
+		nextCursor = tail
+	}
+	for _, it := range iters {
+		if err := it.iter.Error(); err != nil && err != leveldb.ErrNotFound {
+			return nil, nil, err
+		}
+	}
+	return records, nil, nil
+}
+
+type cachedLogList struct {
+	blockHash types.Hash
+	block     *ledger.AccountBlock
+	logList   ledger.VmLogList
+}
+
+// getCachedLogList loads the block and its logs, the logs of a block are adjacent in the merged order
+func (idx *Index) getCachedLogList(blockHash types.Hash) (*cachedLogList, error) {
+	cached := &cachedLogList{blockHash: blockHash}
+	block, err := idx.chain.GetAccountBlockByHash(&blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil || block.LogHash == nil {
+		return cached, nil
+	}
+	if cached.logList, err = idx.chain.GetVmLogList(block.LogHash); err != nil {
+		return nil, err
+	}
+	cached.block = block
+	return cached, nil
+}
+
+// the keys of the log index end with snapshot height, block hash and log index
+func parseLogRef(addr types.Address, key []byte) logRef {
+	tail := key[len(key)-logRefSize:]
+	ref := logRef{
+		addr:           addr,
+		snapshotHeight: binary.BigEndian.Uint64(tail[:8]),
+		logIndex:       binary.BigEndian.Uint64(tail[8+types.HashSize:]),
+	}
+	copy(ref.blockHash[:], tail[8:8+types.HashSize])
+	return ref
+}
+
+func matchTopics(log *ledger.VmLog, topics [][]types.Hash) bool {
+	for i, choices := range topics {
+		if len(choices) <= 0 {
+			continue
+		}
+		if i >= len(log.Topics) {
+			return false
+		}
+		matched := false
+		for _, topic := range choices {
+			if topic == log.Topics[i] {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
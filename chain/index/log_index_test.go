@@ -0,0 +1,179 @@
+package index
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+type mockChain struct {
+	latestHeight  uint64
+	snapshotTimes map[uint64]time.Time
+	subLedgers    map[uint64]map[types.Address][]*ledger.AccountBlock
+	blocks        map[types.Hash]*ledger.AccountBlock
+	metas         map[types.Hash]*ledger.AccountBlockMeta
+	logLists      map[types.Hash]ledger.VmLogList
+}
+
+func newMockChain() *mockChain {
+	return &mockChain{
+		snapshotTimes: make(map[uint64]time.Time),
+		subLedgers:    make(map[uint64]map[types.Address][]*ledger.AccountBlock),
+		blocks:        make(map[types.Hash]*ledger.AccountBlock),
+		metas:         make(map[types.Hash]*ledger.AccountBlockMeta),
+		logLists:      make(map[types.Hash]ledger.VmLogList),
+	}
+}
+
+func (c *mockChain) addBlock(snapshotHeight uint64, block *ledger.AccountBlock, logList ledger.VmLogList) {
+	if c.subLedgers[snapshotHeight] == nil {
+		c.subLedgers[snapshotHeight] = make(map[types.Address][]*ledger.AccountBlock)
+	}
+	c.subLedgers[snapshotHeight][block.AccountAddress] = append(c.subLedgers[snapshotHeight][block.AccountAddress], block)
+	c.blocks[block.Hash] = block
+	if logList != nil {
+		logHash := logList.Hash()
+		block.LogHash = logHash
+		c.logLists[*logHash] = logList
+	}
+	if snapshotHeight > c.latestHeight {
+		c.latestHeight = snapshotHeight
+	}
+}
+
+func (c *mockChain) GetLatestSnapshotBlock() *ledger.SnapshotBlock {
+	return &ledger.SnapshotBlock{Height: c.latestHeight}
+}
+
+func (c *mockChain) GetSnapshotBlockBeforeTime(blockCreatedTime *time.Time) (*ledger.SnapshotBlock, error) {
+	for height := c.latestHeight; height > 0; height-- {
+		if timestamp := c.snapshotTimes[height]; timestamp.Before(*blockCreatedTime) {
+			return &ledger.SnapshotBlock{Height: height, Timestamp: &timestamp}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *mockChain) GetConfirmSubLedger(fromHeight uint64, toHeight uint64) ([]*ledger.SnapshotBlock, map[types.Address][]*ledger.AccountBlock, error) {
+	return []*ledger.SnapshotBlock{{Height: fromHeight}}, c.subLedgers[fromHeight], nil
+}
+
+func (c *mockChain) GetAccountBlockByHash(blockHash *types.Hash) (*ledger.AccountBlock, error) {
+	return c.blocks[*blockHash], nil
+}
+
+func (c *mockChain) GetAccountBlockByHeight(addr *types.Address, height uint64) (*ledger.AccountBlock, error) {
+	for _, block := range c.blocks {
+		if block.AccountAddress == *addr && block.Height == height {
+			return block, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *mockChain) GetAccountBlockMetaByHash(hash *types.Hash) (*ledger.AccountBlockMeta, error) {
+	return c.metas[*hash], nil
+}
+
+func (c *mockChain) GetVmLogList(logListHash *types.Hash) (ledger.VmLogList, error) {
+	return c.logLists[*logListHash], nil
+}
+
+func TestLogIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "log_index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	transfer := types.DataHash([]byte("Transfer"))
+	approve := types.DataHash([]byte("Approve"))
+	from := types.DataHash([]byte("from"))
+	addr1, _, _ := types.CreateAddress()
+	addr2, _, _ := types.CreateAddress()
+
+	chain := newMockChain()
+	for height := uint64(1); height <= 3; height++ {
+		block1 := &ledger.AccountBlock{AccountAddress: addr1, Height: height, Hash: types.DataHash([]byte{1, byte(height)})}
+		chain.addBlock(height, block1, ledger.VmLogList{
+			{Topics: []types.Hash{transfer, from}},
+			{Topics: []types.Hash{approve}},
+		})
+		block2 := &ledger.AccountBlock{AccountAddress: addr2, Height: height, Hash: types.DataHash([]byte{2, byte(height)})}
+		chain.addBlock(height, block2, ledger.VmLogList{{Topics: []types.Hash{transfer}}})
+	}
+
+	idx, err := NewIndex(chain, dir, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	if err := idx.sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, nextCursor, err := idx.GetLogs(&LogFilter{Addresses: []types.Address{addr1}}, nil, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 6 || records[0].SnapshotHeight != 1 || records[5].SnapshotHeight != 3 || nextCursor != nil {
+		t.Fatalf("unexpected records %v", records)
+	}
+
+	// page through the transfers from height 2
+	transferFilter := &LogFilter{
+		FromHeight: 2,
+		Addresses:  []types.Address{addr1, addr2},
+		Topics:     [][]types.Hash{{transfer}},
+	}
+	records, nextCursor, _ = idx.GetLogs(transferFilter, nil, 1)
+	if len(records) != 1 || records[0].SnapshotHeight != 2 || nextCursor == nil {
+		t.Fatalf("unexpected first page of transfer %v", records)
+	}
+	first := records[0]
+	records, nextCursor, _ = idx.GetLogs(transferFilter, nextCursor, 2)
+	if len(records) != 2 || records[0].SnapshotHeight != 2 || records[1].SnapshotHeight != 3 || nextCursor == nil {
+		t.Fatalf("unexpected second page of transfer %v", records)
+	}
+	if records[0].AccountBlockHash == first.AccountBlockHash {
+		t.Fatal("the log of the cursor is returned again")
+	}
+	records, nextCursor, _ = idx.GetLogs(transferFilter, nextCursor, 2)
+	if len(records) != 1 || records[0].SnapshotHeight != 3 || nextCursor != nil {
+		t.Fatalf("unexpected last page of transfer %v", records)
+	}
+	if _, _, err := idx.GetLogs(transferFilter, []byte{1}, 2); err != ErrInvalidCursor {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+
+	records, _, _ = idx.GetLogs(&LogFilter{
+		Addresses: []types.Address{addr1, addr2},
+		Topics:    [][]types.Hash{{transfer}, {from}},
+	}, nil, 100)
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records of transfer from, got %v", len(records))
+	}
+	for _, record := range records {
+		if record.Addr != addr1 || record.LogIndex != 0 {
+			t.Fatalf("unexpected record of transfer from %v", record)
+		}
+	}
+
+	// roll back to height 1
+	chain.latestHeight = 1
+	idx.DeleteSnapshotBlocks([]*ledger.SnapshotBlock{{Height: 3}, {Height: 2}})
+	if err := idx.sync(); err != nil {
+		t.Fatal(err)
+	}
+	if indexedHeight, _ := idx.IndexedHeight(); indexedHeight != 1 {
+		t.Fatalf("expected indexed height 1, got %v", indexedHeight)
+	}
+	records, _, _ = idx.GetLogs(&LogFilter{Addresses: []types.Address{addr1, addr2}}, nil, 100)
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records after roll back, got %v", len(records))
+	}
+}
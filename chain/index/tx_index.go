@@ -0,0 +1,222 @@
+package index
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+const (
+	DirectionOut = byte(1)
+	DirectionIn  = byte(2)
+)
+
+// txTimeSlack is how far the timestamp of a send block may be from the snapshot block confirming it,
+// the time bounds of a filter are widened by it to find the snapshot heights to scan
+const txTimeSlack = 10 * time.Minute
+
+var ErrTxIndexNotOpen = errors.New("tx index is not open")
+
+// TxFilter selects the transactions of an address, TokenId and Direction are optional,
+// FromTime and ToTime are unix seconds of the send block and 0 means unbounded.
+// Only the send blocks confirmed within txTimeSlack of the time bounds are found.
+type TxFilter struct {
+	TokenId   *types.TokenTypeId
+	Direction byte
+	FromTime  int64
+	ToTime    int64
+}
+
+// TxRecord is a send block joined with its receive blocks, ReceiveBlocks is empty while the send is on road.
+type TxRecord struct {
+	SendBlock      *ledger.AccountBlock
+	ReceiveBlocks  []*ledger.AccountBlock
+	Direction      byte
+	SnapshotHeight uint64
+}
+
+func (idx *Index) writeTxIndex(batch *leveldb.Batch, height uint64, subLedger map[types.Address][]*ledger.AccountBlock) [][]byte {
+	var keys [][]byte
+	put := func(addr types.Address, block *ledger.AccountBlock, direction byte) {
+		addrKey, _ := database.EncodeKey(DBKP_TX_ADDR, addr.Bytes(), height, block.Hash.Bytes())
+		tokenKey, _ := database.EncodeKey(DBKP_TX_TOKEN, addr.Bytes(), block.TokenId.Bytes(), height, block.Hash.Bytes())
+		batch.Put(addrKey, []byte{direction})
+		batch.Put(tokenKey, []byte{direction})
+		keys = append(keys, addrKey, tokenKey)
+	}
+
+	for addr, blocks := range subLedger {
+		for _, block := range blocks {
+			if !block.IsSendBlock() {
+				continue
+			}
+			if block.ToAddress == addr {
+				put(addr, block, DirectionOut|DirectionIn)
+				continue
+			}
+			put(addr, block, DirectionOut)
+			put(block.ToAddress, block, DirectionIn)
+		}
+	}
+	return keys
+}
+
+// GetTransactions returns at most count transactions of addr before the cursor, from the newest to the oldest.
+// The cursor is the nextCursor of the previous page, nil means from the latest one; nextCursor is nil on the last page.
+func (idx *Index) GetTransactions(addr types.Address, filter *TxFilter, cursor []byte, count int) ([]*TxRecord, []byte, error) {
+	if !idx.openTxIndex {
+		return nil, nil, ErrTxIndexNotOpen
+	}
+
+	var prefix []byte
+	if filter.TokenId != nil {
+		prefix, _ = database.EncodeKey(DBKP_TX_TOKEN, addr.Bytes(), filter.TokenId.Bytes())
+	} else {
+		prefix, _ = database.EncodeKey(DBKP_TX_ADDR, addr.Bytes())
+	}
+	iterRange := util.BytesPrefix(prefix)
+	fromHeight, toHeight, err := idx.heightRangeByTime(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fromHeight > toHeight {
+		return make([]*TxRecord, 0), nil, nil
+	}
+	iterRange.Start, _ = database.EncodeKey(prefix[0], prefix[1:], fromHeight)
+	if toHeight < math.MaxUint64 {
+		iterRange.Limit, _ = database.EncodeKey(prefix[0], prefix[1:], toHeight+1)
+	}
+	if len(cursor) > 0 {
+		limitKey, _ := database.EncodeKey(prefix[0], prefix[1:], cursor)
+		if bytes.Compare(limitKey, iterRange.Limit) < 0 {
+			iterRange.Limit = limitKey
+		}
+	}
+
+	iter := idx.db.NewIterator(iterRange, nil)
+	defer iter.Release()
+
+	records := make([]*TxRecord, 0)
+	var nextCursor []byte
+	hasMore := false
+	for ok := iter.Last(); ok; ok = iter.Prev() {
+		key, value := iter.Key(), iter.Value()
+		if len(value) <= 0 || len(key) < len(prefix)+8+types.HashSize {
+			continue
+		}
+		direction := value[0]
+		if filter.Direction != 0 && direction&filter.Direction == 0 {
+			continue
+		}
+
+		if len(records) >= count {
+			hasMore = true
+			break
+		}
+		nextCursor = append([]byte(nil), key[len(prefix):]...)
+
+		refPart := key[len(prefix):]
+		blockHash, err := types.BytesToHash(refPart[8:])
+		if err != nil {
+			return nil, nil, err
+		}
+		record, err := idx.getTxRecord(&blockHash, direction)
+		if err != nil {
+			return nil, nil, err
+		}
+		if record == nil || !matchTime(record.SendBlock, filter) {
+			continue
+		}
+		record.SnapshotHeight = binary.BigEndian.Uint64(refPart[:8])
+		records = append(records, record)
+	}
+	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+		return nil, nil, err
+	}
+
+	if !hasMore {
+		nextCursor = nil
+	}
+	return records, nextCursor, nil
+}
+
+func (idx *Index) getTxRecord(sendBlockHash *types.Hash, direction byte) (*TxRecord, error) {
+	sendBlock, err := idx.chain.GetAccountBlockByHash(sendBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	if sendBlock == nil {
+		return nil, nil
+	}
+
+	record := &TxRecord{
+		SendBlock:     sendBlock,
+		ReceiveBlocks: make([]*ledger.AccountBlock, 0),
+		Direction:     direction,
+	}
+
+	meta, err := idx.chain.GetAccountBlockMetaByHash(sendBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		return record, nil
+	}
+	for _, height := range meta.ReceiveBlockHeights {
+		receiveBlock, err := idx.chain.GetAccountBlockByHeight(&sendBlock.ToAddress, height)
+		if err != nil {
+			return nil, err
+		}
+		if receiveBlock != nil {
+			record.ReceiveBlocks = append(record.ReceiveBlocks, receiveBlock)
+		}
+	}
+	return record, nil
+}
+
+// heightRangeByTime finds the snapshot heights which may confirm the send blocks between the time bounds of the filter
+func (idx *Index) heightRangeByTime(filter *TxFilter) (uint64, uint64, error) {
+	fromHeight, toHeight := uint64(0), uint64(math.MaxUint64)
+	if filter.FromTime != 0 {
+		fromTime := time.Unix(filter.FromTime, 0).Add(-txTimeSlack)
+		block, err := idx.chain.GetSnapshotBlockBeforeTime(&fromTime)
+		if err != nil {
+			return 0, 0, err
+		}
+		if block != nil {
+			fromHeight = block.Height + 1
+		}
+	}
+	if filter.ToTime != 0 {
+		toTime := time.Unix(filter.ToTime, 0).Add(txTimeSlack)
+		block, err := idx.chain.GetSnapshotBlockBeforeTime(&toTime)
+		if err != nil {
+			return 0, 0, err
+		}
+		if block == nil {
+			return 1, 0, nil
+		}
+		toHeight = block.Height
+	}
+	return fromHeight, toHeight, nil
+}
+
+func matchTime(block *ledger.AccountBlock, filter *TxFilter) bool {
+	if filter.FromTime == 0 && filter.ToTime == 0 {
+		return true
+	}
+	if block.Timestamp == nil {
+		return false
+	}
+	timestamp := block.Timestamp.Unix()
+	return (filter.FromTime == 0 || timestamp >= filter.FromTime) &&
+		(filter.ToTime == 0 || timestamp <= filter.ToTime)
+}
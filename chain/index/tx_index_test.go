@@ -0,0 +1,101 @@
+package index
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+func TestTxIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tx_index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	addr1, _, _ := types.CreateAddress()
+	addr2, _, _ := types.CreateAddress()
+	tokenA := types.CreateTokenTypeId([]byte("A"))
+	tokenB := types.CreateTokenTypeId([]byte("B"))
+
+	chain := newMockChain()
+	for height := uint64(1); height <= 4; height++ {
+		// the snapshot blocks are far enough apart for the time bounds to narrow the scan
+		timestamp := time.Unix(int64(height*1000), 0)
+		chain.snapshotTimes[height] = timestamp
+		tokenId := tokenA
+		if height%2 == 0 {
+			tokenId = tokenB
+		}
+		sendBlock := &ledger.AccountBlock{
+			BlockType:      ledger.BlockTypeSendCall,
+			AccountAddress: addr1,
+			ToAddress:      addr2,
+			TokenId:        tokenId,
+			Height:         height,
+			Timestamp:      &timestamp,
+			Hash:           types.DataHash([]byte{1, byte(height)}),
+		}
+		chain.addBlock(height, sendBlock, nil)
+		if height < 4 {
+			receiveBlock := &ledger.AccountBlock{
+				BlockType:      ledger.BlockTypeReceive,
+				AccountAddress: addr2,
+				FromBlockHash:  sendBlock.Hash,
+				Height:         height,
+				Hash:           types.DataHash([]byte{2, byte(height)}),
+			}
+			chain.addBlock(height, receiveBlock, nil)
+			chain.metas[sendBlock.Hash] = &ledger.AccountBlockMeta{ReceiveBlockHeights: []uint64{height}}
+		}
+	}
+
+	idx, err := NewIndex(chain, dir, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	if err := idx.sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, cursor, err := idx.GetTransactions(addr1, &TxFilter{}, nil, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || cursor == nil || records[0].SnapshotHeight != 4 || records[0].Direction != DirectionOut {
+		t.Fatalf("unexpected first page %v", records)
+	}
+	if len(records[0].ReceiveBlocks) != 0 || len(records[1].ReceiveBlocks) != 1 || records[1].ReceiveBlocks[0].Hash != types.DataHash([]byte{2, 3}) {
+		t.Fatalf("unexpected receive blocks of the first page")
+	}
+	records, cursor, _ = idx.GetTransactions(addr1, &TxFilter{}, cursor, 3)
+	if len(records) != 1 || cursor != nil || records[0].SnapshotHeight != 1 {
+		t.Fatalf("unexpected last page %v", records)
+	}
+
+	records, _, _ = idx.GetTransactions(addr2, &TxFilter{TokenId: &tokenB, Direction: DirectionIn}, nil, 10)
+	if len(records) != 2 || records[0].SnapshotHeight != 4 || records[1].SnapshotHeight != 2 {
+		t.Fatalf("unexpected records of token B %v", records)
+	}
+	if records, _, _ = idx.GetTransactions(addr2, &TxFilter{Direction: DirectionOut}, nil, 10); len(records) != 0 {
+		t.Fatalf("expected no out records of addr2, got %v", len(records))
+	}
+	if records, _, _ = idx.GetTransactions(addr1, &TxFilter{FromTime: 2000, ToTime: 3000}, nil, 10); len(records) != 2 {
+		t.Fatalf("expected 2 records between time 2000 and 3000, got %v", len(records))
+	}
+	if fromHeight, toHeight, _ := idx.heightRangeByTime(&TxFilter{FromTime: 2000, ToTime: 3000}); fromHeight != 2 || toHeight != 3 {
+		t.Fatalf("unexpected height range %v-%v", fromHeight, toHeight)
+	}
+	if records, _, _ = idx.GetTransactions(addr1, &TxFilter{ToTime: 100}, nil, 10); len(records) != 0 {
+		t.Fatalf("expected no records before the first snapshot block, got %v", len(records))
+	}
+
+	if _, _, err := idx.GetLogs(&LogFilter{Addresses: []types.Address{addr1}}, nil, 10); err != ErrLogIndexNotOpen {
+		t.Fatalf("expected ErrLogIndexNotOpen, got %v", err)
+	}
+}
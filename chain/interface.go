@@ -1,10 +1,12 @@
 package chain
 
 import (
+	"io"
 	"math/big"
 	"time"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vitelabs/go-vite/chain/index"
 	"github.com/vitelabs/go-vite/chain/sender"
 	"github.com/vitelabs/go-vite/chain/trie_gc"
 	"github.com/vitelabs/go-vite/chain_db"
@@ -43,6 +45,9 @@ type Chain interface {
 	Init()
 	Compressor() *compress.Compressor
 	TrieGc() trie_gc.Collector
+	PrunedHeight() uint64
+	ExportState(height uint64, w io.Writer) error
+	ImportState(r io.Reader, trustedHeight uint64, trustedHash types.Hash) (*ledger.SnapshotBlock, error)
 
 	StopSaveTrie()
 	StartSaveTrie()
@@ -95,6 +100,8 @@ type Chain interface {
 	GetSubLedgerByHash(startBlockHash *types.Hash, count uint64, forward bool) ([]*ledger.CompressedFileMeta, [][2]uint64, error)
 	GetConfirmSubLedger(fromHeight uint64, toHeight uint64) ([]*ledger.SnapshotBlock, map[types.Address][]*ledger.AccountBlock, error)
 	GetVmLogList(logListHash *types.Hash) (ledger.VmLogList, error)
+	GetLogs(filter *index.LogFilter, cursor []byte, count int) ([]*index.LogRecord, []byte, error)
+	GetTransactions(addr types.Address, filter *index.TxFilter, cursor []byte, count int) ([]*index.TxRecord, []byte, error)
 	UnRegister(listenerId uint64)
 	TrieDb() *leveldb.DB
 	CleanTrieNodePool()
@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"github.com/vitelabs/go-vite/chain/index"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
 )
@@ -14,3 +15,29 @@ func (c *chain) GetVmLogList(logListHash *types.Hash) (ledger.VmLogList, error)
 
 	return vmLogList, nil
 }
+
+func (c *chain) GetLogs(filter *index.LogFilter, cursor []byte, count int) ([]*index.LogRecord, []byte, error) {
+	if c.index == nil {
+		return nil, nil, index.ErrLogIndexNotOpen
+	}
+
+	records, nextCursor, err := c.index.GetLogs(filter, cursor, count)
+	if err != nil {
+		c.log.Error("GetLogs failed, error is "+err.Error(), "method", "GetLogs")
+		return nil, nil, err
+	}
+	return records, nextCursor, nil
+}
+
+func (c *chain) GetTransactions(addr types.Address, filter *index.TxFilter, cursor []byte, count int) ([]*index.TxRecord, []byte, error) {
+	if c.index == nil {
+		return nil, nil, index.ErrTxIndexNotOpen
+	}
+
+	records, nextCursor, err := c.index.GetTransactions(addr, filter, cursor, count)
+	if err != nil {
+		c.log.Error("GetTransactions failed, error is "+err.Error(), "method", "GetTransactions")
+		return nil, nil, err
+	}
+	return records, nextCursor, nil
+}
@@ -0,0 +1,48 @@
+package chain
+
+import (
+	"github.com/vitelabs/go-vite/chain/pruner"
+	"github.com/vitelabs/go-vite/common/types"
+)
+
+// pruneRetain keeps the blocks which are needed by the trie gc and the account limit of snapshot height.
+func (c *chain) pruneRetain() uint64 {
+	gcRetain := c.cfg.LedgerGcRetain
+	if gcRetain <= 0 {
+		gcRetain = 86400
+	}
+
+	minRetain := gcRetain + types.AccountLimitSnapshotHeight
+	if c.cfg.LedgerPruneRetain < minRetain {
+		c.log.Warn("LedgerPruneRetain is too small", "LedgerPruneRetain", c.cfg.LedgerPruneRetain, "minRetain", minRetain)
+		return minRetain
+	}
+	return c.cfg.LedgerPruneRetain
+}
+
+// prunableHeight is the highest snapshot height which is saved to the ledger files and indexed.
+func (c *chain) prunableHeight() uint64 {
+	height := c.compressor.Indexer().LatestHeight()
+	if c.index != nil {
+		indexedHeight, err := c.index.IndexedHeight()
+		if err != nil {
+			c.log.Error("IndexedHeight failed, error is "+err.Error(), "method", "prunableHeight")
+			return 0
+		}
+		if indexedHeight < height {
+			height = indexedHeight
+		}
+	}
+	return height
+}
+
+// PrunedHeight returns the height of the snapshot block below or at which the ledger is pruned,
+// 0 means the ledger is not pruned.
+func (c *chain) PrunedHeight() uint64 {
+	height, err := pruner.ReadPrunedHeight(c.chainDb.Db())
+	if err != nil {
+		c.log.Error("PrunedHeight failed, error is "+err.Error(), "method", "PrunedHeight")
+		return 0
+	}
+	return height
+}
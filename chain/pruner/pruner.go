@@ -0,0 +1,297 @@
+package pruner
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/log15"
+)
+
+// the batch is committed when it has more than maxBatchLen records
+const maxBatchLen = 10000
+
+// Pruner drops the account blocks, account block metas, vm log lists and snapshot contents which are confirmed
+// more than retainSnapshotHeight snapshot blocks ago. Snapshot block headers and state tries are kept, the ledger
+// files of the compressor remain the archival source of the pruned blocks.
+//
+// The first and the latest block of each account, the send create blocks and the send blocks whose receive blocks
+// are not pruned are kept, so the pruned node is still able to produce and verify new blocks.
+type Pruner struct {
+	chain Chain
+
+	retainSnapshotHeight uint64
+	// the pruner never goes beyond safeHeight, such as the height of the compressed ledger files
+	safeHeight    func() uint64
+	checkInterval time.Duration
+	// the chain write lock, it's held while a batch is built and committed, so no block is inserted or deleted
+	// between reading the metas and deleting the blocks
+	writeLock sync.Locker
+
+	terminal chan struct{}
+	wg       sync.WaitGroup
+
+	log log15.Logger
+}
+
+func NewPruner(chain Chain, retainSnapshotHeight uint64, safeHeight func() uint64, writeLock sync.Locker) *Pruner {
+	return &Pruner{
+		chain:                chain,
+		retainSnapshotHeight: retainSnapshotHeight,
+		safeHeight:           safeHeight,
+		checkInterval:        10 * time.Minute,
+		writeLock:            writeLock,
+		log:                  log15.New("module", "pruner"),
+	}
+}
+
+func (p *Pruner) Start() {
+	p.terminal = make(chan struct{})
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.checkInterval)
+		defer ticker.Stop()
+		for {
+			if err := p.Prune(p.terminal); err != nil {
+				p.log.Error("Prune failed, error is "+err.Error(), "method", "Start")
+			}
+
+			select {
+			case <-ticker.C:
+			case <-p.terminal:
+				return
+			}
+		}
+	}()
+}
+
+func (p *Pruner) Stop() {
+	close(p.terminal)
+	p.wg.Wait()
+}
+
+// PrunedHeight returns the height of the snapshot block below or at which the ledger is pruned.
+func (p *Pruner) PrunedHeight() (uint64, error) {
+	return ReadPrunedHeight(p.chain.ChainDb().Db())
+}
+
+// ReadPrunedHeight reads the pruned height from the db, 0 means the ledger is not pruned.
+func ReadPrunedHeight(db *leveldb.DB) (uint64, error) {
+	key, _ := database.EncodeKey(database.DBKP_PRUNED_HEIGHT)
+	value, err := db.Get(key, nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+// WritePrunedHeight marks the ledger below or at height as pruned, it's also used when the chain is bootstrapped from a state snapshot.
+func WritePrunedHeight(batch *leveldb.Batch, height uint64) {
+	key, _ := database.EncodeKey(database.DBKP_PRUNED_HEIGHT)
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, height)
+	batch.Put(key, value)
+}
+
+func (p *Pruner) targetHeight() uint64 {
+	latestHeight := p.chain.GetLatestSnapshotBlock().Height
+	if latestHeight <= p.retainSnapshotHeight {
+		return 0
+	}
+
+	targetHeight := latestHeight - p.retainSnapshotHeight
+	if safeHeight := p.safeHeight(); safeHeight < targetHeight {
+		targetHeight = safeHeight
+	}
+	return targetHeight
+}
+
+// Prune prunes the ledger to the target height, it returns early without error when terminal is closed.
+func (p *Pruner) Prune(terminal <-chan struct{}) error {
+	prunedHeight, err := p.PrunedHeight()
+	if err != nil {
+		return err
+	}
+	targetHeight := p.targetHeight()
+	if targetHeight <= prunedHeight {
+		return nil
+	}
+
+	chainDb := p.chain.ChainDb()
+	lastAccountId, err := chainDb.Account.GetLastAccountId()
+	if err != nil {
+		return err
+	}
+
+	// the pruned height is raised first, so the historical queries are refused before the blocks are deleted,
+	// an interrupted pruning is finished by the next one which scans all the accounts again
+	batch := new(leveldb.Batch)
+	// the genesis snapshot blocks are kept complete for the data check of the chain
+	fromHeight := prunedHeight + 1
+	if fromHeight < 3 {
+		fromHeight = 3
+	}
+	for height := fromHeight; height <= targetHeight; height++ {
+		chainDb.Sc.DeleteSnapshotContent(batch, height)
+	}
+	WritePrunedHeight(batch, targetHeight)
+	if err := p.commit(batch); err != nil {
+		return err
+	}
+
+	for accountId := uint64(1); accountId <= lastAccountId; {
+		select {
+		case <-terminal:
+			return nil
+		default:
+		}
+
+		// the lock is released between the batches, so the inserts are not blocked for the whole pruning
+		p.writeLock.Lock()
+		for ; accountId <= lastAccountId && batch.Len() <= maxBatchLen; accountId++ {
+			if err := p.pruneAccount(batch, accountId, targetHeight); err != nil {
+				p.writeLock.Unlock()
+				return err
+			}
+		}
+		err := chainDb.Commit(batch)
+		p.writeLock.Unlock()
+		if err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+
+	p.log.Info("pruned", "fromHeight", prunedHeight+1, "toHeight", targetHeight)
+	return nil
+}
+
+func (p *Pruner) commit(batch *leveldb.Batch) error {
+	p.writeLock.Lock()
+	defer p.writeLock.Unlock()
+
+	if err := p.chain.ChainDb().Commit(batch); err != nil {
+		return err
+	}
+	batch.Reset()
+	return nil
+}
+
+func (p *Pruner) pruneAccount(batch *leveldb.Batch, accountId uint64, targetHeight uint64) error {
+	ac := p.chain.ChainDb().Ac
+
+	prefix, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId)
+	iter := p.chain.ChainDb().Db().NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+
+	if !iter.Last() {
+		return iter.Error()
+	}
+	// the key is prefix, account id, height and hash
+	latestHeight := binary.BigEndian.Uint64(iter.Key()[9:17])
+
+	for ok := iter.First(); ok; ok = iter.Next() {
+		key := iter.Key()
+		height := binary.BigEndian.Uint64(key[9:17])
+		if height >= latestHeight {
+			break
+		}
+		if height <= 1 {
+			continue
+		}
+
+		hash, err := types.BytesToHash(key[17:])
+		if err != nil {
+			return err
+		}
+		meta, err := ac.GetBlockMeta(&hash)
+		if err != nil {
+			return err
+		}
+		if meta == nil {
+			continue
+		}
+		// blocks are confirmed in the order of height
+		if meta.SnapshotHeight <= 0 || meta.SnapshotHeight > targetHeight {
+			break
+		}
+
+		block := &ledger.AccountBlock{}
+		if err := block.DbDeserialize(iter.Value()); err != nil {
+			return err
+		}
+		keep, err := p.needKeep(block, meta, targetHeight)
+		if err != nil {
+			return err
+		}
+		if keep {
+			continue
+		}
+
+		batch.Delete(append([]byte(nil), key...))
+		ac.DeleteBlockMeta(batch, &hash)
+		if block.LogHash != nil {
+			ac.DeleteVmLogList(batch, block.LogHash)
+		}
+	}
+
+	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+func (p *Pruner) needKeep(block *ledger.AccountBlock, meta *ledger.AccountBlockMeta, targetHeight uint64) (bool, error) {
+	if !block.IsSendBlock() {
+		return false, nil
+	}
+	// the gid of a contract is read from its send create block
+	if block.BlockType == ledger.BlockTypeSendCreate {
+		return true, nil
+	}
+	// the send block is needed until its receive blocks can't be rolled back
+	if len(meta.ReceiveBlockHeights) <= 0 {
+		return true, nil
+	}
+
+	chainDb := p.chain.ChainDb()
+	toAccount, err := chainDb.Account.GetAccountByAddress(&block.ToAddress)
+	if err != nil {
+		return false, err
+	}
+	if toAccount == nil {
+		return true, nil
+	}
+	for _, height := range meta.ReceiveBlockHeights {
+		receiveHash, err := chainDb.Ac.GetHashByHeight(toAccount.AccountId, height)
+		if err != nil {
+			return false, err
+		}
+		// the receive block has been pruned
+		if receiveHash == nil {
+			continue
+		}
+		receiveMeta, err := chainDb.Ac.GetBlockMeta(receiveHash)
+		if err != nil {
+			return false, err
+		}
+		if receiveMeta == nil {
+			continue
+		}
+		if receiveMeta.SnapshotHeight <= 0 || receiveMeta.SnapshotHeight > targetHeight {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,163 @@
+package pruner
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vitelabs/go-vite/chain_db"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+type mockChain struct {
+	chainDb      *chain_db.ChainDb
+	latestHeight uint64
+}
+
+func (c *mockChain) GetLatestSnapshotBlock() *ledger.SnapshotBlock {
+	return &ledger.SnapshotBlock{Height: c.latestHeight}
+}
+
+func (c *mockChain) ChainDb() *chain_db.ChainDb {
+	return c.chainDb
+}
+
+var now = time.Now()
+
+func writeBlock(t *testing.T, batch *leveldb.Batch, chainDb *chain_db.ChainDb, accountId uint64, block *ledger.AccountBlock, meta *ledger.AccountBlockMeta) {
+	block.Hash = types.DataHash([]byte{byte(accountId), byte(block.Height)})
+	block.Amount = big.NewInt(0)
+	block.Timestamp = &now
+	meta.AccountId = accountId
+	meta.Height = block.Height
+	if err := chainDb.Ac.WriteBlock(batch, accountId, block); err != nil {
+		t.Fatal(err)
+	}
+	if err := chainDb.Ac.WriteBlockMeta(batch, &block.Hash, meta); err != nil {
+		t.Fatal(err)
+	}
+	if meta.SnapshotHeight > 0 {
+		chainDb.Ac.WriteBeSnapshot(batch, &block.Hash, meta.SnapshotHeight)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pruner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	chainDb := chain_db.NewChainDb(dir)
+	if chainDb == nil {
+		t.Fatal("NewChainDb failed")
+	}
+	defer chainDb.Db().Close()
+
+	addrA, _, _ := types.CreateAddress()
+	addrB, _, _ := types.CreateAddress()
+	batch := new(leveldb.Batch)
+	for id, addr := range []types.Address{addrA, addrB} {
+		chainDb.Account.WriteAccountIndex(batch, uint64(id+1), &addr)
+		if err := chainDb.Account.WriteAccount(batch, &ledger.Account{AccountAddress: addr, AccountId: uint64(id + 1)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logList := ledger.VmLogList{{Data: []byte("log")}}
+	if err := chainDb.Ac.WriteVmLogList(batch, logList); err != nil {
+		t.Fatal(err)
+	}
+
+	send := func(height uint64, snapshotHeight uint64, receiveHeights ...uint64) {
+		writeBlock(t, batch, chainDb, 1, &ledger.AccountBlock{
+			BlockType:      ledger.BlockTypeSendCall,
+			AccountAddress: addrA,
+			ToAddress:      addrB,
+			Height:         height,
+		}, &ledger.AccountBlockMeta{SnapshotHeight: snapshotHeight, ReceiveBlockHeights: receiveHeights})
+	}
+	receive := func(height uint64, snapshotHeight uint64, logHash *types.Hash) {
+		writeBlock(t, batch, chainDb, 2, &ledger.AccountBlock{
+			BlockType:      ledger.BlockTypeReceive,
+			AccountAddress: addrB,
+			Height:         height,
+			LogHash:        logHash,
+		}, &ledger.AccountBlockMeta{SnapshotHeight: snapshotHeight})
+	}
+	send(1, 1, 1)
+	send(2, 2, 2)
+	send(3, 3, 3)
+	send(4, 4)
+	send(5, 5)
+	receive(1, 1, nil)
+	receive(2, 2, logList.Hash())
+	receive(3, 5, nil)
+	for height := uint64(1); height <= 6; height++ {
+		if err := chainDb.Sc.WriteSnapshotContent(batch, height, ledger.SnapshotContent{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := chainDb.Commit(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	safeHeight := uint64(3)
+	p := NewPruner(&mockChain{chainDb: chainDb, latestHeight: 6}, 2, func() uint64 { return safeHeight }, &sync.Mutex{})
+	if err := p.Prune(nil); err != nil {
+		t.Fatal(err)
+	}
+	if prunedHeight, _ := p.PrunedHeight(); prunedHeight != 3 {
+		t.Fatalf("expected pruned height 3 limited by the safe height, got %v", prunedHeight)
+	}
+
+	safeHeight = 10
+	if err := p.Prune(nil); err != nil {
+		t.Fatal(err)
+	}
+	if prunedHeight, _ := p.PrunedHeight(); prunedHeight != 4 {
+		t.Fatalf("expected pruned height 4, got %v", prunedHeight)
+	}
+
+	exist := func(accountId uint64, height uint64) bool {
+		hash := types.DataHash([]byte{byte(accountId), byte(height)})
+		block, err := chainDb.Ac.GetBlock(&hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return block != nil
+	}
+	for _, c := range []struct {
+		accountId uint64
+		height    uint64
+		exist     bool
+	}{
+		{1, 1, true},  // the first block
+		{1, 2, false}, // received and confirmed
+		{1, 3, true},  // the receive block is confirmed after the target height
+		{1, 4, true},  // on road
+		{1, 5, true},  // the latest block
+		{2, 1, true},
+		{2, 2, false},
+		{2, 3, true},
+	} {
+		if exist(c.accountId, c.height) != c.exist {
+			t.Fatalf("expected existence of block %v of account %v is %v", c.height, c.accountId, c.exist)
+		}
+	}
+
+	if list, _ := chainDb.Ac.GetVmLogList(logList.Hash()); list != nil {
+		t.Fatal("expected the log list is pruned")
+	}
+	for height := uint64(1); height <= 6; height++ {
+		content, _ := chainDb.Sc.GetSnapshotContent(height)
+		if (content != nil) != (height < 3 || height > 4) {
+			t.Fatalf("unexpected snapshot content of height %v", height)
+		}
+	}
+}
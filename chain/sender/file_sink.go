@@ -0,0 +1,52 @@
+package sender
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// fileSink appends every message as one line of an ndjson file.
+type fileSink struct {
+	fileName string
+	file     *os.File
+}
+
+func newFileSink(fileName string) *fileSink {
+	return &fileSink{
+		fileName: fileName,
+	}
+}
+
+func (sink *fileSink) Open() error {
+	if err := os.MkdirAll(filepath.Dir(sink.fileName), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(sink.fileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	sink.file = file
+	return nil
+}
+
+func (sink *fileSink) Send(msgList [][]byte) error {
+	var buf []byte
+	for _, msg := range msgList {
+		buf = append(buf, msg...)
+		buf = append(buf, '\n')
+	}
+
+	if _, err := sink.file.Write(buf); err != nil {
+		return err
+	}
+	return sink.file.Sync()
+}
+
+func (sink *fileSink) Close() error {
+	if err := sink.file.Close(); err != nil {
+		return err
+	}
+	sink.file = nil
+	return nil
+}
@@ -79,6 +79,31 @@ func (sender *KafkaSender) Start(brokerList []string, topic string) error {
 	return nil
 }
 
+func (sender *KafkaSender) StartSink(sinkType string, target string) error {
+	sender.lock.Lock()
+	defer sender.lock.Unlock()
+
+	producer, err := sender.getSinkProducer(sinkType, target)
+	if err != nil {
+		return err
+	}
+
+	for _, runProducer := range sender.runProducers {
+		if runProducer.IsSameSink(sinkType, target) {
+			// has run
+			return nil
+		}
+	}
+
+	if startErr := producer.Start(); startErr != nil {
+		return startErr
+	}
+
+	sender.runProducers = append(sender.runProducers, producer)
+
+	return nil
+}
+
 func (sender *KafkaSender) StopById(producerId uint8) {
 	sender.lock.Lock()
 	defer sender.lock.Unlock()
@@ -151,6 +176,30 @@ func (sender *KafkaSender) getProducer(brokerList []string, topic string) (*Prod
 	return newProducer, nil
 }
 
+func (sender *KafkaSender) getSinkProducer(sinkType string, target string) (*Producer, error) {
+	if err := checkSinkType(sinkType); err != nil {
+		return nil, err
+	}
+
+	for _, producer := range sender.producers {
+		if producer.IsSameSink(sinkType, target) {
+			return producer, nil
+		}
+	}
+
+	newProducer, newErr := NewSinkProducer(byte(len(sender.producers)+1), sinkType, target, sender.chain, sender.db)
+	if newErr != nil {
+		return nil, newErr
+	}
+
+	if writeErr := sender.writeProducerToDb(newProducer); writeErr != nil {
+		return nil, writeErr
+	}
+
+	sender.producers = append(sender.producers, newProducer)
+	return newProducer, nil
+}
+
 func (sender *KafkaSender) writeProducerToDb(producer *Producer) error {
 	key := append([]byte{DBKP_PRODUCER}, producer.producerId)
 	buf, sErr := producer.Serialize()
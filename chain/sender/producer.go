@@ -8,7 +8,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/Shopify/sarama"
 	"github.com/golang/protobuf/proto"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/vitelabs/go-vite/common"
@@ -59,9 +58,15 @@ type Producer struct {
 	producerId uint8
 	db         *leveldb.DB
 
+	sinkType string
+
+	// kafka sink
 	brokerList []string
 	topic      string
 
+	// file and webhook sink
+	target string
+
 	hasSendLock      sync.RWMutex
 	hasSend          uint64
 	dbHasSend        uint64
@@ -75,11 +80,9 @@ type Producer struct {
 
 	wg sync.WaitGroup
 
-	kafkaProducer sarama.AsyncProducer
-	chain         Chain
-	concurrency   uint64
-
-	sendWg sync.WaitGroup
+	sink        Sink
+	chain       Chain
+	concurrency uint64
 }
 
 func NewProducerFromDb(producerId uint8, buf []byte, chain Chain, db *leveldb.DB) (*Producer, error) {
@@ -97,6 +100,7 @@ func NewProducerFromDb(producerId uint8, buf []byte, chain Chain, db *leveldb.DB
 
 func NewProducer(producerId uint8, brokerList []string, topic string, chain Chain, db *leveldb.DB) (*Producer, error) {
 	producer := &Producer{
+		sinkType:   SINK_KAFKA,
 		brokerList: brokerList,
 		topic:      topic,
 	}
@@ -108,6 +112,19 @@ func NewProducer(producerId uint8, brokerList []string, topic string, chain Chai
 	return producer, nil
 }
 
+func NewSinkProducer(producerId uint8, sinkType string, target string, chain Chain, db *leveldb.DB) (*Producer, error) {
+	producer := &Producer{
+		sinkType: sinkType,
+		target:   target,
+	}
+
+	if err := producer.init(producerId, chain, db); err != nil {
+		return nil, err
+	}
+	producer.log = log15.New("module", "sender/producer")
+	return producer, nil
+}
+
 func (producer *Producer) init(producerId uint8, chain Chain, db *leveldb.DB) error {
 	producer.producerId = producerId
 	producer.concurrency = 100
@@ -143,6 +160,14 @@ func (producer *Producer) ProducerId() uint8 {
 	return producer.producerId
 }
 
+func (producer *Producer) SinkType() string {
+	return producer.sinkType
+}
+
+func (producer *Producer) Target() string {
+	return producer.target
+}
+
 func (producer *Producer) BrokerList() []string {
 	return producer.brokerList
 }
@@ -159,8 +184,13 @@ func (producer *Producer) Status() int {
 	return producer.status
 }
 
+func (producer *Producer) IsSameSink(sinkType string, target string) bool {
+	return producer.sinkType == sinkType && producer.target == target
+}
+
 func (producer *Producer) IsSame(brokerList []string, topic string) bool {
-	if producer.topic != topic ||
+	if producer.sinkType != SINK_KAFKA ||
+		producer.topic != topic ||
 		len(brokerList) != len(producer.brokerList) {
 		return false
 	}
@@ -191,8 +221,14 @@ func (producer *Producer) Deserialize(buffer []byte) error {
 		return err
 	}
 
+	// producers saved before sinks were introduced are all kafka producers
+	producer.sinkType = pb.SinkType
+	if producer.sinkType == "" {
+		producer.sinkType = SINK_KAFKA
+	}
 	producer.topic = pb.Topic
 	producer.brokerList = pb.BrokerList
+	producer.target = pb.Target
 	return nil
 }
 
@@ -200,6 +236,8 @@ func (producer *Producer) Serialize() ([]byte, error) {
 	pb := &vitepb.Producer{}
 	pb.BrokerList = producer.brokerList
 	pb.Topic = producer.topic
+	pb.SinkType = producer.sinkType
+	pb.Target = producer.target
 
 	return proto.Marshal(pb)
 }
@@ -211,16 +249,15 @@ func (producer *Producer) Start() error {
 		return nil
 	}
 
-	config := sarama.NewConfig()
-	config.Producer.Return.Successes = true
-	config.Producer.Return.Errors = true
-
-	kafkaProducer, err := sarama.NewAsyncProducer(producer.brokerList, config)
+	sink, err := newSink(producer)
 	if err != nil {
 		return err
 	}
+	if err := sink.Open(); err != nil {
+		return err
+	}
 
-	producer.kafkaProducer = kafkaProducer
+	producer.sink = sink
 	producer.status = RUNNING
 	producer.termination = make(chan int)
 
@@ -234,18 +271,18 @@ func (producer *Producer) Start() error {
 				closeCount := 0
 
 				for ; closeCount < tryCloseCount; closeCount++ {
-					closeErr := producer.kafkaProducer.Close()
+					closeErr := producer.sink.Close()
 
 					if closeErr != nil {
-						producer.log.Error("kafkaProducer close failed, error is "+closeErr.Error(), "method", "Start")
+						producer.log.Error("sink close failed, error is "+closeErr.Error(), "method", "Start")
 					} else {
-						producer.kafkaProducer = nil
+						producer.sink = nil
 						return
 					}
 				}
 
 				if closeCount == tryCloseCount {
-					producer.log.Crit("kafkaProducer close failed", "method", "Start")
+					producer.log.Crit("sink close failed", "method", "Start")
 				}
 			default:
 				producer.send()
@@ -528,32 +565,14 @@ func (producer *Producer) getHasSend() (uint64, error) {
 	return binary.BigEndian.Uint64(value), nil
 }
 
-func (producer *Producer) sendMessage(msgList []*message) (err error) {
-	//var err error
-	for i := 0; i < len(msgList); i++ {
-		buf, jsonErr := json.Marshal(msgList[i])
+func (producer *Producer) sendMessage(msgList []*message) error {
+	bufList := make([][]byte, 0, len(msgList))
+	for _, msg := range msgList {
+		buf, jsonErr := json.Marshal(msg)
 		if jsonErr != nil {
 			return jsonErr
 		}
-		sMsg := &sarama.ProducerMessage{Topic: producer.topic, Value: sarama.StringEncoder(buf)}
-
-		producer.sendWg.Add(1)
-		// Simple implementation, may be fix
-		go func() {
-			defer producer.sendWg.Done()
-			producer.kafkaProducer.Input() <- sMsg
-			select {
-			// success
-			case <-producer.kafkaProducer.Successes():
-				break
-
-			// error
-			case sendError := <-producer.kafkaProducer.Errors():
-				producer.log.Error("kafka send failed, error is "+sendError.Error(), "method", "sendMessage")
-				err = sendError
-			}
-		}()
+		bufList = append(bufList, buf)
 	}
-	producer.sendWg.Wait()
-	return
+	return producer.sink.Send(bufList)
 }
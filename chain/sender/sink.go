@@ -0,0 +1,44 @@
+package sender
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	SINK_KAFKA   = "kafka"
+	SINK_FILE    = "file"
+	SINK_WEBHOOK = "webhook"
+)
+
+var ErrUnknownSinkType = errors.New("unknown sink type")
+
+// Sink is the destination of the chain event stream. Producer reads the block events,
+// encodes them as json messages and hands them to Send in event id order. Send must
+// return an error unless every message has been accepted by the destination, then the
+// producer will retry the same messages later.
+type Sink interface {
+	Open() error
+	Send(msgList [][]byte) error
+	Close() error
+}
+
+func checkSinkType(sinkType string) error {
+	switch sinkType {
+	case SINK_KAFKA, SINK_FILE, SINK_WEBHOOK:
+		return nil
+	}
+	return fmt.Errorf("%s: %s", ErrUnknownSinkType, sinkType)
+}
+
+func newSink(producer *Producer) (Sink, error) {
+	switch producer.sinkType {
+	case SINK_KAFKA:
+		return newKafkaSink(producer.brokerList, producer.topic, producer.log), nil
+	case SINK_FILE:
+		return newFileSink(producer.target), nil
+	case SINK_WEBHOOK:
+		return newWebhookSink(producer.target), nil
+	}
+	return nil, fmt.Errorf("%s: %s", ErrUnknownSinkType, producer.sinkType)
+}
@@ -0,0 +1,82 @@
+package sender
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file_sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "events", "chain.ndjson")
+	for i := 0; i < 2; i++ {
+		sink := newFileSink(fileName)
+		if err := sink.Open(); err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Send([][]byte{[]byte(`{"eventId":1}`), []byte(`{"eventId":2}`)}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(buf)), "\n"); len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d", len(lines))
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var received []message
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msgList []message
+		if err := json.NewDecoder(r.Body).Decode(&msgList); err != nil {
+			t.Error(err)
+		}
+		received = append(received, msgList...)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := newWebhookSink(server.URL)
+	if err := sink.Open(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Send([][]byte{[]byte(`{"eventId":1}`), []byte(`{"eventId":2}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 2 || received[1].EventId != 2 {
+		t.Fatalf("unexpected messages %v", received)
+	}
+
+	status = http.StatusInternalServerError
+	if err := sink.Send([][]byte{[]byte(`{"eventId":3}`)}); err == nil {
+		t.Fatal("expected an error when the webhook fails")
+	}
+}
+
+func TestGetSinkProducerUnknownType(t *testing.T) {
+	sender := &KafkaSender{}
+	if _, err := sender.getSinkProducer("ftp", "ftp://localhost"); err == nil {
+		t.Fatal("expected an error for an unknown sink type")
+	}
+	if len(sender.producers) != 0 {
+		t.Fatal("a producer of an unknown sink type is saved")
+	}
+}
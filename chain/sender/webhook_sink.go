@@ -0,0 +1,57 @@
+package sender
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// webhookSink posts every batch of messages to the url as a json array, the batch
+// is considered delivered only when the endpoint answers with a 2xx status.
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func newWebhookSink(url string) *webhookSink {
+	return &webhookSink{
+		url: url,
+	}
+}
+
+func (sink *webhookSink) Open() error {
+	sink.client = &http.Client{Timeout: webhookTimeout}
+	return nil
+}
+
+func (sink *webhookSink) Send(msgList [][]byte) error {
+	if len(msgList) <= 0 {
+		return nil
+	}
+
+	body := []byte{'['}
+	body = append(body, bytes.Join(msgList, []byte{','})...)
+	body = append(body, ']')
+
+	resp, err := sink.client.Post(sink.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded with status %d", sink.url, resp.StatusCode)
+	}
+	return nil
+}
+
+func (sink *webhookSink) Close() error {
+	sink.client = nil
+	return nil
+}
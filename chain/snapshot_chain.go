@@ -44,6 +44,8 @@ func (c *chain) GetNeedSnapshotContent() ledger.SnapshotContent {
 }
 
 func (c *chain) InsertSnapshotBlock(snapshotBlock *ledger.SnapshotBlock) error {
+	c.writeLock.RLock()
+	defer c.writeLock.RUnlock()
 
 	batch := new(leveldb.Batch)
 
@@ -417,6 +419,9 @@ func (c *chain) getNeedSnapshotMapByDeleteSubLedger(deleteSubLedger map[types.Ad
 
 // Contains to height
 func (c *chain) DeleteSnapshotBlocksToHeight(toHeight uint64) ([]*ledger.SnapshotBlock, map[types.Address][]*ledger.AccountBlock, error) {
+	c.writeLock.RLock()
+	defer c.writeLock.RUnlock()
+
 	if toHeight <= 0 || toHeight > c.GetLatestSnapshotBlock().Height {
 		return nil, nil, nil
 	}
@@ -0,0 +1,725 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vitelabs/go-vite/chain/pruner"
+	"github.com/vitelabs/go-vite/chain/statefile"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/trie"
+	"github.com/vitelabs/go-vite/vm/contracts"
+	"github.com/vitelabs/go-vite/vm/contracts/abi"
+	"github.com/vitelabs/go-vite/vm_context"
+)
+
+// importBatchLen is the number of records committed in one batch when importing the state snapshot
+const importBatchLen = 10000
+
+type stateExporter struct {
+	c      *chain
+	writer *statefile.Writer
+
+	snapshotHeight uint64
+
+	nodeSet         map[types.Hash]struct{}
+	accountSet      map[types.Address]struct{}
+	blockSet        map[types.Hash]struct{}
+	confirmedHeight map[types.Address]uint64
+}
+
+// ExportState writes the state of every account at the snapshot block of height to w,
+// the file contains the latest account blocks, the state tries, the onroad blocks and the token infos.
+func (c *chain) ExportState(height uint64, w io.Writer) error {
+	latestSnapshotBlock := c.GetLatestSnapshotBlock()
+	if latestSnapshotBlock == nil {
+		return errors.New("chain db is empty")
+	}
+	if height <= SecondSnapshotBlock.Height || height > latestSnapshotBlock.Height {
+		return errors.New(fmt.Sprintf("height %d is out of range (%d, %d]", height, SecondSnapshotBlock.Height, latestSnapshotBlock.Height))
+	}
+
+	snapshotBlock, err := c.GetSnapshotBlockByHeight(height)
+	if err != nil {
+		return err
+	}
+	if snapshotBlock == nil {
+		return errors.New(fmt.Sprintf("snapshot block %d is not exist", height))
+	}
+
+	writer, err := statefile.NewWriter(w)
+	if err != nil {
+		return err
+	}
+
+	exporter := &stateExporter{
+		c:               c,
+		writer:          writer,
+		snapshotHeight:  height,
+		nodeSet:         make(map[types.Hash]struct{}),
+		accountSet:      make(map[types.Address]struct{}),
+		blockSet:        make(map[types.Hash]struct{}),
+		confirmedHeight: make(map[types.Address]uint64),
+	}
+	if err := exporter.export(snapshotBlock); err != nil {
+		c.log.Error("export state failed, error is "+err.Error(), "method", "ExportState")
+		return err
+	}
+	return writer.Close()
+}
+
+func (e *stateExporter) export(snapshotBlock *ledger.SnapshotBlock) error {
+	buf, err := snapshotBlock.Serialize()
+	if err != nil {
+		return err
+	}
+	if err := e.writer.Write(statefile.RecordSnapshotBlock, buf); err != nil {
+		return err
+	}
+
+	stateTrie := e.c.GetStateTrie(&snapshotBlock.StateHash)
+	if stateTrie == nil || stateTrie.Root == nil {
+		return errors.New(fmt.Sprintf("state trie %s is missing", snapshotBlock.StateHash))
+	}
+	if err := e.writeTrie(stateTrie); err != nil {
+		return err
+	}
+
+	var addrList []types.Address
+	iter := stateTrie.NewIterator(nil)
+	for key, _, ok := iter.Next(); ok; key, _, ok = iter.Next() {
+		addr, err := types.BytesToAddress(key)
+		if err != nil {
+			return err
+		}
+		addrList = append(addrList, addr)
+	}
+
+	for _, addr := range addrList {
+		if err := e.writeAccountState(addr); err != nil {
+			return err
+		}
+	}
+
+	// after the latest blocks, so the flag of a send create block which is also the latest one is kept
+	for _, addr := range addrList {
+		if err := e.writeContractCreation(addr); err != nil {
+			return err
+		}
+	}
+
+	if err := e.writeOnRoad(); err != nil {
+		return err
+	}
+
+	gidAddrList, err := e.c.chainDb.OnRoad.GetAllGidAddrList()
+	if err != nil {
+		return err
+	}
+	for gid, data := range gidAddrList {
+		if err := e.writer.Write(statefile.RecordGidAddr, append(gid.Bytes(), data...)); err != nil {
+			return err
+		}
+	}
+
+	return e.writeTokenInfos(snapshotBlock)
+}
+
+// writeTrie writes the nodes of the trie which are not written yet, the tries of the accounts share a lot of nodes.
+func (e *stateExporter) writeTrie(t *trie.Trie) error {
+	if t == nil || t.Root == nil {
+		return nil
+	}
+
+	ni := t.NewNodeIterator()
+	for ni.Next(func(node *trie.TrieNode) bool {
+		_, ok := e.nodeSet[*node.Hash()]
+		return !ok
+	}) {
+		node := ni.Node()
+		nodeHash := node.Hash()
+		if _, ok := e.nodeSet[*nodeHash]; ok {
+			continue
+		}
+		e.nodeSet[*nodeHash] = struct{}{}
+
+		buf, err := node.DbSerialize()
+		if err != nil {
+			return err
+		}
+		if err := e.writer.Write(statefile.RecordTrieNode, buf); err != nil {
+			return err
+		}
+
+		if node.NodeType() == trie.TRIE_HASH_NODE {
+			value := t.LeafNodeValue(node)
+			if value == nil {
+				return errors.New(fmt.Sprintf("referenced value of trie node %s is missing", nodeHash))
+			}
+			if err := e.writer.Write(statefile.RecordRefValue, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *stateExporter) writeAccountState(addr types.Address) error {
+	block, err := e.c.GetConfirmAccountBlock(e.snapshotHeight, &addr)
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		return errors.New(fmt.Sprintf("confirmed account block of %s is not exist", addr))
+	}
+
+	if err := e.writeBlock(statefile.FlagLatest, block); err != nil {
+		return err
+	}
+
+	if block.StateHash != (types.Hash{}) {
+		return e.writeTrie(e.c.GetStateTrie(&block.StateHash))
+	}
+	return nil
+}
+
+// writeContractCreation writes the first block of the contract and the send create block of it,
+// the gid of a contract is read from them.
+func (e *stateExporter) writeContractCreation(addr types.Address) error {
+	if contracts.IsPrecompiledContractAddress(addr, e.snapshotHeight) {
+		return nil
+	}
+	firstBlock, err := e.c.GetAccountBlockByHeight(&addr, 1)
+	if err != nil {
+		return err
+	}
+	if firstBlock == nil || !firstBlock.IsReceiveBlock() {
+		return nil
+	}
+
+	sendBlock, err := e.c.GetAccountBlockByHash(&firstBlock.FromBlockHash)
+	if err != nil {
+		return err
+	}
+	if sendBlock == nil || sendBlock.BlockType != ledger.BlockTypeSendCreate {
+		return nil
+	}
+
+	if err := e.writeBlock(0, firstBlock); err != nil {
+		return err
+	}
+	return e.writeBlock(0, sendBlock)
+}
+
+func (e *stateExporter) writeBlock(flag byte, block *ledger.AccountBlock) error {
+	if _, ok := e.blockSet[block.Hash]; ok {
+		return nil
+	}
+	e.blockSet[block.Hash] = struct{}{}
+
+	if err := e.writeAccount(block.AccountAddress); err != nil {
+		return err
+	}
+
+	meta, err := e.c.chainDb.Ac.GetBlockMeta(&block.Hash)
+	if err != nil {
+		return err
+	}
+	if meta == nil {
+		return errors.New(fmt.Sprintf("meta of account block %s is not exist", block.Hash))
+	}
+
+	// Drop the receive blocks which are not confirmed at the snapshot height
+	meta = meta.Copy()
+	if block.IsSendBlock() && len(meta.ReceiveBlockHeights) > 0 {
+		receiverHeight, err := e.getConfirmedHeight(block.ToAddress)
+		if err != nil {
+			return err
+		}
+
+		var receiveBlockHeights []uint64
+		for _, receiveBlockHeight := range meta.ReceiveBlockHeights {
+			if receiveBlockHeight <= receiverHeight {
+				receiveBlockHeights = append(receiveBlockHeights, receiveBlockHeight)
+			}
+		}
+		meta.ReceiveBlockHeights = receiveBlockHeights
+	}
+
+	pb := block.Proto()
+	pb.StateHash = block.StateHash.Bytes()
+	blockBuf, err := proto.Marshal(pb)
+	if err != nil {
+		return err
+	}
+	metaBuf, err := meta.Serialize()
+	if err != nil {
+		return err
+	}
+
+	return e.writer.Write(statefile.RecordAccountBlock, statefile.EncodeAccountBlock(flag, meta.SnapshotHeight, blockBuf, metaBuf))
+}
+
+// writeAccount writes the account ahead of its first block, the importer assigns the account ids by them
+func (e *stateExporter) writeAccount(addr types.Address) error {
+	if _, ok := e.accountSet[addr]; ok {
+		return nil
+	}
+	e.accountSet[addr] = struct{}{}
+
+	account, err := e.c.GetAccount(&addr)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return errors.New(fmt.Sprintf("account %s is not exist", addr))
+	}
+	return e.writer.Write(statefile.RecordAccount, append(addr.Bytes(), account.PublicKey...))
+}
+
+// getConfirmedHeight returns the height of the latest account block of addr at the snapshot height
+func (e *stateExporter) getConfirmedHeight(addr types.Address) (uint64, error) {
+	if height, ok := e.confirmedHeight[addr]; ok {
+		return height, nil
+	}
+
+	account, err := e.c.chainDb.Account.GetAccountByAddress(&addr)
+	if err != nil {
+		return 0, err
+	}
+
+	height := uint64(0)
+	if account != nil {
+		block, err := e.c.chainDb.Ac.GetConfirmAccountBlock(e.snapshotHeight, account.AccountId)
+		if err != nil {
+			return 0, err
+		}
+		if block != nil {
+			height = block.Height
+		}
+	}
+
+	e.confirmedHeight[addr] = height
+	return height, nil
+}
+
+// writeOnRoad writes the send blocks which are confirmed but not received at the snapshot height
+func (e *stateExporter) writeOnRoad() error {
+	chainDb := e.c.chainDb
+
+	onRoadMap, err := chainDb.OnRoad.GetAllMeta()
+	if err != nil {
+		return err
+	}
+
+	isConfirmed := func(hash *types.Hash) (bool, error) {
+		confirmHeight, err := chainDb.Ac.GetConfirmHeight(hash)
+		if err != nil {
+			return false, err
+		}
+		return confirmHeight > 0 && confirmHeight <= e.snapshotHeight, nil
+	}
+
+	// The send blocks which are still onroad now
+	for addr, hashList := range onRoadMap {
+		var confirmedList []types.Hash
+		for _, hash := range hashList {
+			confirmed, err := isConfirmed(&hash)
+			if err != nil {
+				return err
+			}
+			if confirmed {
+				confirmedList = append(confirmedList, hash)
+			}
+		}
+		onRoadMap[addr] = confirmedList
+	}
+
+	// The send blocks which are received after the snapshot height
+	lastAccountId, err := chainDb.Account.GetLastAccountId()
+	if err != nil {
+		return err
+	}
+	for accountId := uint64(1); accountId <= lastAccountId; accountId++ {
+		addr, err := chainDb.Account.GetAddressById(accountId)
+		if err != nil {
+			return err
+		}
+		if addr == nil {
+			continue
+		}
+
+		latestBlock, err := chainDb.Ac.GetLatestBlock(accountId)
+		if err != nil {
+			return err
+		}
+		confirmedHeight, err := e.getConfirmedHeight(*addr)
+		if err != nil {
+			return err
+		}
+		if latestBlock == nil || latestBlock.Height <= confirmedHeight {
+			continue
+		}
+
+		blockList, err := chainDb.Ac.GetBlockListByAccountId(accountId, confirmedHeight+1, latestBlock.Height, true)
+		if err != nil {
+			return err
+		}
+		for _, block := range blockList {
+			if !block.IsReceiveBlock() {
+				continue
+			}
+			confirmed, err := isConfirmed(&block.FromBlockHash)
+			if err != nil {
+				return err
+			}
+			if confirmed {
+				onRoadMap[*addr] = append(onRoadMap[*addr], block.FromBlockHash)
+			}
+		}
+	}
+
+	for addr, hashList := range onRoadMap {
+		for _, hash := range hashList {
+			sendBlock, err := e.c.GetAccountBlockByHash(&hash)
+			if err != nil {
+				return err
+			}
+			if sendBlock == nil {
+				return errors.New(fmt.Sprintf("onroad send block %s is not exist", hash))
+			}
+			if err := e.writeBlock(statefile.FlagOnRoad, sendBlock); err != nil {
+				return err
+			}
+			if err := e.writer.Write(statefile.RecordOnRoad, append(addr.Bytes(), hash.Bytes()...)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *stateExporter) writeTokenInfos(snapshotBlock *ledger.SnapshotBlock) error {
+	vmContext, err := vm_context.NewVmContext(e.c, &snapshotBlock.Hash, nil, &types.AddressMintage)
+	if err != nil {
+		return err
+	}
+	tokenMap := abi.GetTokenMap(vmContext)
+
+	tokenIdList := make([]types.TokenTypeId, 0, len(tokenMap))
+	for tokenId := range tokenMap {
+		tokenIdList = append(tokenIdList, tokenId)
+	}
+	sort.Slice(tokenIdList, func(i, j int) bool {
+		return bytes.Compare(tokenIdList[i].Bytes(), tokenIdList[j].Bytes()) < 0
+	})
+
+	for _, tokenId := range tokenIdList {
+		buf, err := json.Marshal(tokenMap[tokenId])
+		if err != nil {
+			return err
+		}
+		if err := e.writer.Write(statefile.RecordTokenInfo, append(tokenId.Bytes(), buf...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportState seeds the empty chain db with the state snapshot file, the snapshot block of the file must be the
+// trusted one, which is got out of band, and the imported tries are checked against its state hash.
+// The ledger below the snapshot block is treated as pruned.
+func (c *chain) ImportState(r io.Reader, trustedHeight uint64, trustedHash types.Hash) (*ledger.SnapshotBlock, error) {
+	if c.checkData() && c.GetLatestSnapshotBlock().Height > SecondSnapshotBlock.Height {
+		return nil, errors.New("chain db is not empty")
+	}
+
+	reader, snapshotBlock, err := readStateHead(r)
+	if err != nil {
+		return nil, err
+	}
+	// the hash and the signature only prove the file is consistent, anyone is able to forge a signed one
+	if snapshotBlock.Height != trustedHeight || snapshotBlock.Hash != trustedHash {
+		return nil, errors.New(fmt.Sprintf("snapshot block %d(%s) is not the trusted one %d(%s)",
+			snapshotBlock.Height, snapshotBlock.Hash, trustedHeight, trustedHash))
+	}
+
+	c.clearData()
+	c.initData()
+	c.initCache()
+
+	if err := c.importState(reader, snapshotBlock); err != nil {
+		c.log.Error("import state failed, error is "+err.Error(), "method", "ImportState")
+
+		c.clearData()
+		c.initData()
+		c.initCache()
+		return nil, err
+	}
+	return snapshotBlock, nil
+}
+
+func readStateHead(r io.Reader) (*statefile.Reader, *ledger.SnapshotBlock, error) {
+	reader, err := statefile.NewReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recordType, payload, err := reader.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+	if recordType != statefile.RecordSnapshotBlock {
+		return nil, nil, errors.New("the first record is not the snapshot block")
+	}
+
+	snapshotBlock := &ledger.SnapshotBlock{}
+	if err := snapshotBlock.Deserialize(payload); err != nil {
+		return nil, nil, err
+	}
+	if snapshotBlock.ComputeHash() != snapshotBlock.Hash || !snapshotBlock.VerifySignature() {
+		return nil, nil, errors.New(fmt.Sprintf("snapshot block %s is broken", snapshotBlock.Hash))
+	}
+	return reader, snapshotBlock, nil
+}
+
+func (c *chain) importState(reader *statefile.Reader, snapshotBlock *ledger.SnapshotBlock) error {
+	if snapshotBlock.Height <= c.GetLatestSnapshotBlock().Height {
+		return errors.New(fmt.Sprintf("height of the snapshot block %d is too low", snapshotBlock.Height))
+	}
+
+	lastAccountId, err := c.chainDb.Account.GetLastAccountId()
+	if err != nil {
+		return err
+	}
+
+	accountIds := make(map[types.Address]uint64)
+	tokenInfos := make(map[types.TokenTypeId][]byte)
+	var latestBlocks []*ledger.AccountBlock
+
+	batch := new(leveldb.Batch)
+	count := 0
+	var recordType byte
+	var payload []byte
+	for recordType, payload, err = reader.Next(); err == nil; recordType, payload, err = reader.Next() {
+		switch recordType {
+		case statefile.RecordAccount:
+			if len(payload) < types.AddressSize {
+				return errors.New("account record is broken")
+			}
+			addr, _ := types.BytesToAddress(payload[:types.AddressSize])
+			var publicKey []byte
+			if len(payload) > types.AddressSize {
+				publicKey = payload[types.AddressSize:]
+			}
+
+			existAccount, err := c.chainDb.Account.GetAccountByAddress(&addr)
+			if err != nil {
+				return err
+			}
+			if existAccount != nil {
+				accountIds[addr] = existAccount.AccountId
+				continue
+			}
+
+			lastAccountId++
+			if _, err := c.createAccount(batch, lastAccountId, &addr, publicKey); err != nil {
+				return err
+			}
+			accountIds[addr] = lastAccountId
+
+		case statefile.RecordAccountBlock:
+			flag, snapshotHeight, blockBuf, metaBuf, err := statefile.DecodeAccountBlock(payload)
+			if err != nil {
+				return err
+			}
+
+			block := &ledger.AccountBlock{}
+			if err := block.Deserialize(blockBuf); err != nil {
+				return err
+			}
+			if block.ComputeHash() != block.Hash {
+				return errors.New(fmt.Sprintf("account block %s is broken", block.Hash))
+			}
+			meta := &ledger.AccountBlockMeta{}
+			if err := meta.Deserialize(metaBuf); err != nil {
+				return err
+			}
+
+			accountId, ok := accountIds[block.AccountAddress]
+			if !ok {
+				return errors.New(fmt.Sprintf("account %s of account block %s is missing", block.AccountAddress, block.Hash))
+			}
+
+			if flag == statefile.FlagLatest {
+				latestBlocks = append(latestBlocks, block)
+			}
+
+			// The genesis blocks are written already
+			existBlock, err := c.chainDb.Ac.GetBlock(&block.Hash)
+			if err != nil {
+				return err
+			}
+			if existBlock != nil {
+				continue
+			}
+
+			meta.AccountId = accountId
+			if err := c.chainDb.Ac.WriteBlock(batch, accountId, block); err != nil {
+				return err
+			}
+			if err := c.chainDb.Ac.WriteBlockMeta(batch, &block.Hash, meta); err != nil {
+				return err
+			}
+			if snapshotHeight > 0 {
+				if err := c.chainDb.Ac.WriteBeSnapshot(batch, &block.Hash, snapshotHeight); err != nil {
+					return err
+				}
+			}
+
+		case statefile.RecordTrieNode:
+			if err := trie.SaveNodes(batch, [][]byte{payload}, nil); err != nil {
+				return err
+			}
+
+		case statefile.RecordRefValue:
+			if err := trie.SaveNodes(batch, nil, [][]byte{payload}); err != nil {
+				return err
+			}
+
+		case statefile.RecordOnRoad:
+			if len(payload) != types.AddressSize+types.HashSize {
+				return errors.New("onroad record is broken")
+			}
+			addr, _ := types.BytesToAddress(payload[:types.AddressSize])
+			hash, _ := types.BytesToHash(payload[types.AddressSize:])
+			if err := c.chainDb.OnRoad.WriteMeta(batch, &addr, &hash); err != nil {
+				return err
+			}
+
+		case statefile.RecordGidAddr:
+			if len(payload) < types.GidSize {
+				return errors.New("gid address record is broken")
+			}
+			gid, _ := types.BytesToGid(payload[:types.GidSize])
+			if err := c.chainDb.OnRoad.WriteGidAddrList(batch, &gid, payload[types.GidSize:]); err != nil {
+				return err
+			}
+
+		case statefile.RecordTokenInfo:
+			if len(payload) < types.TokenTypeIdSize {
+				return errors.New("token info record is broken")
+			}
+			tokenId, _ := types.BytesToTokenTypeId(payload[:types.TokenTypeIdSize])
+			tokenInfos[tokenId] = payload[types.TokenTypeIdSize:]
+
+		default:
+			return errors.New(fmt.Sprintf("unknown record type %d", recordType))
+		}
+
+		count++
+		if count%importBatchLen == 0 {
+			if err := c.chainDb.Commit(batch); err != nil {
+				return err
+			}
+			batch = new(leveldb.Batch)
+		}
+	}
+	if err != io.EOF {
+		return err
+	}
+	if err := c.chainDb.Commit(batch); err != nil {
+		return err
+	}
+
+	if err := c.verifyImportedState(snapshotBlock, latestBlocks); err != nil {
+		return err
+	}
+
+	// Save snapshot block
+	batch = new(leveldb.Batch)
+	if err := c.chainDb.Sc.WriteSnapshotBlock(batch, snapshotBlock); err != nil {
+		return err
+	}
+	if err := c.chainDb.Sc.WriteSnapshotContent(batch, snapshotBlock.Height, snapshotBlock.SnapshotContent); err != nil {
+		return err
+	}
+	c.chainDb.Sc.WriteSnapshotHash(batch, &snapshotBlock.Hash, snapshotBlock.Height)
+
+	latestHashList := make([]types.Hash, 0, len(latestBlocks))
+	for _, block := range latestBlocks {
+		latestHashList = append(latestHashList, block.Hash)
+	}
+	c.chainDb.Be.AddAccountBlocks(batch, latestHashList)
+	c.chainDb.Be.AddSnapshotBlocks(batch, []types.Hash{snapshotBlock.Hash})
+
+	pruner.WritePrunedHeight(batch, snapshotBlock.Height)
+
+	if err := c.chainDb.Commit(batch); err != nil {
+		return err
+	}
+	c.initCache()
+
+	if err := c.verifyImportedTokenInfos(snapshotBlock, tokenInfos); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *chain) verifyImportedState(snapshotBlock *ledger.SnapshotBlock, latestBlocks []*ledger.AccountBlock) error {
+	db := c.TrieDb()
+	if err := trie.VerifyTrie(db, &snapshotBlock.StateHash); err != nil {
+		return err
+	}
+
+	stateTrie := c.GetStateTrie(&snapshotBlock.StateHash)
+	for _, block := range latestBlocks {
+		if !bytes.Equal(stateTrie.GetValue(block.AccountAddress.Bytes()), block.StateHash.Bytes()) {
+			return errors.New(fmt.Sprintf("state hash of account block %s mismatches the state trie", block.Hash))
+		}
+		if block.StateHash == (types.Hash{}) {
+			continue
+		}
+		if err := trie.VerifyTrie(db, &block.StateHash); err != nil {
+			return err
+		}
+	}
+
+	accountCount := 0
+	iter := stateTrie.NewIterator(nil)
+	for _, _, ok := iter.Next(); ok; _, _, ok = iter.Next() {
+		accountCount++
+	}
+	if accountCount != len(latestBlocks) {
+		return errors.New(fmt.Sprintf("%d accounts are in the state trie, but %d latest account blocks are imported", accountCount, len(latestBlocks)))
+	}
+	return nil
+}
+
+func (c *chain) verifyImportedTokenInfos(snapshotBlock *ledger.SnapshotBlock, tokenInfos map[types.TokenTypeId][]byte) error {
+	vmContext, err := vm_context.NewVmContext(c, &snapshotBlock.Hash, nil, &types.AddressMintage)
+	if err != nil {
+		return err
+	}
+	if tokenCount := len(abi.GetTokenMap(vmContext)); tokenCount != len(tokenInfos) {
+		return errors.New(fmt.Sprintf("%d token infos are in the state, but %d are in the file", tokenCount, len(tokenInfos)))
+	}
+
+	for tokenId, expected := range tokenInfos {
+		buf, err := json.Marshal(abi.GetTokenById(vmContext, tokenId))
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(buf, expected) {
+			return errors.New(fmt.Sprintf("token info of %s mismatches", tokenId))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// Package statefile reads and writes the state snapshot files. A state snapshot file is a magic header followed by
+// records, each record is a type byte, the uvarint length of the payload and the payload. The last record is an end
+// record whose payload is the sha256 of all the bytes before it, so a truncated or modified file is detected.
+package statefile
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+)
+
+const (
+	// the snapshot block with snapshot content, it's always the first record
+	RecordSnapshotBlock = byte(1)
+	// the address and the public key of an account
+	RecordAccount = byte(2)
+	// flag, snapshot height, the account block and its meta, see EncodeAccountBlock
+	RecordAccountBlock = byte(3)
+	// a trie node serialized by TrieNode.DbSerialize
+	RecordTrieNode = byte(4)
+	// the value of a trie hash node
+	RecordRefValue = byte(5)
+	// the token id and the json of the token info
+	RecordTokenInfo = byte(6)
+	// the receive address and the hash of an on road send block
+	RecordOnRoad = byte(7)
+	// the gid and the contract address list
+	RecordGidAddr = byte(8)
+
+	recordEnd = byte(255)
+)
+
+const (
+	// the latest account block of the account at the snapshot height
+	FlagLatest = byte(1)
+	// a send block which is not received at the snapshot height
+	FlagOnRoad = byte(2)
+)
+
+// maxPayloadSize guards against allocating a huge buffer for a broken length
+const maxPayloadSize = 64 * 1024 * 1024
+
+var magic = []byte("VITESTATE\x01")
+
+var (
+	ErrBadMagic         = errors.New("not a state snapshot file")
+	ErrChecksumMismatch = errors.New("checksum of the state snapshot file mismatches")
+	ErrPayloadTooLarge  = errors.New("payload of the record is too large")
+	ErrBadAccountBlock  = errors.New("account block record is broken")
+)
+
+type Writer struct {
+	w      *bufio.Writer
+	hasher hash.Hash
+}
+
+func NewWriter(w io.Writer) (*Writer, error) {
+	writer := &Writer{
+		w:      bufio.NewWriter(w),
+		hasher: sha256.New(),
+	}
+	if err := writer.write(magic); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (writer *Writer) write(data []byte) error {
+	writer.hasher.Write(data)
+	_, err := writer.w.Write(data)
+	return err
+}
+
+func (writer *Writer) writeRecord(recordType byte, payload []byte) error {
+	head := make([]byte, 1+binary.MaxVarintLen64)
+	head[0] = recordType
+	n := binary.PutUvarint(head[1:], uint64(len(payload)))
+	if err := writer.write(head[:n+1]); err != nil {
+		return err
+	}
+	return writer.write(payload)
+}
+
+func (writer *Writer) Write(recordType byte, payload []byte) error {
+	return writer.writeRecord(recordType, payload)
+}
+
+// Close writes the end record and flushes, it doesn't close the underlying writer.
+func (writer *Writer) Close() error {
+	if err := writer.writeRecord(recordEnd, writer.hasher.Sum(nil)); err != nil {
+		return err
+	}
+	return writer.w.Flush()
+}
+
+type Reader struct {
+	r      *bufio.Reader
+	hasher hash.Hash
+}
+
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := &Reader{
+		r:      bufio.NewReader(r),
+		hasher: sha256.New(),
+	}
+	head := make([]byte, len(magic))
+	if _, err := io.ReadFull(reader.r, head); err != nil {
+		return nil, ErrBadMagic
+	}
+	if !bytes.Equal(head, magic) {
+		return nil, ErrBadMagic
+	}
+	reader.hasher.Write(head)
+	return reader, nil
+}
+
+// Next returns the next record, it returns io.EOF after the end record is read and the checksum is verified.
+func (reader *Reader) Next() (byte, []byte, error) {
+	recordType, err := reader.r.ReadByte()
+	if err != nil {
+		if err == io.EOF {
+			return 0, nil, io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+	size, err := binary.ReadUvarint(reader.r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if size > maxPayloadSize {
+		return 0, nil, ErrPayloadTooLarge
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(reader.r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	if recordType == recordEnd {
+		if !bytes.Equal(payload, reader.hasher.Sum(nil)) {
+			return 0, nil, ErrChecksumMismatch
+		}
+		return 0, nil, io.EOF
+	}
+
+	head := make([]byte, 1+binary.MaxVarintLen64)
+	head[0] = recordType
+	n := binary.PutUvarint(head[1:], size)
+	reader.hasher.Write(head[:n+1])
+	reader.hasher.Write(payload)
+	return recordType, payload, nil
+}
+
+// EncodeAccountBlock encodes the payload of RecordAccountBlock, block and meta are serialized by the caller.
+func EncodeAccountBlock(flag byte, snapshotHeight uint64, block []byte, meta []byte) []byte {
+	payload := make([]byte, 1+8+binary.MaxVarintLen64, 1+8+binary.MaxVarintLen64+len(block)+len(meta))
+	payload[0] = flag
+	binary.BigEndian.PutUint64(payload[1:9], snapshotHeight)
+	n := binary.PutUvarint(payload[9:], uint64(len(block)))
+	payload = payload[:9+n]
+	payload = append(payload, block...)
+	return append(payload, meta...)
+}
+
+func DecodeAccountBlock(payload []byte) (flag byte, snapshotHeight uint64, block []byte, meta []byte, err error) {
+	if len(payload) < 10 {
+		return 0, 0, nil, nil, ErrBadAccountBlock
+	}
+	flag = payload[0]
+	snapshotHeight = binary.BigEndian.Uint64(payload[1:9])
+	size, n := binary.Uvarint(payload[9:])
+	if n <= 0 || uint64(len(payload)-9-n) < size {
+		return 0, 0, nil, nil, ErrBadAccountBlock
+	}
+	block = payload[9+n : 9+n+int(size)]
+	meta = payload[9+n+int(size):]
+	return flag, snapshotHeight, block, meta, nil
+}
@@ -0,0 +1,61 @@
+package statefile
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestStateFile(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer, err := NewWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer.Write(RecordSnapshotBlock, []byte("snapshot block"))
+	writer.Write(RecordAccountBlock, EncodeAccountBlock(FlagLatest, 10, []byte("block"), []byte("meta")))
+	writer.Write(RecordTrieNode, nil)
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	reader, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordType, payload, _ := reader.Next()
+	if recordType != RecordSnapshotBlock || string(payload) != "snapshot block" {
+		t.Fatalf("unexpected record %v %s", recordType, payload)
+	}
+	recordType, payload, _ = reader.Next()
+	flag, snapshotHeight, block, meta, err := DecodeAccountBlock(payload)
+	if recordType != RecordAccountBlock || err != nil || flag != FlagLatest || snapshotHeight != 10 ||
+		string(block) != "block" || string(meta) != "meta" {
+		t.Fatalf("unexpected account block record %v %v %v %s %s", recordType, flag, snapshotHeight, block, meta)
+	}
+	if recordType, payload, _ = reader.Next(); recordType != RecordTrieNode || len(payload) != 0 {
+		t.Fatalf("unexpected record %v %s", recordType, payload)
+	}
+	if _, _, err := reader.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+
+	// a modified payload is detected by the checksum
+	broken := append([]byte(nil), data...)
+	broken[len(magic)+3] ^= 0xff
+	reader, _ = NewReader(bytes.NewReader(broken))
+	for err = nil; err == nil; _, _, err = reader.Next() {
+	}
+	if err != ErrChecksumMismatch {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+
+	// a truncated file
+	reader, _ = NewReader(bytes.NewReader(data[:len(data)-40]))
+	for err = nil; err == nil; _, _, err = reader.Next() {
+	}
+	if err == io.EOF {
+		t.Fatal("expected the truncated file to be detected")
+	}
+}
@@ -2,6 +2,7 @@ package access
 
 import (
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 )
@@ -33,3 +34,68 @@ func (or *OnRoad) GetMeta(addr *types.Address, hash *types.Hash) ([]byte, error)
 	}
 	return value, nil
 }
+
+func (or *OnRoad) WriteMeta(batch *leveldb.Batch, addr *types.Address, hash *types.Hash) error {
+	key, err := database.EncodeKey(database.DBKP_ONROADMETA, addr.Bytes(), hash.Bytes())
+	if err != nil {
+		return err
+	}
+	batch.Put(key, []byte{byte(0)})
+	return nil
+}
+
+// GetAllMeta returns the hash list of the onroad send blocks of every receiver
+func (or *OnRoad) GetAllMeta() (map[types.Address][]types.Hash, error) {
+	iter := or.db.NewIterator(util.BytesPrefix([]byte{database.DBKP_ONROADMETA}), nil)
+	defer iter.Release()
+
+	metaMap := make(map[types.Address][]types.Hash)
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != 1+types.AddressSize+types.HashSize {
+			continue
+		}
+
+		addr, _ := types.BytesToAddress(key[1 : 1+types.AddressSize])
+		hash, _ := types.BytesToHash(key[1+types.AddressSize:])
+		metaMap[addr] = append(metaMap[addr], hash)
+	}
+
+	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+		return nil, err
+	}
+	return metaMap, nil
+}
+
+// GetAllGidAddrList returns the serialized contract address list of every consensus group
+func (or *OnRoad) GetAllGidAddrList() (map[types.Gid][]byte, error) {
+	iter := or.db.NewIterator(util.BytesPrefix([]byte{database.DBKP_GID_ADDR}), nil)
+	defer iter.Release()
+
+	listMap := make(map[types.Gid][]byte)
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != 1+types.GidSize {
+			continue
+		}
+
+		gid, _ := types.BytesToGid(key[1:])
+		value := make([]byte, len(iter.Value()))
+		copy(value, iter.Value())
+		listMap[gid] = value
+	}
+
+	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+		return nil, err
+	}
+	return listMap, nil
+}
+
+func (or *OnRoad) WriteGidAddrList(batch *leveldb.Batch, gid *types.Gid, data []byte) error {
+	key, err := database.EncodeKey(database.DBKP_GID_ADDR, gid.Bytes())
+	if err != nil {
+		return err
+	}
+	batch.Put(key, data)
+	return nil
+}
@@ -85,6 +85,11 @@ func (sc *SnapshotChain) GetLatestBlock() (*ledger.SnapshotBlock, error) {
 	return sb, nil
 }
 
+func (sc *SnapshotChain) DeleteSnapshotContent(batch *leveldb.Batch, snapshotBlockHeight uint64) {
+	key, _ := database.EncodeKey(database.DBKP_SNAPSHOTCONTENT, snapshotBlockHeight)
+	batch.Delete(key)
+}
+
 func (sc *SnapshotChain) GetSnapshotContent(snapshotBlockHeight uint64) (ledger.SnapshotContent, error) {
 	key, _ := database.EncodeKey(database.DBKP_SNAPSHOTCONTENT, snapshotBlockHeight)
 	data, err := sc.db.Get(key, nil)
@@ -0,0 +1,93 @@
+package gvite_plugins
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vitelabs/go-vite/chain/export"
+	"github.com/vitelabs/go-vite/cmd/nodemanager"
+	"github.com/vitelabs/go-vite/cmd/utils"
+	"gopkg.in/urfave/cli.v1"
+	"path/filepath"
+)
+
+const (
+	exportSourceChainDb = "chaindb"
+	exportSourceFiles   = "files"
+)
+
+var (
+	exportSourceFlag = cli.StringFlag{
+		Name:  "source",
+		Usage: "Where the ledger is read from, chaindb or files(the ledger_files directory, vm logs are not included)",
+		Value: exportSourceChainDb,
+	}
+	exportFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "The first snapshot height to export",
+		Value: 1,
+	}
+	exportToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "The last snapshot height to export, 0 means the latest one",
+	}
+	exportOutFlag = cli.StringFlag{
+		Name:  "out",
+		Usage: "Directory of the exported csv files",
+	}
+	exportPartitionFlag = cli.Uint64Flag{
+		Name:  "partition",
+		Usage: "Count of snapshot heights in one csv file",
+		Value: export.DefaultPartitionSize,
+	}
+
+	ledgerExportCommand = cli.Command{
+		Action:    utils.MigrateFlags(ledgerExportAction),
+		Name:      "ledger-export",
+		Usage:     "Export the ledger to csv files partitioned by snapshot height",
+		ArgsUsage: " ",
+		Flags:     []cli.Flag{exportSourceFlag, exportFromFlag, exportToFlag, exportOutFlag, exportPartitionFlag},
+		Category:  "LEDGER COMMANDS",
+		Description: `
+The node must be stopped. Snapshot blocks, account blocks, vm logs and token transfers are written to
+<out>/<table>/<first height>-<last height>.csv, each file starts with a header line.
+`,
+	}
+)
+
+func ledgerExportAction(ctx *cli.Context) error {
+	outDir := ctx.String(exportOutFlag.Name)
+	if outDir == "" {
+		return errors.New("--out is required")
+	}
+	fromHeight := ctx.Uint64(exportFromFlag.Name)
+	toHeight := ctx.Uint64(exportToFlag.Name)
+
+	exporter, err := export.NewExporter(outDir, ctx.Uint64(exportPartitionFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	switch source := ctx.String(exportSourceFlag.Name); source {
+	case exportSourceChainDb:
+		c := openChain(ctx)
+		defer c.Destroy()
+
+		err = export.ExportChain(c, exporter, fromHeight, toHeight)
+	case exportSourceFiles:
+		cfg := nodemanager.FullNodeMaker{}.MakeNodeConfig(ctx)
+
+		err = export.ExportLedgerFiles(filepath.Join(cfg.DataDir, "ledger_files"), exporter, fromHeight, toHeight, nil)
+	default:
+		err = fmt.Errorf("unknown source %q", source)
+	}
+
+	if closeErr := exporter.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Ledger is exported to %s\n", outDir)
+	return nil
+}
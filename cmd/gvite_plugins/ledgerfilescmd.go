@@ -0,0 +1,102 @@
+package gvite_plugins
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/chain_db"
+	"github.com/vitelabs/go-vite/cmd/nodemanager"
+	"github.com/vitelabs/go-vite/cmd/utils"
+	"github.com/vitelabs/go-vite/compress"
+	"github.com/vitelabs/go-vite/ledger"
+	"gopkg.in/urfave/cli.v1"
+	"os"
+	"path/filepath"
+)
+
+var (
+	ledgerFilesCommand = cli.Command{
+		Name:     "ledger-files",
+		Usage:    "Verify or repair the ledger files written by the compressor",
+		Category: "LEDGER COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(ledgerFilesVerifyAction),
+				Name:      "verify",
+				Usage:     "Re-parse the ledger files and report gaps, truncated files and corrupt blocks",
+				ArgsUsage: " ",
+				Description: `
+Every file in the index is re-parsed, the block hashes are recomputed and the snapshot blocks are
+checked against the former file and the snapshot chain in the chain db if it exists.
+`,
+			},
+			{
+				Action:    utils.MigrateFlags(ledgerFilesRepairAction),
+				Name:      "repair",
+				Usage:     "Rebuild the index and re-generate the bad files from the chain db",
+				ArgsUsage: " ",
+				Description: `
+The node must be stopped. The right files on the disk are put back into the index, the broken files
+and the gaps are re-generated from the chain db. The index ends before the ledger which is pruned.
+`,
+			},
+		},
+	}
+)
+
+func ledgerFilesVerifyAction(ctx *cli.Context) error {
+	cfg := nodemanager.FullNodeMaker{}.MakeNodeConfig(ctx)
+
+	var getSnapshotBlock compress.SnapshotBlockGetter
+	ledgerDir := filepath.Join(cfg.DataDir, "ledger")
+	if _, err := os.Stat(ledgerDir); err == nil {
+		chainDb := chain_db.NewChainDb(ledgerDir)
+		if chainDb == nil {
+			return errors.New("open chain db failed")
+		}
+		defer chainDb.Db().Close()
+
+		getSnapshotBlock = func(height uint64) (*ledger.SnapshotBlock, error) {
+			return chainDb.Sc.GetSnapshotBlock(height, false)
+		}
+	}
+
+	problems, err := compress.Verify(filepath.Join(cfg.DataDir, "ledger_files"), getSnapshotBlock)
+	if err != nil {
+		return err
+	}
+
+	for _, problem := range problems {
+		fmt.Println(problem.String())
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d problems are found, run ledger-files repair to fix them", len(problems))
+	}
+
+	fmt.Println("Ledger files are verified")
+	return nil
+}
+
+func ledgerFilesRepairAction(ctx *cli.Context) error {
+	// the files which are not in the index are reused by the repair
+	viteCfg := nodemanager.FullNodeMaker{}.MakeNodeConfig(ctx).ViteConfig()
+	viteCfg.Chain.LedgerFilesKeepOrphans = true
+
+	c := chain.NewChain(viteCfg)
+	c.Init()
+	defer c.Destroy()
+
+	result, err := c.Compressor().Repair(c.GetSnapshotBlockByHeight)
+	if err != nil {
+		return err
+	}
+
+	for _, filename := range result.Regenerated {
+		fmt.Printf("%s is re-generated\n", filename)
+	}
+	for _, problem := range result.Unrepaired {
+		fmt.Println(problem.String())
+	}
+	fmt.Printf("Ledger files are indexed to snapshot height %d\n", result.IndexedHeight)
+	return nil
+}
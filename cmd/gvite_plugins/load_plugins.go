@@ -80,6 +80,9 @@ var (
 		utils.MinerFlag,
 		utils.CoinBaseFlag,
 		utils.MinerIntervalFlag,
+		utils.SignerEndpointFlag,
+		utils.LeaseListenFlag,
+		utils.LeasePeersFlag,
 	}
 
 	//Log
@@ -97,6 +100,15 @@ var (
 	netFlags = []cli.Flag{
 		utils.SingleFlag,
 		utils.FilePortFlag,
+		utils.FileHttpAddrFlag,
+		utils.LedgerMirrorsFlag,
+	}
+
+	//Watchdog
+	watchdogFlags = []cli.Flag{
+		utils.WatchdogFlag,
+		utils.WatchdogWebhooksFlag,
+		utils.WatchdogCommandFlag,
 	}
 
 	//Stat
@@ -128,11 +140,18 @@ func init() {
 		licenseCommand,
 		consoleCommand,
 		attachCommand,
+		exportStateCommand,
+		importStateCommand,
+		ledgerExportCommand,
+		ledgerFilesCommand,
+		signerCommand,
+		exportProtectionCommand,
+		importProtectionCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
 	//Import: Please add the New Flags here
-	app.Flags = utils.MergeFlags(configFlags, generalFlags, p2pFlags, ipcFlags, httpFlags, wsFlags, consoleFlags, producerFlags, logFlags, vmFlags, netFlags, statFlags)
+	app.Flags = utils.MergeFlags(configFlags, generalFlags, p2pFlags, ipcFlags, httpFlags, wsFlags, consoleFlags, producerFlags, logFlags, vmFlags, netFlags, watchdogFlags, statFlags)
 
 	app.Before = beforeAction
 	app.Action = action
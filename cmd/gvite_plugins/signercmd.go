@@ -0,0 +1,180 @@
+package gvite_plugins
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/vitelabs/go-vite/cmd/nodemanager"
+	"github.com/vitelabs/go-vite/cmd/utils"
+	"github.com/vitelabs/go-vite/signer"
+	"github.com/vitelabs/go-vite/wallet"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	signerEndpointFlag = cli.StringFlag{
+		Name:  "endpoint",
+		Usage: "IPC endpoint the signer daemon listens on",
+	}
+	signerEntropyStoreFlag = cli.StringFlag{
+		Name:  "entropystore",
+		Usage: "Entropy store holding the coinbase key",
+	}
+	signerPasswordFileFlag = cli.StringFlag{
+		Name:  "passwordfile",
+		Usage: "File containing the password of the entropy store",
+	}
+	signerProtectionFlag = cli.StringFlag{
+		Name:  "protection",
+		Usage: "File recording the signed slots, the daemon refuses to sign twice for a slot",
+	}
+	protectionExportFileFlag = cli.StringFlag{
+		Name:  "file",
+		Usage: "Path of the exported protection record",
+	}
+
+	signerCommand = cli.Command{
+		Action:    utils.MigrateFlags(signerAction),
+		Name:      "signer",
+		Usage:     "Run a signing daemon for a snapshot block producer",
+		ArgsUsage: " ",
+		Flags:     []cli.Flag{signerEndpointFlag, signerEntropyStoreFlag, signerPasswordFileFlag, signerProtectionFlag},
+		Category:  "PRODUCER COMMANDS",
+		Description: `
+The daemon keeps the coinbase key out of the producing node. Start the node with --signer
+set to the same endpoint, the node then asks the daemon to sign every block it produces.
+The entropy store is looked up in the keystore of the node.
+`,
+	}
+	exportProtectionCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportProtectionAction),
+		Name:      "export-protection",
+		Usage:     "Export the slots signed by the producer to a file",
+		ArgsUsage: " ",
+		Flags:     []cli.Flag{protectionExportFileFlag, signerProtectionFlag},
+		Category:  "PRODUCER COMMANDS",
+		Description: `
+The producer must be stopped. Import the file on the host the producer is moved to before
+starting it there, so that it never signs a block conflicting with one signed on this host.
+The record of the node is exported unless --protection is set.
+`,
+	}
+	importProtectionCommand = cli.Command{
+		Action:    utils.MigrateFlags(importProtectionAction),
+		Name:      "import-protection",
+		Usage:     "Merge the slots exported from another host into the record of the producer",
+		ArgsUsage: " ",
+		Flags:     []cli.Flag{protectionExportFileFlag, signerProtectionFlag},
+		Category:  "PRODUCER COMMANDS",
+		Description: `
+The producer must be stopped. A recorded slot is only replaced by a later one.
+The record of the node is imported into unless --protection is set.
+`,
+	}
+)
+
+func signerAction(ctx *cli.Context) error {
+	endpoint := ctx.String(signerEndpointFlag.Name)
+	entropyStore := ctx.String(signerEntropyStoreFlag.Name)
+	protectionFile := ctx.String(signerProtectionFlag.Name)
+	if endpoint == "" || entropyStore == "" || protectionFile == "" {
+		return errors.New("--endpoint, --entropystore and --protection are required")
+	}
+
+	password := ""
+	if passwordFile := ctx.String(signerPasswordFileFlag.Name); passwordFile != "" {
+		data, err := ioutil.ReadFile(passwordFile)
+		if err != nil {
+			return err
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+	}
+
+	cfg := nodemanager.FullNodeMaker{}.MakeNodeConfig(ctx)
+	wt := wallet.New(&wallet.Config{DataDir: cfg.KeyStoreDir})
+	if err := wt.AddEntropyStore(entropyStore); err != nil {
+		return err
+	}
+	manager, err := wt.GetEntropyStoreManager(entropyStore)
+	if err != nil {
+		return err
+	}
+	if err := manager.Unlock(password); err != nil {
+		return err
+	}
+
+	protection, err := signer.NewProtection(protectionFile)
+	if err != nil {
+		return err
+	}
+	listener, err := signer.StartDaemon(endpoint, signer.NewLocalSigner(wt), protection)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	fmt.Printf("Signer daemon is listening on %s\n", endpoint)
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	<-sigc
+	return nil
+}
+
+func openProtection(ctx *cli.Context) (*signer.Protection, error) {
+	protectionFile := ctx.String(signerProtectionFlag.Name)
+	if protectionFile == "" {
+		cfg := nodemanager.FullNodeMaker{}.MakeNodeConfig(ctx)
+		protectionFile = cfg.ViteConfig().ProducerProtectionFile()
+	}
+	return signer.NewProtection(protectionFile)
+}
+
+func exportProtectionAction(ctx *cli.Context) error {
+	fileName := ctx.String(protectionExportFileFlag.Name)
+	if fileName == "" {
+		return errors.New("--file is required")
+	}
+	protection, err := openProtection(ctx)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := protection.Export(file); err != nil {
+		os.Remove(fileName)
+		return err
+	}
+	fmt.Printf("Protection record is exported to %s\n", fileName)
+	return nil
+}
+
+func importProtectionAction(ctx *cli.Context) error {
+	fileName := ctx.String(protectionExportFileFlag.Name)
+	if fileName == "" {
+		return errors.New("--file is required")
+	}
+	protection, err := openProtection(ctx)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := protection.Import(file); err != nil {
+		return err
+	}
+	fmt.Printf("Protection record is imported from %s\n", fileName)
+	return nil
+}
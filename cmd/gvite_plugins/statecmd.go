@@ -0,0 +1,126 @@
+package gvite_plugins
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/cmd/nodemanager"
+	"github.com/vitelabs/go-vite/cmd/utils"
+	"github.com/vitelabs/go-vite/common/types"
+	"gopkg.in/urfave/cli.v1"
+	"os"
+)
+
+var (
+	stateHeightFlag = cli.Uint64Flag{
+		Name:  "height",
+		Usage: "Height of the snapshot block to export the state at",
+	}
+	stateFileFlag = cli.StringFlag{
+		Name:  "file",
+		Usage: "Path of the state snapshot file",
+	}
+	trustedHeightFlag = cli.Uint64Flag{
+		Name:  "trusted-height",
+		Usage: "Height of the trusted snapshot block which the state snapshot file must be exported at",
+	}
+	trustedHashFlag = cli.StringFlag{
+		Name:  "trusted-hash",
+		Usage: "Hash of the trusted snapshot block which the state snapshot file must be exported at",
+	}
+
+	exportStateCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportStateAction),
+		Name:      "export-state",
+		Usage:     "Export the state of all accounts at a snapshot block to a file",
+		ArgsUsage: " ",
+		Flags:     []cli.Flag{stateHeightFlag, stateFileFlag},
+		Category:  "LEDGER COMMANDS",
+		Description: `
+The node must be stopped. The file can be imported by import-state to bootstrap a new node
+without syncing the whole ledger.
+`,
+	}
+	importStateCommand = cli.Command{
+		Action:    utils.MigrateFlags(importStateAction),
+		Name:      "import-state",
+		Usage:     "Seed an empty ledger with a state snapshot file",
+		ArgsUsage: " ",
+		Flags:     []cli.Flag{stateFileFlag, trustedHeightFlag, trustedHashFlag},
+		Category:  "LEDGER COMMANDS",
+		Description: `
+The snapshot block in the file must match the trusted height and hash, which should be got from
+a source you trust, such as a synced node or the official release. The imported state is checked
+against the state hash of the snapshot block, the ledger below it is treated as pruned.
+`,
+	}
+)
+
+func openChain(ctx *cli.Context) chain.Chain {
+	cfg := nodemanager.FullNodeMaker{}.MakeNodeConfig(ctx)
+
+	c := chain.NewChain(cfg.ViteConfig())
+	c.Init()
+	return c
+}
+
+func exportStateAction(ctx *cli.Context) error {
+	height := ctx.Uint64(stateHeightFlag.Name)
+	fileName := ctx.String(stateFileFlag.Name)
+	if fileName == "" {
+		return errors.New("--file is required")
+	}
+
+	c := openChain(ctx)
+	defer c.Destroy()
+
+	if height == 0 {
+		height = c.GetLatestSnapshotBlock().Height
+	}
+
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := c.ExportState(height, file); err != nil {
+		os.Remove(fileName)
+		return err
+	}
+
+	fmt.Printf("State at snapshot block %d is exported to %s\n", height, fileName)
+	return nil
+}
+
+func importStateAction(ctx *cli.Context) error {
+	fileName := ctx.String(stateFileFlag.Name)
+	if fileName == "" {
+		return errors.New("--file is required")
+	}
+	trustedHeight := ctx.Uint64(trustedHeightFlag.Name)
+	if trustedHeight == 0 || ctx.String(trustedHashFlag.Name) == "" {
+		return errors.New("--trusted-height and --trusted-hash are required")
+	}
+	trustedHash, err := types.HexToHash(ctx.String(trustedHashFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	c := openChain(ctx)
+	defer c.Destroy()
+
+	snapshotBlock, err := c.ImportState(file, trustedHeight, trustedHash)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("State at snapshot block %d(%s) is imported\n", snapshotBlock.Height, snapshotBlock.Hash)
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/vitelabs/go-vite/cmd/utils"
 	"github.com/vitelabs/go-vite/common"
@@ -141,6 +142,18 @@ func mappingNodeConfig(ctx *cli.Context, cfg *node.Config) {
 		cfg.MinerInterval = ctx.GlobalInt(utils.MinerIntervalFlag.Name)
 	}
 
+	if signerEndpoint := ctx.GlobalString(utils.SignerEndpointFlag.Name); len(signerEndpoint) > 0 {
+		cfg.SignerEndpoint = signerEndpoint
+	}
+
+	if leaseListen := ctx.GlobalString(utils.LeaseListenFlag.Name); len(leaseListen) > 0 {
+		cfg.LeaseListen = leaseListen
+	}
+
+	if leasePeers := ctx.GlobalString(utils.LeasePeersFlag.Name); len(leasePeers) > 0 {
+		cfg.LeasePeers = strings.Split(leasePeers, ",")
+	}
+
 	//Log Level Config
 	if logLevel := ctx.GlobalString(utils.LogLvlFlag.Name); len(logLevel) > 0 {
 		cfg.LogLevel = logLevel
@@ -162,6 +175,27 @@ func mappingNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(utils.FilePortFlag.Name) {
 		cfg.FilePort = ctx.GlobalInt(utils.FilePortFlag.Name)
 	}
+
+	if fileHttpAddr := ctx.GlobalString(utils.FileHttpAddrFlag.Name); len(fileHttpAddr) > 0 {
+		cfg.FileHttpAddr = fileHttpAddr
+	}
+
+	if ledgerMirrors := ctx.GlobalString(utils.LedgerMirrorsFlag.Name); len(ledgerMirrors) > 0 {
+		cfg.LedgerMirrors = strings.Split(ledgerMirrors, ",")
+	}
+
+	//Watchdog
+	if ctx.GlobalIsSet(utils.WatchdogFlag.Name) {
+		cfg.WatchdogEnabled = ctx.GlobalBool(utils.WatchdogFlag.Name)
+	}
+
+	if webhooks := ctx.GlobalString(utils.WatchdogWebhooksFlag.Name); len(webhooks) > 0 {
+		cfg.WatchdogWebhooks = strings.Split(webhooks, ",")
+	}
+
+	if command := ctx.GlobalString(utils.WatchdogCommandFlag.Name); len(command) > 0 {
+		cfg.WatchdogCommand = command
+	}
 }
 
 func overrideNodeConfigs(ctx *cli.Context, cfg *node.Config) {
@@ -139,6 +139,21 @@ var (
 		Usage: "Miner Interval(unit: second)",
 	}
 
+	SignerEndpointFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "IPC endpoint of the remote signer daemon which holds the coinbase key",
+	}
+
+	LeaseListenFlag = cli.StringFlag{
+		Name:  "leaselisten",
+		Usage: "TCP address to receive the heartbeats of the standby producers on, enables the hot-standby mode",
+	}
+
+	LeasePeersFlag = cli.StringFlag{
+		Name:  "leasepeers",
+		Usage: "Comma separated TCP addresses of the other producers holding the same coinbase",
+	}
+
 	//Log Lvl
 	LogLvlFlag = cli.StringFlag{
 		Name:  "loglevel",
@@ -166,6 +181,32 @@ var (
 		Usage: "File transfer listening port",
 	}
 
+	FileHttpAddrFlag = cli.StringFlag{
+		Name:  "filehttpaddr",
+		Usage: "Serve the ledger files over http on the address, e.g. 0.0.0.0:8485",
+	}
+
+	LedgerMirrorsFlag = cli.StringFlag{
+		Name:  "ledgermirrors",
+		Usage: "Comma separated urls of the http ledger file servers to download the ledger files from",
+	}
+
+	//Watchdog
+	WatchdogFlag = cli.BoolFlag{
+		Name:  "watchdog",
+		Usage: "Enable the watchdog alerting on missed producer slots and sync stalls",
+	}
+
+	WatchdogWebhooksFlag = cli.StringFlag{
+		Name:  "watchdogwebhooks",
+		Usage: "Comma separated urls the watchdog posts the alerts to",
+	}
+
+	WatchdogCommandFlag = cli.StringFlag{
+		Name:  "watchdogcommand",
+		Usage: "Local command and its space separated arguments the watchdog runs without a shell for every alert, the alert is passed on the stdin",
+	}
+
 	//Stat
 	PProfEnabledFlag = cli.BoolFlag{
 		Name:  "pprof",
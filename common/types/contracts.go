@@ -49,6 +49,8 @@ type TokenInfo struct {
 	TotalSupply    *big.Int `json:"totalSupply"`
 	Decimals       uint8    `json:"decimals"`
 	Owner          Address  `json:"owner"`
+	IsReIssuable   bool     `json:"isReIssuable"`
+	MaxSupply      *big.Int `json:"maxSupply"`
 	PledgeAmount   *big.Int `json:"pledgeAmount"`
 	WithdrawHeight uint64   `json:"withdrawHeight"`
 }
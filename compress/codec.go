@@ -0,0 +1,146 @@
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/DataDog/zstd"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+)
+
+const (
+	CodecNone   = "none"
+	CodecSnappy = "snappy"
+	CodecZstd   = "zstd"
+)
+
+const zstdBufferSize = 1024 * 1024
+
+var (
+	zstdMagic   = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	snappyMagic = []byte("\xff\x06\x00\x00sNaPpY")
+)
+
+// Codec compresses the ledger files, the codec of a file is recorded in its CompressedFileMeta
+type Codec interface {
+	Name() string
+
+	// NewWriter returns a writer which compresses the data to w, closing it doesn't close w
+	NewWriter(w io.Writer) io.WriteCloser
+	// NewReader returns a reader which decompresses the data from r, closing it doesn't close r
+	NewReader(r io.Reader) io.ReadCloser
+}
+
+var codecs = map[string]Codec{
+	CodecNone:   noneCodec{},
+	CodecSnappy: snappyCodec{},
+	CodecZstd:   zstdCodec{},
+}
+
+// Codecs returns the names of the codecs, they're advertised to the peers
+func Codecs() []string {
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetCodec returns the codec of the name, the files without the codec name are not compressed.
+func GetCodec(name string) (Codec, error) {
+	if name == "" {
+		name = CodecNone
+	}
+
+	codec, ok := codecs[name]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("unknown codec %s", name))
+	}
+	return codec, nil
+}
+
+// DetectCodec returns the codec of the file by the first bytes of it
+func DetectCodec(header []byte) string {
+	switch {
+	case bytes.HasPrefix(header, zstdMagic):
+		return CodecZstd
+	case bytes.HasPrefix(header, snappyMagic):
+		return CodecSnappy
+	default:
+		return CodecNone
+	}
+}
+
+type bufferedWriteCloser struct {
+	*bufio.Writer
+	closer io.Closer
+}
+
+func (w *bufferedWriteCloser) Close() error {
+	if err := w.Flush(); err != nil {
+		w.closer.Close()
+		return err
+	}
+	return w.closer.Close()
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+type noneCodec struct{}
+
+func (noneCodec) Name() string {
+	return CodecNone
+}
+
+func (noneCodec) NewWriter(w io.Writer) io.WriteCloser {
+	return nopWriteCloser{w}
+}
+
+func (noneCodec) NewReader(r io.Reader) io.ReadCloser {
+	return ioutil.NopCloser(r)
+}
+
+type snappyCodec struct{}
+
+func (snappyCodec) Name() string {
+	return CodecSnappy
+}
+
+func (snappyCodec) NewWriter(w io.Writer) io.WriteCloser {
+	return snappy.NewBufferedWriter(w)
+}
+
+func (snappyCodec) NewReader(r io.Reader) io.ReadCloser {
+	return ioutil.NopCloser(snappy.NewReader(r))
+}
+
+type zstdCodec struct{}
+
+func (zstdCodec) Name() string {
+	return CodecZstd
+}
+
+func (zstdCodec) NewWriter(w io.Writer) io.WriteCloser {
+	// every write is compressed as a zstd block, so the small writes are buffered
+	zw := zstd.NewWriter(w)
+	return &bufferedWriteCloser{
+		Writer: bufio.NewWriterSize(zw, zstdBufferSize),
+		closer: zw,
+	}
+}
+
+func (zstdCodec) NewReader(r io.Reader) io.ReadCloser {
+	return zstd.NewReader(r)
+}
@@ -0,0 +1,191 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+var testCodecs = []string{CodecNone, CodecSnappy, CodecZstd}
+
+// newTestBlocks returns the snapshot blocks between the heights, every snapshot block confirms an account block
+func newTestBlocks(startHeight, endHeight uint64) []ledger.Block {
+	now := time.Unix(1541000000, 0)
+	addr, _ := types.BytesToAddress([]byte("12345678901234567890"))
+	to, _ := types.BytesToAddress([]byte("09876543210987654321"))
+
+	var blocks []ledger.Block
+	var prevHash, prevAccountHash types.Hash
+	for height := startHeight; height <= endHeight; height++ {
+		accountBlock := &ledger.AccountBlock{
+			BlockType:      ledger.BlockTypeSendCall,
+			AccountAddress: addr,
+			ToAddress:      to,
+			TokenId:        ledger.ViteTokenId,
+			Amount:         big.NewInt(int64(height)),
+			Fee:            big.NewInt(0),
+			Height:         height,
+			PrevHash:       prevAccountHash,
+			Data:           []byte("transfer"),
+			Timestamp:      &now,
+		}
+		accountBlock.Hash = accountBlock.ComputeHash()
+		prevAccountHash = accountBlock.Hash
+
+		snapshotBlock := &ledger.SnapshotBlock{
+			Height:    height,
+			PrevHash:  prevHash,
+			Timestamp: &now,
+			SnapshotContent: ledger.SnapshotContent{
+				addr: &ledger.HashHeight{Height: height, Hash: accountBlock.Hash},
+			},
+		}
+		snapshotBlock.Hash = snapshotBlock.ComputeHash()
+		prevHash = snapshotBlock.Hash
+
+		blocks = append(blocks, snapshotBlock, accountBlock)
+	}
+	return blocks
+}
+
+func writeTestBlocks(t testing.TB, filename string, codec Codec, blocks []ledger.Block) {
+	writer := NewFileWriter(filename, codec)
+	if writer == nil {
+		t.Fatal("create file failed")
+	}
+	if err := BlockFormatter(writer, func(uint64, uint64) ([]ledger.Block, error) {
+		return blocks, io.EOF
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCodec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ledger_files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	blocks := newTestBlocks(1, 100)
+	for _, name := range testCodecs {
+		codec, err := GetCodec(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		filename := filepath.Join(dir, "subgraph_1_100_"+name)
+		writeTestBlocks(t, filename, codec, blocks)
+
+		if detected := detectFileCodec(filename); detected != name {
+			t.Fatalf("codec of %s is detected as %s", name, detected)
+		}
+
+		reader := NewFileReader(filename, codec)
+		var parsed []ledger.Block
+		BlockParser(reader, 0, func(block ledger.Block, err error) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed = append(parsed, block)
+		})
+		reader.Close()
+
+		if len(parsed) != len(blocks) {
+			t.Fatalf("%d blocks are parsed by %s, expect %d", len(parsed), name, len(blocks))
+		}
+		for i, block := range parsed {
+			a, _ := block.Serialize()
+			b, _ := blocks[i].Serialize()
+			if !bytes.Equal(a, b) {
+				t.Fatalf("block %d parsed by %s mismatches", i, name)
+			}
+		}
+	}
+
+	// the files written before the codec is recorded are not compressed
+	if codec, err := GetCodec(""); err != nil || codec.Name() != CodecNone {
+		t.Fatalf("unexpected codec %v, error is %v", codec, err)
+	}
+	if _, err := GetCodec("gzip"); err == nil {
+		t.Fatal("unknown codec should fail")
+	}
+}
+
+func TestIndexLineCodec(t *testing.T) {
+	item := &ledger.CompressedFileMeta{
+		StartHeight:  1,
+		EndHeight:    5,
+		Filename:     "subgraph_1_5",
+		FileSize:     100,
+		BlockNumbers: 5,
+		Codec:        CodecZstd,
+	}
+
+	parsed, err := parseIndexLine([]byte(formatIndexLine(item)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Checksum != nil || parsed.Codec != CodecZstd {
+		t.Fatalf("unexpected index line %+v", parsed)
+	}
+}
+
+// BenchmarkCodec compares the file size and the throughput of writing and parsing a ledger file by the codecs,
+// the throughput is reported in MB/s of the file and the blocks per second are logged
+func BenchmarkCodec(b *testing.B) {
+	dir, err := ioutil.TempDir("", "ledger_files")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	blocks := newTestBlocks(1, 3600)
+	for _, name := range testCodecs {
+		codec, _ := GetCodec(name)
+		filename := filepath.Join(dir, "subgraph_1_3600_"+name)
+
+		writeTestBlocks(b, filename, codec, blocks)
+		fileInfo, err := os.Stat(filename)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Logf("%s file is %d bytes", name, fileInfo.Size())
+
+		b.Run(name+"/write", func(b *testing.B) {
+			b.SetBytes(fileInfo.Size())
+			for i := 0; i < b.N; i++ {
+				writeTestBlocks(b, filename, codec, blocks)
+			}
+		})
+
+		b.Run(name+"/parse", func(b *testing.B) {
+			b.SetBytes(fileInfo.Size())
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				reader := NewFileReader(filename, codec)
+				count := 0
+				BlockParser(reader, 0, func(block ledger.Block, err error) {
+					count++
+				})
+				reader.Close()
+
+				if count != len(blocks) {
+					b.Fatalf("%d blocks are parsed, expect %d", count, len(blocks))
+				}
+			}
+			b.Logf("%.0f blocks/s", float64(len(blocks)*b.N)/time.Since(start).Seconds())
+		})
+	}
+}
@@ -19,6 +19,14 @@ const (
 	TASK_RUNNING = 2
 )
 
+const (
+	DefaultChunkSize = uint64(3600)
+	DefaultInterval  = time.Minute * 10
+)
+
+// taskTmpFileName is the file which the compressor task writes to, it's renamed after the task finishes
+const taskTmpFileName = "subgraph_tmp"
+
 var compressorLog = log15.New("module", "compressor")
 
 type Compressor struct {
@@ -35,25 +43,46 @@ type Compressor struct {
 	indexer *Indexer
 	ticker  *time.Ticker
 
+	codec       Codec
+	chunkSize   uint64
+	keepOrphans bool
+
 	tickerDuration time.Duration
 	log            log15.Logger
 }
 
-func NewCompressor(chain Chain, dataDir string) *Compressor {
+// NewCompressor writes the ledger files compressed by codec, every file contains chunkSize snapshot blocks
+// and the task runs every interval. The default values are used if codec is nil or the others are zero.
+// The files which are not in the index are deleted unless keepOrphans is true.
+func NewCompressor(chain Chain, dataDir string, codec Codec, chunkSize uint64, interval time.Duration, keepOrphans bool) *Compressor {
+	if codec == nil {
+		codec = noneCodec{}
+	}
+	if chunkSize == 0 {
+		chunkSize = DefaultChunkSize
+	}
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
 	c := &Compressor{
 		stopSignal: make(chan int),
 		status:     STOPPED,
 		chain:      chain,
 		dir:        filepath.Join(dataDir, "ledger_files"),
 
-		tickerDuration: time.Minute * 10,
+		codec:       codec,
+		chunkSize:   chunkSize,
+		keepOrphans: keepOrphans,
+
+		tickerDuration: interval,
 		log:            log15.New("module", "compressor"),
 	}
 
 	if err := c.createDataDir(); err != nil {
 		c.log.Crit("Create data directory failed, error is "+err.Error(), "method", "NewCompressor")
 	}
-	c.indexer = NewIndexer(c.dir)
+	c.indexer = NewIndexer(c.dir, c.keepOrphans)
 	return c
 }
 
@@ -86,7 +115,7 @@ func (c *Compressor) ClearData() error {
 		return errors.New("Create data directory failed, error is " + err.Error())
 	}
 
-	c.indexer = NewIndexer(c.dir)
+	c.indexer = NewIndexer(c.dir, c.keepOrphans)
 	return nil
 }
 
@@ -94,8 +123,23 @@ func (c *Compressor) Indexer() *Indexer {
 	return c.indexer
 }
 
+func (c *Compressor) Dir() string {
+	return c.dir
+}
+
+// FileReader returns the raw bytes of the file, they're decompressed by the codec in the meta of the file
 func (c *Compressor) FileReader(filename string) io.ReadCloser {
-	return NewFileReader(path.Join(c.dir, filename))
+	return NewFileReader(path.Join(c.dir, filename), noneCodec{})
+}
+
+// DecompressedFileReader returns the decompressed bytes of the file, it's for the peers which don't support the codecs
+func (c *Compressor) DecompressedFileReader(filename string) io.ReadCloser {
+	fullName := path.Join(c.dir, filename)
+	codec, err := GetCodec(detectFileCodec(fullName))
+	if err != nil {
+		return nil
+	}
+	return NewFileReader(fullName, codec)
 }
 
 func (c *Compressor) BlockParser(reader io.Reader, blockNum uint64, processFunc func(block ledger.Block, err error)) {
@@ -144,10 +188,10 @@ func (c *Compressor) RunTask() {
 
 	c.status = TASK_RUNNING
 
-	tmpFileName := filepath.Join(c.dir, "subgraph_tmp")
-	task := NewCompressorTask(c.chain, tmpFileName, c.indexer.LatestHeight())
+	tmpFileName := filepath.Join(c.dir, taskTmpFileName)
+	task := NewCompressorTask(c.chain, tmpFileName, c.indexer.LatestHeight(), c.codec, c.chunkSize)
 	if result := task.Run(); result.IsSuccess {
-		c.indexer.Add(result.Ti, tmpFileName, result.BlockNumbers)
+		c.indexer.Add(result.Ti, tmpFileName, result.BlockNumbers, c.codec.Name())
 	}
 	task.Clear()
 
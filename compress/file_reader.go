@@ -8,7 +8,21 @@ import (
 
 var fileReaderLog = log15.New("module", "file_reader")
 
-func NewFileReader(filename string) io.ReadCloser {
+type fileReader struct {
+	io.ReadCloser
+	file *os.File
+}
+
+func (r *fileReader) Close() error {
+	err := r.ReadCloser.Close()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// NewFileReader opens the file, the data read is decompressed by codec
+func NewFileReader(filename string, codec Codec) io.ReadCloser {
 	file, err := os.Open(filename)
 
 	if err != nil {
@@ -16,5 +30,8 @@ func NewFileReader(filename string) io.ReadCloser {
 		return nil
 	}
 
-	return file
+	return &fileReader{
+		ReadCloser: codec.NewReader(file),
+		file:       file,
+	}
 }
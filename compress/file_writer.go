@@ -8,7 +8,21 @@ import (
 
 var fileWriterLog = log15.New("module", "file_writer")
 
-func NewFileWriter(filename string) io.WriteCloser {
+type fileWriter struct {
+	io.WriteCloser
+	file *os.File
+}
+
+func (w *fileWriter) Close() error {
+	err := w.WriteCloser.Close()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// NewFileWriter creates the file, the data written is compressed by codec
+func NewFileWriter(filename string, codec Codec) io.WriteCloser {
 	file, err := os.Create(filename)
 
 	if err != nil {
@@ -16,5 +30,8 @@ func NewFileWriter(filename string) io.WriteCloser {
 		return nil
 	}
 
-	return file
+	return &fileWriter{
+		WriteCloser: codec.NewWriter(file),
+		file:        file,
+	}
 }
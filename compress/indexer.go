@@ -3,6 +3,8 @@ package compress
 import (
 	"bufio"
 	"fmt"
+	"github.com/pkg/errors"
+	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
 	"io"
@@ -26,7 +28,8 @@ type Indexer struct {
 	dir string
 }
 
-func NewIndexer(dir string) *Indexer {
+// NewIndexer loads the index in dir, the data files which are not in the index are deleted unless keepOrphans is true.
+func NewIndexer(dir string, keepOrphans bool) *Indexer {
 	indexFileName := path.Join(dir, "index")
 	var file *os.File
 	var oErr error
@@ -55,7 +58,7 @@ func NewIndexer(dir string) *Indexer {
 		}
 	}
 
-	indexer.checkAndDeleteDataFile()
+	indexer.checkAndDeleteDataFile(keepOrphans)
 
 	return indexer
 }
@@ -111,7 +114,9 @@ func (indexer *Indexer) check(item *ledger.CompressedFileMeta) (bool, error) {
 	return true, nil
 }
 
-func (indexer *Indexer) checkAndDeleteDataFile() {
+// checkAndDeleteDataFile deletes the data files which are not in the index, they are only warned about if keepOrphans
+// is true and kept for `gvite ledger-files repair`, a file of the same heights created by the compressor later replaces it.
+func (indexer *Indexer) checkAndDeleteDataFile(keepOrphans bool) {
 	indexListMap := make(map[string]int, 0)
 
 	for _, item := range indexer.indexList {
@@ -119,8 +124,12 @@ func (indexer *Indexer) checkAndDeleteDataFile() {
 	}
 	allFileNames := indexer.getAllFileNames()
 	for _, fileName := range allFileNames {
-		if strings.HasPrefix(fileName, "subgraph") {
+		if strings.HasPrefix(fileName, "subgraph") && fileName != taskTmpFileName {
 			if value := indexListMap[fileName]; value == 0 {
+				if keepOrphans {
+					indexer.log.Warn("Ledger file "+fileName+" is not in the index, run `gvite ledger-files repair` to check it", "method", "checkAndDeleteDataFile")
+					continue
+				}
 				indexer.delete(filepath.Join(indexer.dir, fileName))
 			}
 		}
@@ -129,8 +138,9 @@ func (indexer *Indexer) checkAndDeleteDataFile() {
 
 func (indexer *Indexer) flushToFile() {
 	indexer.file.Truncate(0)
+	indexer.file.Seek(0, io.SeekStart)
 	for _, indexItem := range indexer.indexList {
-		_, err := indexer.file.WriteString(indexer.formatToLine(indexItem) + "\n")
+		_, err := indexer.file.WriteString(formatIndexLine(indexItem) + "\n")
 		if err != nil {
 			indexer.log.Error("WriteString failed, error is "+err.Error(), "method", "flushToFile")
 			time.Sleep(time.Second)
@@ -160,6 +170,15 @@ func (indexer *Indexer) getAllFileNames() []string {
 	return allFileName
 }
 
+// Reset replaces the index with the list, it's used when the index is rebuilt.
+func (indexer *Indexer) Reset(indexList []*ledger.CompressedFileMeta) {
+	indexer.lock.Lock()
+	defer indexer.lock.Unlock()
+
+	indexer.indexList = indexList
+	indexer.flushToFile()
+}
+
 func (indexer *Indexer) truncate(n int64) error {
 	return indexer.file.Truncate(n)
 }
@@ -195,7 +214,7 @@ func (indexer *Indexer) loadFromFile() (int64, error) {
 			break
 		}
 
-		item, parseErr := indexer.parseLine(line)
+		item, parseErr := parseIndexLine(line)
 		if parseErr != nil {
 			indexer.log.Error("ParseLine failed, error is "+parseErr.Error(), "method", "loadFromFile")
 			return hasParsedSize, parseErr
@@ -203,6 +222,8 @@ func (indexer *Indexer) loadFromFile() (int64, error) {
 		if isCorrect, err := indexer.check(item); !isCorrect {
 			if err != nil {
 				indexer.log.Error("Check failed, error is "+err.Error(), "method", "loadFromFile")
+			} else {
+				indexer.log.Error("Size of ledger file "+item.Filename+" mismatches the index, the index is truncated before it", "method", "loadFromFile")
 			}
 
 			return hasParsedSize, err
@@ -216,8 +237,11 @@ func (indexer *Indexer) loadFromFile() (int64, error) {
 	return hasParsedSize, nil
 }
 
-func (indexer *Indexer) parseLine(line []byte) (*ledger.CompressedFileMeta, error) {
+func parseIndexLine(line []byte) (*ledger.CompressedFileMeta, error) {
 	segs := strings.Split(string(line), INDEX_SEP)
+	if len(segs) < 5 {
+		return nil, errors.New(fmt.Sprintf("index line %q is broken", line))
+	}
 
 	startHeight, err1 := strconv.ParseUint(segs[0], 10, 64)
 	if err1 != nil {
@@ -225,7 +249,7 @@ func (indexer *Indexer) parseLine(line []byte) (*ledger.CompressedFileMeta, erro
 	}
 
 	endHeight, err2 := strconv.ParseUint(segs[1], 10, 64)
-	if err1 != nil {
+	if err2 != nil {
 		return nil, err2
 	}
 
@@ -248,16 +272,40 @@ func (indexer *Indexer) parseLine(line []byte) (*ledger.CompressedFileMeta, erro
 		FileSize:     fileSize,
 		BlockNumbers: blockNumbers,
 	}
+
+	// The checksum and the codec are appended later, the old lines have no checksum or codec
+	if len(segs) > 5 && segs[5] != "" {
+		checksum, err5 := types.HexToHash(segs[5])
+		if err5 != nil {
+			return nil, err5
+		}
+		item.Checksum = &checksum
+	}
+	if len(segs) > 6 {
+		if _, err6 := GetCodec(segs[6]); err6 != nil {
+			return nil, err6
+		}
+		item.Codec = segs[6]
+	}
 	return item, nil
 }
 
-func (indexer *Indexer) formatToLine(item *ledger.CompressedFileMeta) string {
+func formatIndexLine(item *ledger.CompressedFileMeta) string {
 	lineString := ""
 	lineString += strconv.FormatUint(item.StartHeight, 10) + INDEX_SEP
 	lineString += strconv.FormatUint(item.EndHeight, 10) + INDEX_SEP
 	lineString += item.Filename + INDEX_SEP
 	lineString += strconv.FormatInt(item.FileSize, 10) + INDEX_SEP
 	lineString += strconv.FormatUint(item.BlockNumbers, 10)
+	if item.Checksum != nil || item.Codec != "" {
+		lineString += INDEX_SEP
+		if item.Checksum != nil {
+			lineString += item.Checksum.String()
+		}
+	}
+	if item.Codec != "" {
+		lineString += INDEX_SEP + item.Codec
+	}
 	return lineString
 }
 
@@ -352,7 +400,7 @@ func (indexer *Indexer) Delete(toHeight uint64) error {
 	return nil
 }
 
-func (indexer *Indexer) Add(ti *taskInfo, tmpFile string, blockNumbers uint64) error {
+func (indexer *Indexer) Add(ti *taskInfo, tmpFile string, blockNumbers uint64, codec string) error {
 	indexer.lock.Lock()
 	defer indexer.lock.Unlock()
 
@@ -383,6 +431,12 @@ func (indexer *Indexer) Add(ti *taskInfo, tmpFile string, blockNumbers uint64) e
 		return fileSizeErr
 	}
 
+	checksum, checksumErr := FileChecksum(newAbsoluteFileName)
+	if checksumErr != nil {
+		indexer.log.Error("FileChecksum failed, error is "+checksumErr.Error(), "method", "Add")
+		return checksumErr
+	}
+
 	newItem := &ledger.CompressedFileMeta{
 		StartHeight: ti.beginHeight,
 		EndHeight:   ti.targetHeight,
@@ -391,9 +445,11 @@ func (indexer *Indexer) Add(ti *taskInfo, tmpFile string, blockNumbers uint64) e
 
 		FileSize:     fileSize,
 		BlockNumbers: blockNumbers,
+		Checksum:     checksum,
+		Codec:        codec,
 	}
 
-	_, writeErr := indexer.file.WriteString(indexer.formatToLine(newItem) + "\n")
+	_, writeErr := indexer.file.WriteString(formatIndexLine(newItem) + "\n")
 
 	if writeErr != nil {
 		return writeErr
@@ -8,4 +8,5 @@ import (
 type Chain interface {
 	GetConfirmSubLedger(fromHeight uint64, toHeight uint64) ([]*ledger.SnapshotBlock, map[types.Address][]*ledger.AccountBlock, error)
 	GetLatestSnapshotBlock() *ledger.SnapshotBlock
+	PrunedHeight() uint64
 }
@@ -39,6 +39,7 @@ func (ti *taskInfo) Split(gap uint64) []*taskInfo {
 }
 
 type CompressorTask struct {
+	codec          Codec
 	splitSize      uint64
 	tmpFile        string
 	chain          Chain
@@ -48,16 +49,22 @@ type CompressorTask struct {
 	log            log15.Logger
 }
 
-func NewCompressorTask(chain Chain, tmpFile string, indexerHeight uint64) *CompressorTask {
+func NewCompressorTask(chain Chain, tmpFile string, indexerHeight uint64, codec Codec, taskGap uint64) *CompressorTask {
+	startHeightGap := uint64(7200)
+	if startHeightGap < taskGap {
+		startHeightGap = taskGap
+	}
+
 	compressorTask := &CompressorTask{
+		codec:     codec,
 		splitSize: 10,
 		chain:     chain,
 		tmpFile:   tmpFile,
 		log:       log15.New("module", "compressor/task"),
 
 		indexerHeight:  indexerHeight,
-		startHeightGap: 7200,
-		taskGap:        3600,
+		startHeightGap: startHeightGap,
+		taskGap:        taskGap,
 	}
 
 	return compressorTask
@@ -80,12 +87,17 @@ func (task *CompressorTask) Run() *TaskRunResult {
 		}
 	}
 
+	return task.run(ti)
+}
+
+// run writes the blocks between the heights of ti to the tmp file
+func (task *CompressorTask) run(ti *taskInfo) *TaskRunResult {
 	taskInfoList := ti.Split(task.splitSize)
 
 	taskLen := len(taskInfoList)
 	currentTaskIndex := 0
 
-	tmpFileWriter := NewFileWriter(task.tmpFile)
+	tmpFileWriter := NewFileWriter(task.tmpFile, task.codec)
 	var blockNumbers = uint64(0)
 
 	// Limit write length
@@ -104,7 +116,9 @@ func (task *CompressorTask) Run() *TaskRunResult {
 		return blocks, err
 	})
 
-	tmpFileWriter.Close()
+	if closeErr := tmpFileWriter.Close(); formatterErr == nil {
+		formatterErr = closeErr
+	}
 
 	if formatterErr != nil {
 		task.log.Error("Block write failed, error is "+formatterErr.Error(), "method", "Run")
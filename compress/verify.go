@@ -0,0 +1,502 @@
+package compress
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+const (
+	ProblemIndex     = "index"
+	ProblemGap       = "gap"
+	ProblemMissing   = "missing"
+	ProblemTruncated = "truncated"
+	ProblemChecksum  = "checksum"
+	ProblemCorrupt   = "corrupt"
+	ProblemLinkage   = "linkage"
+	ProblemUnindexed = "unindexed"
+)
+
+type FileProblem struct {
+	Kind        string
+	Filename    string
+	StartHeight uint64
+	EndHeight   uint64
+	Detail      string
+}
+
+func (p *FileProblem) String() string {
+	return fmt.Sprintf("[%s] %s(%d-%d): %s", p.Kind, p.Filename, p.StartHeight, p.EndHeight, p.Detail)
+}
+
+// SnapshotBlockGetter returns the snapshot block of the snapshot chain, the blocks in the files are checked against it.
+type SnapshotBlockGetter func(height uint64) (*ledger.SnapshotBlock, error)
+
+type RepairResult struct {
+	// the files which are re-generated from the chain db
+	Regenerated []string
+	// the problems which can't be repaired, the index ends before them
+	Unrepaired []*FileProblem
+
+	IndexedHeight uint64
+}
+
+// FileChecksum returns the sha256 of the file
+func FileChecksum(filename string) (*types.Hash, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return nil, err
+	}
+
+	checksum, err := types.BytesToHash(hasher.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return &checksum, nil
+}
+
+// ReadIndex reads the index of the ledger files without any check, the broken lines are reported as problems.
+func ReadIndex(dir string) ([]*ledger.CompressedFileMeta, []*FileProblem, error) {
+	file, err := os.Open(filepath.Join(dir, "index"))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var indexList []*ledger.CompressedFileMeta
+	var problems []*FileProblem
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Bytes()
+		if len(line) <= 0 {
+			continue
+		}
+
+		item, err := parseIndexLine(line)
+		if err != nil {
+			problems = append(problems, &FileProblem{
+				Kind:     ProblemIndex,
+				Filename: "index",
+				Detail:   fmt.Sprintf("line %d is broken, error is %s", lineNumber, err),
+			})
+			continue
+		}
+		indexList = append(indexList, item)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return indexList, problems, nil
+}
+
+// Verify re-parses every ledger file in the index, recomputes the block hashes and checks the linkage of the
+// snapshot blocks against the former file and the snapshot chain. getSnapshotBlock can be nil.
+func Verify(dir string, getSnapshotBlock SnapshotBlockGetter) ([]*FileProblem, error) {
+	indexList, problems, err := ReadIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	indexed := make(map[string]struct{}, len(indexList))
+	expectedHeight := uint64(1)
+	var prevSnapshotBlock *ledger.SnapshotBlock
+	for _, item := range indexList {
+		indexed[item.Filename] = struct{}{}
+
+		if item.StartHeight > expectedHeight {
+			problems = append(problems, &FileProblem{
+				Kind:        ProblemGap,
+				StartHeight: expectedHeight,
+				EndHeight:   item.StartHeight - 1,
+				Detail:      "no file contains the snapshot blocks",
+			})
+		} else if item.StartHeight < expectedHeight {
+			problems = append(problems, &FileProblem{
+				Kind:        ProblemGap,
+				Filename:    item.Filename,
+				StartHeight: item.StartHeight,
+				EndHeight:   item.EndHeight,
+				Detail:      fmt.Sprintf("overlaps with the former file which ends at %d", expectedHeight-1),
+			})
+		}
+
+		if prevSnapshotBlock != nil && prevSnapshotBlock.Height+1 != item.StartHeight {
+			prevSnapshotBlock = nil
+		}
+
+		var fileProblems []*FileProblem
+		prevSnapshotBlock, fileProblems, _ = checkFile(dir, item, prevSnapshotBlock, getSnapshotBlock)
+		problems = append(problems, fileProblems...)
+
+		if item.EndHeight >= expectedHeight {
+			expectedHeight = item.EndHeight + 1
+		}
+	}
+
+	for _, item := range scanDataFiles(dir) {
+		if _, ok := indexed[item.Filename]; !ok {
+			problems = append(problems, &FileProblem{
+				Kind:        ProblemUnindexed,
+				Filename:    item.Filename,
+				StartHeight: item.StartHeight,
+				EndHeight:   item.EndHeight,
+				Detail:      "the file is not in the index",
+			})
+		}
+	}
+	return problems, nil
+}
+
+// checkFile returns the last snapshot block in the file, the problems and the count of the parsed blocks.
+// The size, the block numbers and the checksum are only checked when they are set in item.
+func checkFile(dir string, item *ledger.CompressedFileMeta, prevSnapshotBlock *ledger.SnapshotBlock,
+	getSnapshotBlock SnapshotBlockGetter) (*ledger.SnapshotBlock, []*FileProblem, uint64) {
+	var problems []*FileProblem
+	addProblem := func(kind string, detail string) {
+		problems = append(problems, &FileProblem{
+			Kind:        kind,
+			Filename:    item.Filename,
+			StartHeight: item.StartHeight,
+			EndHeight:   item.EndHeight,
+			Detail:      detail,
+		})
+	}
+
+	filename := filepath.Join(dir, item.Filename)
+	fileInfo, err := os.Stat(filename)
+	if err != nil {
+		addProblem(ProblemMissing, err.Error())
+		return nil, problems, 0
+	}
+
+	if item.FileSize > 0 && fileInfo.Size() != item.FileSize {
+		addProblem(ProblemTruncated, fmt.Sprintf("size is %d, %d in the index", fileInfo.Size(), item.FileSize))
+	}
+
+	if item.Checksum != nil {
+		checksum, err := FileChecksum(filename)
+		if err != nil {
+			addProblem(ProblemMissing, err.Error())
+			return nil, problems, 0
+		}
+		if *checksum != *item.Checksum {
+			addProblem(ProblemChecksum, fmt.Sprintf("checksum is %s, %s in the index", checksum, item.Checksum))
+		}
+	}
+
+	codec, err := GetCodec(item.Codec)
+	if err != nil {
+		addProblem(ProblemCorrupt, err.Error())
+		return nil, problems, 0
+	}
+	file := NewFileReader(filename, codec)
+	if file == nil {
+		addProblem(ProblemMissing, "open file failed")
+		return nil, problems, 0
+	}
+	defer file.Close()
+
+	var snapshotBlocks []*ledger.SnapshotBlock
+	accountBlocks := make(map[types.Address][]*ledger.AccountBlock)
+	blockNumbers := uint64(0)
+	corruptNumbers := 0
+	BlockParser(file, 0, func(block ledger.Block, err error) {
+		blockNumbers++
+		if err != nil {
+			corruptNumbers++
+			if corruptNumbers == 1 {
+				addProblem(ProblemCorrupt, fmt.Sprintf("block %d can't be parsed, error is %s", blockNumbers, err))
+			}
+			return
+		}
+
+		switch block.(type) {
+		case *ledger.SnapshotBlock:
+			snapshotBlock := block.(*ledger.SnapshotBlock)
+			if snapshotBlock.ComputeHash() != snapshotBlock.Hash {
+				addProblem(ProblemCorrupt, fmt.Sprintf("hash of snapshot block %d mismatches", snapshotBlock.Height))
+			}
+			snapshotBlocks = append(snapshotBlocks, snapshotBlock)
+		case *ledger.AccountBlock:
+			accountBlock := block.(*ledger.AccountBlock)
+			if accountBlock.ComputeHash() != accountBlock.Hash {
+				addProblem(ProblemCorrupt, fmt.Sprintf("hash of account block %s mismatches", accountBlock.Hash))
+			}
+			accountBlocks[accountBlock.AccountAddress] = append(accountBlocks[accountBlock.AccountAddress], accountBlock)
+		}
+	})
+	if corruptNumbers > 1 {
+		addProblem(ProblemCorrupt, fmt.Sprintf("%d blocks can't be parsed", corruptNumbers))
+	}
+
+	if item.BlockNumbers > 0 && blockNumbers != item.BlockNumbers {
+		addProblem(ProblemTruncated, fmt.Sprintf("%d blocks are parsed, %d in the index", blockNumbers, item.BlockNumbers))
+	}
+
+	// The snapshot blocks are continuous and linked with the former file and the snapshot chain
+	expectedHeight := item.StartHeight
+	for _, snapshotBlock := range snapshotBlocks {
+		if snapshotBlock.Height != expectedHeight {
+			addProblem(ProblemLinkage, fmt.Sprintf("snapshot block %d is expected, but got %d", expectedHeight, snapshotBlock.Height))
+			return nil, problems, blockNumbers
+		}
+		if prevSnapshotBlock != nil && snapshotBlock.PrevHash != prevSnapshotBlock.Hash {
+			addProblem(ProblemLinkage, fmt.Sprintf("snapshot block %d is not linked to the former one", snapshotBlock.Height))
+		}
+
+		if getSnapshotBlock != nil {
+			chainBlock, err := getSnapshotBlock(snapshotBlock.Height)
+			if err != nil {
+				addProblem(ProblemLinkage, fmt.Sprintf("snapshot block %d can't be read from the snapshot chain, error is %s", snapshotBlock.Height, err))
+				return nil, problems, blockNumbers
+			}
+			if chainBlock != nil && chainBlock.Hash != snapshotBlock.Hash {
+				addProblem(ProblemLinkage, fmt.Sprintf("snapshot block %d mismatches the snapshot chain", snapshotBlock.Height))
+			}
+		}
+
+		prevSnapshotBlock = snapshotBlock
+		expectedHeight++
+	}
+	if expectedHeight != item.EndHeight+1 {
+		addProblem(ProblemTruncated, fmt.Sprintf("snapshot blocks end at %d", expectedHeight-1))
+		return nil, problems, blockNumbers
+	}
+
+	for addr, blocks := range accountBlocks {
+		sort.Slice(blocks, func(i, j int) bool {
+			return blocks[i].Height < blocks[j].Height
+		})
+		for i := 1; i < len(blocks); i++ {
+			if blocks[i].Height == blocks[i-1].Height+1 && blocks[i].PrevHash != blocks[i-1].Hash {
+				addProblem(ProblemLinkage, fmt.Sprintf("account block %d of %s is not linked to the former one", blocks[i].Height, addr))
+			}
+		}
+	}
+
+	return prevSnapshotBlock, problems, blockNumbers
+}
+
+// scanDataFiles returns the ledger files in dir sorted by the start height, the heights are parsed from the file names.
+func scanDataFiles(dir string) []*ledger.CompressedFileMeta {
+	dirFile, err := os.Open(dir)
+	if err != nil {
+		return nil
+	}
+	defer dirFile.Close()
+
+	fileNames, err := dirFile.Readdirnames(0)
+	if err != nil {
+		return nil
+	}
+
+	var items []*ledger.CompressedFileMeta
+	for _, fileName := range fileNames {
+		segs := strings.Split(fileName, "_")
+		if len(segs) != 3 || segs[0] != "subgraph" {
+			continue
+		}
+
+		startHeight, err1 := strconv.ParseUint(segs[1], 10, 64)
+		endHeight, err2 := strconv.ParseUint(segs[2], 10, 64)
+		if err1 != nil || err2 != nil || startHeight > endHeight {
+			continue
+		}
+
+		items = append(items, &ledger.CompressedFileMeta{
+			StartHeight: startHeight,
+			EndHeight:   endHeight,
+			Filename:    fileName,
+			Codec:       detectFileCodec(filepath.Join(dir, fileName)),
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].StartHeight == items[j].StartHeight {
+			return items[i].EndHeight > items[j].EndHeight
+		}
+		return items[i].StartHeight < items[j].StartHeight
+	})
+	return items
+}
+
+func detectFileCodec(filename string) string {
+	file, err := os.Open(filename)
+	if err != nil {
+		return CodecNone
+	}
+	defer file.Close()
+
+	header := make([]byte, len(snappyMagic))
+	n, _ := io.ReadFull(file, header)
+	return DetectCodec(header[:n])
+}
+
+// Repair rebuilds the index from the ledger files on the disk, the broken files and the gaps are re-generated from
+// the chain db. The index ends before the first problem which can't be repaired, e.g. the ledger is pruned.
+func (c *Compressor) Repair(getSnapshotBlock SnapshotBlockGetter) (*RepairResult, error) {
+	c.statusLock.Lock()
+	defer c.statusLock.Unlock()
+	if c.status != STOPPED {
+		return nil, errors.New("compressor is running")
+	}
+
+	files := scanDataFiles(c.dir)
+	result := &RepairResult{}
+
+	var indexList []*ledger.CompressedFileMeta
+	var prevSnapshotBlock *ledger.SnapshotBlock
+	expectedHeight := uint64(1)
+	for {
+		var candidates []*ledger.CompressedFileMeta
+		var nextStartHeight uint64
+		for _, item := range files {
+			if item.StartHeight == expectedHeight {
+				candidates = append(candidates, item)
+			} else if item.StartHeight > expectedHeight && nextStartHeight == 0 {
+				nextStartHeight = item.StartHeight
+			}
+		}
+
+		// Reuse the file on the disk if it's right
+		var item *ledger.CompressedFileMeta
+		var lastSnapshotBlock *ledger.SnapshotBlock
+		for _, candidate := range candidates {
+			var problems []*FileProblem
+			var blockNumbers uint64
+			lastSnapshotBlock, problems, blockNumbers = checkFile(c.dir, candidate, prevSnapshotBlock, getSnapshotBlock)
+			if len(problems) > 0 || lastSnapshotBlock == nil {
+				continue
+			}
+
+			if err := completeFileMeta(c.dir, candidate, blockNumbers); err != nil {
+				return nil, err
+			}
+			item = candidate
+			break
+		}
+
+		if item == nil {
+			var endHeight uint64
+			switch {
+			case len(candidates) > 0:
+				endHeight = candidates[0].EndHeight
+			case nextStartHeight > 0:
+				endHeight = nextStartHeight - 1
+			default:
+				// All the files are indexed, the compressor goes on from here
+				c.indexer.Reset(indexList)
+				result.IndexedHeight = expectedHeight - 1
+				return result, nil
+			}
+
+			var problem *FileProblem
+			item, lastSnapshotBlock, problem = c.regenerate(expectedHeight, endHeight, prevSnapshotBlock, getSnapshotBlock)
+			if problem != nil {
+				result.Unrepaired = append(result.Unrepaired, problem)
+				c.indexer.Reset(indexList)
+				result.IndexedHeight = expectedHeight - 1
+				return result, nil
+			}
+			result.Regenerated = append(result.Regenerated, item.Filename)
+		}
+
+		indexList = append(indexList, item)
+		prevSnapshotBlock = lastSnapshotBlock
+		expectedHeight = item.EndHeight + 1
+	}
+}
+
+// regenerate writes the blocks between the heights to a new file from the chain db
+func (c *Compressor) regenerate(startHeight, endHeight uint64, prevSnapshotBlock *ledger.SnapshotBlock,
+	getSnapshotBlock SnapshotBlockGetter) (*ledger.CompressedFileMeta, *ledger.SnapshotBlock, *FileProblem) {
+	task := NewCompressorTask(c.chain, filepath.Join(c.dir, taskTmpFileName), startHeight-1, c.codec, c.chunkSize)
+	if endHeight >= startHeight+task.taskGap {
+		endHeight = startHeight + task.taskGap - 1
+	}
+
+	problem := &FileProblem{
+		Kind:        ProblemGap,
+		Filename:    c.indexer.newFileName(startHeight, endHeight),
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+	}
+	if prunedHeight := c.chain.PrunedHeight(); prunedHeight >= startHeight {
+		problem.Detail = fmt.Sprintf("the ledger is pruned to %d, the file can't be re-generated", prunedHeight)
+		return nil, nil, problem
+	}
+
+	defer task.Clear()
+	taskResult := task.run(&taskInfo{
+		beginHeight:  startHeight,
+		targetHeight: endHeight,
+	})
+	if !taskResult.IsSuccess {
+		problem.Detail = "re-generate the file from the chain db failed"
+		return nil, nil, problem
+	}
+
+	item := &ledger.CompressedFileMeta{
+		StartHeight:  startHeight,
+		EndHeight:    endHeight,
+		Filename:     taskTmpFileName,
+		BlockNumbers: taskResult.BlockNumbers,
+		Codec:        c.codec.Name(),
+	}
+	lastSnapshotBlock, problems, _ := checkFile(c.dir, item, prevSnapshotBlock, getSnapshotBlock)
+	if len(problems) > 0 || lastSnapshotBlock == nil {
+		problem.Detail = "the re-generated file is broken"
+		if len(problems) > 0 {
+			problem.Detail += ", " + problems[0].Detail
+		}
+		return nil, nil, problem
+	}
+
+	item.Filename = problem.Filename
+	if err := os.Rename(filepath.Join(c.dir, taskTmpFileName), filepath.Join(c.dir, item.Filename)); err != nil {
+		problem.Detail = err.Error()
+		return nil, nil, problem
+	}
+	if err := completeFileMeta(c.dir, item, taskResult.BlockNumbers); err != nil {
+		problem.Detail = err.Error()
+		return nil, nil, problem
+	}
+	return item, lastSnapshotBlock, nil
+}
+
+func completeFileMeta(dir string, item *ledger.CompressedFileMeta, blockNumbers uint64) error {
+	filename := filepath.Join(dir, item.Filename)
+	fileInfo, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	checksum, err := FileChecksum(filename)
+	if err != nil {
+		return err
+	}
+
+	item.FileSize = fileInfo.Size()
+	item.BlockNumbers = blockNumbers
+	item.Checksum = checksum
+	return nil
+}
@@ -0,0 +1,149 @@
+package compress
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+func writeTestLedgerFile(t *testing.T, dir string, startHeight, endHeight uint64) *ledger.CompressedFileMeta {
+	now := time.Unix(1541000000, 0)
+
+	var blocks []ledger.Block
+	var prevHash types.Hash
+	for height := startHeight; height <= endHeight; height++ {
+		snapshotBlock := &ledger.SnapshotBlock{
+			Height:    height,
+			PrevHash:  prevHash,
+			Timestamp: &now,
+		}
+		snapshotBlock.Hash = snapshotBlock.ComputeHash()
+		prevHash = snapshotBlock.Hash
+		blocks = append(blocks, snapshotBlock)
+	}
+
+	item := &ledger.CompressedFileMeta{
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+		Filename:    fmt.Sprintf("subgraph_%d_%d", startHeight, endHeight),
+	}
+	file, err := os.Create(filepath.Join(dir, item.Filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := BlockFormatter(file, func(uint64, uint64) ([]ledger.Block, error) {
+		return blocks, io.EOF
+	}); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	if err := completeFileMeta(dir, item, uint64(len(blocks))); err != nil {
+		t.Fatal(err)
+	}
+	return item
+}
+
+func writeTestIndex(t *testing.T, dir string, items ...*ledger.CompressedFileMeta) {
+	content := ""
+	for _, item := range items {
+		content += formatIndexLine(item) + "\n"
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "index"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func hasProblem(problems []*FileProblem, kind string) bool {
+	for _, problem := range problems {
+		if problem.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func TestIndexLine(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ledger_files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	item := writeTestLedgerFile(t, dir, 1, 5)
+	parsed, err := parseIndexLine([]byte(formatIndexLine(item)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.StartHeight != 1 || parsed.EndHeight != 5 || parsed.Filename != item.Filename ||
+		parsed.FileSize != item.FileSize || parsed.BlockNumbers != 5 || *parsed.Checksum != *item.Checksum {
+		t.Fatalf("unexpected index line %+v", parsed)
+	}
+
+	// the index lines written before the checksum
+	parsed, err = parseIndexLine([]byte("1,,,5,,,subgraph_1_5,,,100,,,5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Checksum != nil {
+		t.Fatal("checksum should be nil")
+	}
+
+	if _, err := parseIndexLine([]byte("1,,,5")); err == nil {
+		t.Fatal("broken line should fail")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ledger_files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	item1 := writeTestLedgerFile(t, dir, 1, 5)
+	item2 := writeTestLedgerFile(t, dir, 8, 10)
+	writeTestIndex(t, dir, item1, item2)
+
+	problems, err := Verify(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 1 || problems[0].Kind != ProblemGap || problems[0].StartHeight != 6 || problems[0].EndHeight != 7 {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+
+	// the problems are kept when the snapshot chain can't be read
+	problems, err = Verify(dir, func(uint64) (*ledger.SnapshotBlock, error) {
+		return nil, errors.New("chain db is closed")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasProblem(problems, ProblemLinkage) || !hasProblem(problems, ProblemGap) {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+
+	// truncate the first file
+	filename := filepath.Join(dir, item1.Filename)
+	if err := os.Truncate(filename, item1.FileSize-10); err != nil {
+		t.Fatal(err)
+	}
+	writeTestIndex(t, dir, item1)
+
+	problems, err = Verify(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasProblem(problems, ProblemTruncated) || !hasProblem(problems, ProblemChecksum) || !hasProblem(problems, ProblemUnindexed) {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+}
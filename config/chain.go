@@ -5,9 +5,30 @@ type KafkaProducer struct {
 	Topic      string
 }
 
+// EventSink exports the same event stream as KafkaProducer to a file or a webhook,
+// Type is one of "file" and "webhook", Target is the file path or the url.
+type EventSink struct {
+	Type   string
+	Target string
+}
+
 type Chain struct {
 	KafkaProducers []*KafkaProducer
+	EventSinks     []*EventSink
 	OpenBlackBlock bool
+	OpenLogIndex   bool
+	OpenTxIndex    bool
 	LedgerGcRetain uint64
-	GenesisFile    string
+	// 0 means the ledger is never pruned
+	LedgerPruneRetain uint64
+	GenesisFile       string
+
+	// codec of the ledger files, one of none, snappy and zstd
+	LedgerFilesCodec string
+	// count of snapshot blocks in one ledger file, 0 means the default
+	LedgerFilesChunkSize uint64
+	// seconds between the runs of the compressor, 0 means the default
+	LedgerFilesInterval int64
+	// keep the ledger files which are not in the index for `gvite ledger-files repair`, they're deleted on start by default
+	LedgerFilesKeepOrphans bool
 }
@@ -9,6 +9,7 @@ type Config struct {
 	*Chain    `json:"Chain"`
 	*Vm       `json:"Vm"`
 	*Net      `json:"Net"`
+	*Watchdog `json:"Watchdog"`
 
 	// global keys
 	DataDir string `json:"DataDir"`
@@ -19,3 +20,8 @@ type Config struct {
 func (c Config) RunLogDir() string {
 	return filepath.Join(c.DataDir, "runlog")
 }
+
+// ProducerProtectionFile records the last signed slots of the producer
+func (c Config) ProducerProtectionFile() string {
+	return filepath.Join(c.DataDir, "producer_protection.json")
+}
@@ -7,4 +7,9 @@ type Net struct {
 	Topic        string   `json:"Topic"`
 	Interval     int64    `json:"Interval"`
 	TopoDisabled bool     `json:"TopoDisabled"`
+
+	// FileHttpAddr is the listen address of the http ledger file server, it's disabled if empty
+	FileHttpAddr string `json:"FileHttpAddr"`
+	// LedgerMirrors are the urls of the http ledger file servers to download the ledger files from
+	LedgerMirrors []string `json:"LedgerMirrors"`
 }
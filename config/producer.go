@@ -4,6 +4,18 @@ type Producer struct {
 	Producer         bool   `json:"Producer"`
 	Coinbase         string `json:"Coinbase"`
 	EntropyStorePath string `json:"EntropyStorePath"`
+
+	// SignerEndpoint is the ipc endpoint of a remote signer daemon,
+	// the keys of the coinbase stay in the node if it is empty
+	SignerEndpoint string `json:"SignerEndpoint"`
+
+	// LeaseListen enables the hot-standby mode, the nodes holding the same coinbase send heartbeats
+	// to the LeasePeers and only the holder of the lease produces. The heartbeats are authenticated
+	// with the LeaseSecret shared by the nodes. The nodes must sign through the same SignerEndpoint,
+	// whose protection record keeps both of them from signing a slot while they can not hear each other
+	LeaseListen string   `json:"LeaseListen"`
+	LeasePeers  []string `json:"LeasePeers"`
+	LeaseSecret string   `json:"LeaseSecret"`
 }
 
 //func MergeMinerConfig(cfg *Miner) *Miner {
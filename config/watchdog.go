@@ -0,0 +1,21 @@
+package config
+
+// Watchdog notifies the operators by the webhooks and the local command when a producer misses its slots,
+// the synchronization stalls or the head of the chain gets old, the zero values mean the defaults
+type Watchdog struct {
+	Enabled bool `json:"Enabled"`
+	// Producers are the addresses whose slots are watched, the coinbase is watched if it's empty
+	Producers []string `json:"Producers"`
+	Webhooks  []string `json:"Webhooks"`
+	// Command is run without a shell, the program and its arguments are separated by spaces
+	Command string `json:"Command"`
+
+	// seconds between the checks
+	CheckInterval int64 `json:"CheckInterval"`
+	// consecutive missed slots of a producer to fire an alert
+	MissedSlots int `json:"MissedSlots"`
+	// seconds the latest snapshot block may be old
+	HeadAge int64 `json:"HeadAge"`
+	// seconds the sync may not be done
+	SyncTimeout int64 `json:"SyncTimeout"`
+}
@@ -407,6 +407,7 @@ func newConsensusEvent(r *electionResult, p *core.MemberPlan, gid types.Gid) Eve
 		Address:        p.Member,
 		Stime:          p.STime,
 		Etime:          p.ETime,
+		Index:          r.Index,
 		Timestamp:      p.STime,
 		SnapshotHash:   r.Hash,
 		SnapshotHeight: r.Height,
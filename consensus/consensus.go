@@ -18,6 +18,7 @@ type Event struct {
 	Address types.Address
 	Stime   time.Time
 	Etime   time.Time
+	Index   uint64 // election index of the slot
 
 	Timestamp      time.Time  // add to block
 	SnapshotHash   types.Hash // add to block
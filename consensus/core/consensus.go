@@ -20,6 +20,7 @@ type Detail struct {
 type PeriodDetails struct {
 	ActualNum uint64 // actual block num in period
 	VoteMap   map[string]*big.Int
+	VoterMap  map[types.Address]*big.Int // balances of the voters of the register at the vote time
 }
 
 type ConsensusReader interface {
@@ -75,7 +76,7 @@ func (self *reader) VoteDetails(startIndex, endIndex uint64,
 	memAllPlanNum := uint64(0)
 	memAllActualNum := uint64(0)
 	for i := startIndex; i <= endIndex; i++ {
-		votes, finalVotes, err := self.voteDetail(i, r)
+		votes, finalVotes, voters, err := self.voteDetail(i, register.Name, r)
 		if err != nil {
 			return nil, err
 		}
@@ -98,21 +99,22 @@ func (self *reader) VoteDetails(startIndex, endIndex uint64,
 		periodM[i] = &PeriodDetails{
 			ActualNum: actualNum,
 			VoteMap:   voteM,
+			VoterMap:  voters,
 		}
 	}
 	return &Detail{PlanNum: memAllPlanNum, ActualNum: memAllActualNum, PeriodM: periodM}, nil
 }
 
-func (self *reader) voteDetail(index uint64,
-	r stateCh) ([]*Vote, []*Vote, error) {
+func (self *reader) voteDetail(index uint64, name string,
+	r stateCh) ([]*Vote, []*Vote, map[types.Address]*big.Int, error) {
 	voteTime := self.info.GenVoteTime(index)
 	block, err := r.GetSnapshotBlockBeforeTime(&voteTime)
 
 	hashH := ledger.HashHeight{Hash: block.Hash, Height: block.Height}
 
-	votes, err := CalVotes(self.info, hashH, r)
+	votes, voters, err := calVotesAndVoters(self.info, hashH, name, r)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	// top
 	topVotes := self.ag.FilterSimple(votes)
@@ -120,7 +122,7 @@ func (self *reader) voteDetail(index uint64,
 	finalVotes := self.ag.FilterVotes(votes, &hashH)
 	// shuffle the members
 	finalVotes = self.ag.ShuffleVotes(finalVotes, &hashH)
-	return topVotes, finalVotes, nil
+	return topVotes, finalVotes, voters, nil
 }
 
 func (self *reader) actualSnapshotBlockNum(index uint64, register *types.Registration, r stateCh) (uint64, uint64, error) {
@@ -31,19 +31,38 @@ func CalVotes(info *GroupInfo, block ledger.HashHeight, rw stateCh) ([]*Vote, er
 	}
 	return registers, nil
 }
+
+// calVotesAndVoters calculates the votes like CalVotes, and the balances of the voters of the registration with the name
+func calVotesAndVoters(info *GroupInfo, block ledger.HashHeight, name string, rw stateCh) ([]*Vote, map[types.Address]*big.Int, error) {
+	registerList, _ := rw.GetRegisterList(block.Hash, info.Gid)
+	votes, _ := rw.GetVoteMap(block.Hash, info.Gid)
+
+	var registers []*Vote
+	for _, v := range registerList {
+		registers = append(registers, GenVote(block.Hash, v, votes, info.CountingTokenId, rw))
+	}
+	return registers, GenVoterBalances(block.Hash, name, votes, info.CountingTokenId, rw), nil
+}
+
 func GenVote(snapshotHash types.Hash, registration *types.Registration, infos []*types.VoteInfo, id types.TokenTypeId, rw stateCh) *Vote {
+	result := &Vote{Balance: big.NewInt(0), Name: registration.Name, Addr: registration.NodeAddr}
+	for _, v := range GenVoterBalances(snapshotHash, registration.Name, infos, id, rw) {
+		result.Balance.Add(result.Balance, v)
+	}
+	return result
+}
+
+// GenVoterBalances returns the balances of the voters of the registration with the name
+func GenVoterBalances(snapshotHash types.Hash, name string, infos []*types.VoteInfo, id types.TokenTypeId, rw stateCh) map[types.Address]*big.Int {
 	var addrs []types.Address
 	for _, v := range infos {
-		if v.NodeName == registration.Name {
+		if v.NodeName == name {
 			addrs = append(addrs, v.VoterAddr)
 		}
 	}
-	result := &Vote{Balance: big.NewInt(0), Name: registration.Name, Addr: registration.NodeAddr}
-	if len(addrs) > 0 {
-		balanceMap, _ := rw.GetBalanceList(snapshotHash, id, addrs)
-		for _, v := range balanceMap {
-			result.Balance.Add(result.Balance, v)
-		}
+	if len(addrs) == 0 {
+		return nil
 	}
-	return result
+	balanceMap, _ := rw.GetBalanceList(snapshotHash, id, addrs)
+	return balanceMap
 }
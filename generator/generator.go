@@ -47,6 +47,11 @@ func NewGenerator(chain vm_context.Chain, snapshotBlockHash, prevBlockHash *type
 	return gen, nil
 }
 
+// SetUnlimitedQuota makes the vm skip the quota of the account, the blocks generated can only be used to simulate
+func (gen *Generator) SetUnlimitedQuota(unlimited bool) {
+	gen.vm.UnlimitedQuota = unlimited
+}
+
 func (gen *Generator) GenerateWithMessage(message *IncomingMessage, signFunc SignFunc) (*GenResult, error) {
 	var genResult *GenResult
 	var errGenMsg error
@@ -2,6 +2,7 @@ package ledger
 
 import (
 	"github.com/golang/protobuf/proto"
+	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/vitepb"
 )
 
@@ -13,6 +14,12 @@ type CompressedFileMeta struct {
 	FileSize int64
 
 	BlockNumbers uint64
+
+	// sha256 of the file, nil for the files created before the checksum is recorded
+	Checksum *types.Hash
+
+	// codec of the file, empty for the files created before the codec is recorded, they're not compressed
+	Codec string
 }
 
 func (f *CompressedFileMeta) Serialize() ([]byte, error) {
@@ -32,13 +39,18 @@ func (f *CompressedFileMeta) Deserialize(buf []byte) error {
 }
 
 func (f *CompressedFileMeta) Proto() *vitepb.CompressedFileMeta {
-	return &vitepb.CompressedFileMeta{
+	pb := &vitepb.CompressedFileMeta{
 		StartHeight:  f.StartHeight,
 		EndHeight:    f.EndHeight,
 		Filename:     f.Filename,
 		FileSize:     f.FileSize,
 		BlockNumbers: f.BlockNumbers,
+		Codec:        f.Codec,
 	}
+	if f.Checksum != nil {
+		pb.Checksum = f.Checksum.Bytes()
+	}
+	return pb
 }
 
 func (f *CompressedFileMeta) Deproto(pb *vitepb.CompressedFileMeta) {
@@ -47,4 +59,9 @@ func (f *CompressedFileMeta) Deproto(pb *vitepb.CompressedFileMeta) {
 	f.Filename = pb.Filename
 	f.FileSize = pb.FileSize
 	f.BlockNumbers = pb.BlockNumbers
+	f.Codec = pb.Codec
+	if len(pb.Checksum) > 0 {
+		checksum, _ := types.BytesToHash(pb.Checksum)
+		f.Checksum = &checksum
+	}
 }
@@ -26,10 +26,23 @@ type Config struct {
 	// template：["broker1,broker2,...|topic",""]
 	KafkaProducers []string `json:"KafkaProducers"`
 
+	// template：["file|/path/to/events.ndjson","webhook|http://host/path"]
+	EventSinks []string `json:"EventSinks"`
+
 	// chain
-	OpenBlackBlock bool   `json:"OpenBlackBlock"`
-	LedgerGcRetain uint64 `json:"LedgerGcRetain"`
-	GenesisFile    string `json:"GenesisFile"`
+	OpenBlackBlock    bool   `json:"OpenBlackBlock"`
+	OpenLogIndex      bool   `json:"OpenLogIndex"`
+	OpenTxIndex       bool   `json:"OpenTxIndex"`
+	LedgerGcRetain    uint64 `json:"LedgerGcRetain"`
+	LedgerPruneRetain uint64 `json:"LedgerPruneRetain"`
+	GenesisFile       string `json:"GenesisFile"`
+
+	// ledger files
+	LedgerFilesCodec     string `json:"LedgerFilesCodec"`
+	LedgerFilesChunkSize uint64 `json:"LedgerFilesChunkSize"`
+	LedgerFilesInterval  int64  `json:"LedgerFilesInterval"`
+
+	LedgerFilesKeepOrphans bool `json:"LedgerFilesKeepOrphans"`
 
 	// p2p
 	NetSelect            string
@@ -45,11 +58,25 @@ type Config struct {
 	Discovery            bool     `json:"Discovery"`
 
 	//producer
-	EntropyStorePath     string `json:"EntropyStorePath"`
-	EntropyStorePassword string `json:"EntropyStorePassword"`
-	CoinBase             string `json:"CoinBase"`
-	MinerEnabled         bool   `json:"Miner"`
-	MinerInterval        int    `json:"MinerInterval"`
+	EntropyStorePath     string   `json:"EntropyStorePath"`
+	EntropyStorePassword string   `json:"EntropyStorePassword"`
+	CoinBase             string   `json:"CoinBase"`
+	MinerEnabled         bool     `json:"Miner"`
+	MinerInterval        int      `json:"MinerInterval"`
+	SignerEndpoint       string   `json:"SignerEndpoint"`
+	LeaseListen          string   `json:"LeaseListen"`
+	LeasePeers           []string `json:"LeasePeers"`
+	LeaseSecret          string   `json:"LeaseSecret"`
+
+	//watchdog
+	WatchdogEnabled       bool     `json:"WatchdogEnabled"`
+	WatchdogProducers     []string `json:"WatchdogProducers"`
+	WatchdogWebhooks      []string `json:"WatchdogWebhooks"`
+	WatchdogCommand       string   `json:"WatchdogCommand"`
+	WatchdogCheckInterval int64    `json:"WatchdogCheckInterval"`
+	WatchdogMissedSlots   int      `json:"WatchdogMissedSlots"`
+	WatchdogHeadAge       int64    `json:"WatchdogHeadAge"`
+	WatchdogSyncTimeout   int64    `json:"WatchdogSyncTimeout"`
 
 	//rpc
 	RPCEnabled bool `json:"RPCEnabled"`
@@ -88,6 +115,8 @@ type Config struct {
 	TopologyTopic          string   `json:"TopologyTopic"`
 	TopologyReportInterval int      `json:"TopologyReportInterval"`
 	TopoDisabled           bool     `json:"TopoDisabled"`
+	FileHttpAddr           string   `json:"FileHttpAddr"`
+	LedgerMirrors          []string `json:"LedgerMirrors"`
 	DashboardTargetURL     string
 }
 
@@ -95,6 +124,11 @@ func (c *Config) makeWalletConfig() *wallet.Config {
 	return &wallet.Config{DataDir: c.KeyStoreDir}
 }
 
+// ViteConfig is used by the commands which open the chain without starting the node
+func (c *Config) ViteConfig() *config.Config {
+	return c.makeViteConfig()
+}
+
 func (c *Config) makeViteConfig() *config.Config {
 	return &config.Config{
 		Chain:    c.makeChainConfig(),
@@ -102,10 +136,24 @@ func (c *Config) makeViteConfig() *config.Config {
 		DataDir:  c.DataDir,
 		Net:      c.makeNetConfig(),
 		Vm:       c.makeVmConfig(),
+		Watchdog: c.makeWatchdogConfig(),
 		LogLevel: c.LogLevel,
 	}
 }
 
+func (c *Config) makeWatchdogConfig() *config.Watchdog {
+	return &config.Watchdog{
+		Enabled:       c.WatchdogEnabled,
+		Producers:     c.WatchdogProducers,
+		Webhooks:      c.WatchdogWebhooks,
+		Command:       c.WatchdogCommand,
+		CheckInterval: c.WatchdogCheckInterval,
+		MissedSlots:   c.WatchdogMissedSlots,
+		HeadAge:       c.WatchdogHeadAge,
+		SyncTimeout:   c.WatchdogSyncTimeout,
+	}
+}
+
 func (c *Config) makeNetConfig() *config.Net {
 	return &config.Net{
 		Single:       c.Single,
@@ -114,6 +162,9 @@ func (c *Config) makeNetConfig() *config.Net {
 		Topic:        c.TopologyTopic,
 		Interval:     int64(c.TopologyReportInterval),
 		TopoDisabled: c.TopoDisabled,
+
+		FileHttpAddr:  c.FileHttpAddr,
+		LedgerMirrors: c.LedgerMirrors,
 	}
 }
 
@@ -129,6 +180,10 @@ func (c *Config) makeMinerConfig() *config.Producer {
 		Producer:         c.MinerEnabled,
 		Coinbase:         c.CoinBase,
 		EntropyStorePath: c.EntropyStorePath,
+		SignerEndpoint:   c.SignerEndpoint,
+		LeaseListen:      c.LeaseListen,
+		LeasePeers:       c.LeasePeers,
+		LeaseSecret:      c.LeaseSecret,
 	}
 }
 
@@ -148,14 +203,41 @@ func (c *Config) makeP2PConfig() *p2p.Config {
 	}
 }
 
+func (c *Config) makeEventSinks() []*config.EventSink {
+	var eventSinks []*config.EventSink
+	for _, eventSink := range c.EventSinks {
+		splitEventSink := strings.SplitN(eventSink, "|", 2)
+		if len(splitEventSink) != 2 || splitEventSink[1] == "" {
+			log.Warn(fmt.Sprintf("EventSinks is setting error，The program will skip %s and continue processing", eventSink))
+			continue
+		}
+
+		eventSinks = append(eventSinks, &config.EventSink{
+			Type:   splitEventSink[0],
+			Target: splitEventSink[1],
+		})
+	}
+	return eventSinks
+}
+
 func (c *Config) makeChainConfig() *config.Chain {
 
 	if len(c.KafkaProducers) == 0 {
 		return &config.Chain{
-			KafkaProducers: nil,
-			OpenBlackBlock: c.OpenBlackBlock,
-			LedgerGcRetain: c.LedgerGcRetain,
-			GenesisFile:    c.GenesisFile,
+			KafkaProducers:    nil,
+			EventSinks:        c.makeEventSinks(),
+			OpenBlackBlock:    c.OpenBlackBlock,
+			OpenLogIndex:      c.OpenLogIndex,
+			OpenTxIndex:       c.OpenTxIndex,
+			LedgerGcRetain:    c.LedgerGcRetain,
+			LedgerPruneRetain: c.LedgerPruneRetain,
+			GenesisFile:       c.GenesisFile,
+
+			LedgerFilesCodec:     c.LedgerFilesCodec,
+			LedgerFilesChunkSize: c.LedgerFilesChunkSize,
+			LedgerFilesInterval:  c.LedgerFilesInterval,
+
+			LedgerFilesKeepOrphans: c.LedgerFilesKeepOrphans,
 		}
 	}
 
@@ -182,10 +264,20 @@ func (c *Config) makeChainConfig() *config.Chain {
 	}
 END:
 	return &config.Chain{
-		KafkaProducers: kafkaProducers,
-		OpenBlackBlock: c.OpenBlackBlock,
-		LedgerGcRetain: c.LedgerGcRetain,
-		GenesisFile:    c.GenesisFile,
+		KafkaProducers:    kafkaProducers,
+		EventSinks:        c.makeEventSinks(),
+		OpenBlackBlock:    c.OpenBlackBlock,
+		OpenLogIndex:      c.OpenLogIndex,
+		OpenTxIndex:       c.OpenTxIndex,
+		LedgerGcRetain:    c.LedgerGcRetain,
+		LedgerPruneRetain: c.LedgerPruneRetain,
+		GenesisFile:       c.GenesisFile,
+
+		LedgerFilesCodec:     c.LedgerFilesCodec,
+		LedgerFilesChunkSize: c.LedgerFilesChunkSize,
+		LedgerFilesInterval:  c.LedgerFilesInterval,
+
+		LedgerFilesKeepOrphans: c.LedgerFilesKeepOrphans,
 	}
 }
 
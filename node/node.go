@@ -390,6 +390,9 @@ func (node *Node) stopRPC() error {
 	node.stopWS()
 	node.stopHTTP()
 	node.stopIPC()
+	if node.viteServer != nil {
+		rpcapi.Stop(node.viteServer)
+	}
 	return nil
 }
 
@@ -10,17 +10,17 @@ import (
 
 //In-proc apis
 func (node *Node) GetInProcessApis() []rpc.API {
-	return rpcapi.GetApis(node.viteServer, "ledger", "wallet", "private_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "testapi", "pow", "tx")
+	return rpcapi.GetApis(node.viteServer, "ledger", "wallet", "private_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "multisig", "vesting", "producer", "consensusGroup", "consensus", "watchdog", "testapi", "pow", "tx", "subscribe")
 }
 
 //Ipc apis
 func (node *Node) GetIpcApis() []rpc.API {
-	return rpcapi.GetApis(node.viteServer, "ledger", "wallet", "private_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "testapi", "pow", "tx")
+	return rpcapi.GetApis(node.viteServer, "ledger", "wallet", "private_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "multisig", "vesting", "producer", "consensusGroup", "consensus", "watchdog", "testapi", "pow", "tx", "subscribe")
 }
 
 //Http apis
 func (node *Node) GetHttpApis() []rpc.API {
-	apiModules := []string{"ledger", "public_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "pow", "tx"}
+	apiModules := []string{"ledger", "public_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "multisig", "vesting", "consensusGroup", "consensus", "pow", "tx"}
 	if node.Config().NetID > 1 {
 		apiModules = append(apiModules, "testapi")
 	}
@@ -29,7 +29,7 @@ func (node *Node) GetHttpApis() []rpc.API {
 
 //WS apis
 func (node *Node) GetWSApis() []rpc.API {
-	apiModules := []string{"ledger", "public_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "pow", "tx"}
+	apiModules := []string{"ledger", "public_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "multisig", "vesting", "consensusGroup", "consensus", "pow", "tx"}
 	if node.Config().NetID > 1 {
 		apiModules = append(apiModules, "testapi")
 	}
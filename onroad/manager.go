@@ -12,6 +12,7 @@ import (
 	"github.com/vitelabs/go-vite/log15"
 	"github.com/vitelabs/go-vite/onroad/model"
 	"github.com/vitelabs/go-vite/producer/producerevent"
+	"github.com/vitelabs/go-vite/signer"
 	"github.com/vitelabs/go-vite/vite/net"
 	"github.com/vitelabs/go-vite/vm_context"
 	"github.com/vitelabs/go-vite/wallet"
@@ -29,6 +30,7 @@ type Manager struct {
 	chain    chain.Chain
 	producer Producer
 	wallet   *wallet.Manager
+	signer   signer.Signer
 
 	uAccess          *model.UAccess
 	onroadBlocksPool *model.OnroadBlocksPool
@@ -54,6 +56,7 @@ func NewManager(net Net, pool Pool, producer Producer, wallet *wallet.Manager) *
 		net:                net,
 		producer:           producer,
 		wallet:             wallet,
+		signer:             signer.NewLocalSigner(wallet),
 		autoReceiveWorkers: make(map[types.Address]*AutoReceiveWorker),
 		contractWorkers:    make(map[types.Gid]*ContractWorker),
 		log:                slog.New("w", "manager"),
@@ -63,6 +66,11 @@ func NewManager(net Net, pool Pool, producer Producer, wallet *wallet.Manager) *
 	return m
 }
 
+// SetSigner replaces the signer of the contract blocks, which defaults to the keys unlocked in the wallet
+func (manager *Manager) SetSigner(sn signer.Signer) {
+	manager.signer = sn
+}
+
 func (manager *Manager) Init(chain chain.Chain) {
 	manager.uAccess.Init(chain)
 	manager.chain = chain
@@ -146,7 +154,7 @@ func (manager *Manager) producerStartEventFunc(accevent producerevent.AccountEve
 		return
 	}
 
-	if !manager.wallet.GlobalCheckAddrUnlock(event.Address) {
+	if err := manager.signer.CheckAddress(event.Address); err != nil {
 		manager.log.Error("receive a right event but address locked", "event", event)
 		return
 	}
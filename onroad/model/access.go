@@ -8,6 +8,7 @@ import (
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/vm/contracts"
 )
 
 const (
@@ -39,7 +40,8 @@ func (access *UAccess) GetContractAddrListByGid(gid *types.Gid) ([]types.Address
 	}
 
 	if *gid == types.DELEGATE_GID {
-		addrList = append(addrList, types.PrecompiledContractAddressList...)
+		// the contracts activated at a fork height are ordinary accounts before it
+		addrList = append(addrList, contracts.PrecompiledContractAddressList(access.Chain.GetLatestSnapshotBlock().Height)...)
 	}
 	return addrList, nil
 }
@@ -169,18 +169,20 @@ func (tp *ContractTaskProcessor) processOneAddress(task *contractTask) {
 		return
 	}
 
-	genResult, err := gen.GenerateWithOnroad(*sBlock, consensusMessage,
-		func(addr types.Address, data []byte) (signedData, pubkey []byte, err error) {
-			_, key, _, err := tp.worker.manager.wallet.GlobalFindAddr(addr)
-			if err != nil {
-				return nil, nil, err
-			}
-			return key.SignData(data)
-		}, nil)
+	genResult, err := gen.GenerateWithOnroad(*sBlock, consensusMessage, nil, nil)
 	if err != nil {
 		plog.Error("GenerateWithOnroad failed", "error", err)
 		return
 	}
+	// the receive block is signed by the producer, the signer hashes the block itself
+	if len(genResult.BlockGenList) > 0 {
+		block := genResult.BlockGenList[0].AccountBlock
+		block.Signature, block.PublicKey, err = tp.worker.manager.signer.SignAccountBlock(consensusMessage.Producer, block)
+		if err != nil {
+			plog.Error("SignAccountBlock failed", "error", err)
+			return
+		}
+	}
 
 	if genResult.Err != nil {
 		plog.Error("vm.Run error, ignore", "error", genResult.Err)
@@ -0,0 +1,332 @@
+package producer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vitelabs/go-vite/common"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/log15"
+)
+
+// the nodes holding the same coinbase send heartbeats to each other, only the holder of the lease produces blocks.
+// a standby takes the lease if no leader is heard within the timeout, which is shorter than a snapshot slot.
+// the heartbeats are authenticated with a secret shared by the nodes. the lease can not prevent two leaders
+// while the nodes can not hear each other, the nodes must sign through the same signer daemon, whose protection
+// refuses to sign a second block for a slot.
+const (
+	leaseInterval = 200 * time.Millisecond
+	leaseTimeout  = 3 * leaseInterval
+	leaseGrace    = 2 * leaseTimeout // a started node waits for the heartbeats of the peers before taking the lease
+)
+
+type heartbeat struct {
+	Coinbase types.Address `json:"coinbase"`
+	Id       string        `json:"id"`
+	Leader   bool          `json:"leader"`
+	Since    int64         `json:"since"`
+	Time     int64         `json:"time"`
+	Mac      []byte        `json:"mac"`
+}
+
+type LeasePeer struct {
+	Id       string `json:"id"`
+	Addr     string `json:"addr"`
+	Leader   bool   `json:"leader"`
+	Since    int64  `json:"since"`
+	LastSeen int64  `json:"lastSeen"`
+}
+
+type LeaseStatus struct {
+	Id     string       `json:"id"`
+	Listen string       `json:"listen"`
+	Leader bool         `json:"leader"`
+	Since  int64        `json:"since"`
+	Peers  []*LeasePeer `json:"peers"`
+}
+
+type lease struct {
+	coinbase types.Address
+	id       string
+	listen   string
+	peers    []string
+	secret   []byte
+	interval time.Duration
+	timeout  time.Duration
+	grace    time.Duration
+	onChange func(leader bool)
+
+	mu       sync.Mutex
+	leader   bool
+	since    time.Time
+	started  time.Time
+	seen     map[string]*LeasePeer
+	listener net.Listener
+	closed   chan struct{}
+	wg       sync.WaitGroup
+	log      log15.Logger
+}
+
+func newLease(coinbase types.Address, listen string, peers []string, secret string) *lease {
+	id := types.DataHash([]byte(listen + time.Now().String())).String()[:16]
+	return &lease{
+		coinbase: coinbase,
+		id:       id,
+		listen:   listen,
+		peers:    peers,
+		secret:   []byte(secret),
+		interval: leaseInterval,
+		timeout:  leaseTimeout,
+		grace:    leaseGrace,
+		log:      log15.New("module", "producer/lease", "id", id),
+	}
+}
+
+// start listens for the heartbeats of the peers, the node starts as a standby,
+// onChange is called from a single goroutine whenever the node takes or loses the lease
+func (l *lease) start(onChange func(leader bool)) error {
+	if len(l.secret) == 0 {
+		return errors.New("lease secret is required")
+	}
+	listener, err := net.Listen("tcp", l.listen)
+	if err != nil {
+		return errors.Wrap(err, "lease listen fail")
+	}
+	l.listener = listener
+	l.onChange = onChange
+	l.leader = false
+	l.started = time.Now()
+	l.seen = make(map[string]*LeasePeer)
+	l.closed = make(chan struct{})
+
+	l.wg.Add(1)
+	common.Go(l.accept)
+	for _, peer := range l.peers {
+		tmpPeer := peer
+		l.wg.Add(1)
+		common.Go(func() {
+			l.send(tmpPeer)
+		})
+	}
+	l.wg.Add(1)
+	common.Go(l.loop)
+	l.log.Info("lease started.", "listen", l.listen, "peers", l.peers)
+	return nil
+}
+
+func (l *lease) stop() {
+	close(l.closed)
+	l.listener.Close()
+	l.wg.Wait()
+
+	l.mu.Lock()
+	l.leader = false
+	l.mu.Unlock()
+	l.log.Info("lease stopped.")
+}
+
+func (l *lease) isLeader() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.leader
+}
+
+func (l *lease) status() *LeaseStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := &LeaseStatus{Id: l.id, Listen: l.listen, Leader: l.leader}
+	if l.leader {
+		s.Since = l.since.UnixNano() / 1e6
+	}
+	for _, p := range l.seen {
+		peer := *p
+		peer.Since = peer.Since / 1e6
+		peer.LastSeen = peer.LastSeen / 1e6
+		s.Peers = append(s.Peers, &peer)
+	}
+	return s
+}
+
+func (l *lease) loop() {
+	defer l.wg.Done()
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.closed:
+			return
+		case now := <-ticker.C:
+			if changed, leader := l.check(now); changed {
+				l.onChange(leader)
+			}
+		}
+	}
+}
+
+// check takes the lease if no leader is alive after the startup grace period and no alive standby
+// has a smaller id, and gives it up if a leader holding it longer is seen, which happens after
+// the network between the nodes recovers
+func (l *lease) check(now time.Time) (changed bool, leader bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var other *LeasePeer
+	preferred := true
+	for _, p := range l.seen {
+		if now.UnixNano()-p.LastSeen >= int64(l.timeout) {
+			continue
+		}
+		if !p.Leader {
+			if p.Id < l.id {
+				preferred = false
+			}
+			continue
+		}
+		if other == nil || p.Since < other.Since || (p.Since == other.Since && p.Id < other.Id) {
+			other = p
+		}
+	}
+
+	if l.leader {
+		since := l.since.UnixNano()
+		if other != nil && (other.Since < since || (other.Since == since && other.Id < l.id)) {
+			l.log.Warn("give up the lease.", "leader", other.Id, "addr", other.Addr)
+			l.leader = false
+			return true, false
+		}
+		return false, true
+	}
+	if other == nil && preferred && now.Sub(l.started) >= l.grace {
+		l.log.Warn("take the lease, no leader is alive.")
+		l.leader = true
+		l.since = now
+		return true, true
+	}
+	return false, false
+}
+
+func (l *lease) heartbeat() *heartbeat {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	hb := &heartbeat{Coinbase: l.coinbase, Id: l.id, Leader: l.leader, Time: time.Now().UnixNano()}
+	if l.leader {
+		hb.Since = l.since.UnixNano()
+	}
+	hb.Mac = l.mac(hb)
+	return hb
+}
+
+// mac is the HMAC-SHA256 of the heartbeat without the mac, keyed by the shared secret
+func (l *lease) mac(hb *heartbeat) []byte {
+	tmp := *hb
+	tmp.Mac = nil
+	data, _ := json.Marshal(&tmp)
+	h := hmac.New(sha256.New, l.secret)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// verify checks the mac of the heartbeat, and that it is sent within the timeout so that it can not be replayed later
+func (l *lease) verify(hb *heartbeat, now time.Time) bool {
+	if !hmac.Equal(hb.Mac, l.mac(hb)) {
+		return false
+	}
+	age := now.UnixNano() - hb.Time
+	return age < int64(l.timeout) && age > -int64(l.timeout)
+}
+
+func (l *lease) accept() {
+	defer l.wg.Done()
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			select {
+			case <-l.closed:
+				return
+			default:
+			}
+			l.log.Error("lease accept fail.", "err", err)
+			time.Sleep(l.interval)
+			continue
+		}
+		l.wg.Add(1)
+		common.Go(func() {
+			l.receive(conn)
+		})
+	}
+}
+
+func (l *lease) receive(conn net.Conn) {
+	defer l.wg.Done()
+	defer conn.Close()
+
+	dec := json.NewDecoder(conn)
+	for {
+		select {
+		case <-l.closed:
+			return
+		default:
+		}
+		conn.SetReadDeadline(time.Now().Add(l.timeout))
+		hb := new(heartbeat)
+		if err := dec.Decode(hb); err != nil {
+			return
+		}
+		now := time.Now()
+		if !l.verify(hb, now) {
+			l.log.Error("ignore the heartbeat with an invalid mac or time.", "id", hb.Id, "addr", conn.RemoteAddr())
+			return
+		}
+		if hb.Coinbase != l.coinbase || hb.Id == l.id {
+			l.log.Error("ignore the heartbeat of another producer.", "coinbase", hb.Coinbase, "id", hb.Id, "addr", conn.RemoteAddr())
+			return
+		}
+		l.mu.Lock()
+		l.seen[hb.Id] = &LeasePeer{
+			Id:       hb.Id,
+			Addr:     conn.RemoteAddr().String(),
+			Leader:   hb.Leader,
+			Since:    hb.Since,
+			LastSeen: now.UnixNano(),
+		}
+		l.mu.Unlock()
+	}
+}
+
+func (l *lease) send(peer string) {
+	defer l.wg.Done()
+	for {
+		conn, err := net.DialTimeout("tcp", peer, l.interval)
+		if err == nil {
+			l.sendTo(conn)
+			conn.Close()
+		}
+		select {
+		case <-l.closed:
+			return
+		case <-time.After(l.interval):
+		}
+	}
+}
+
+func (l *lease) sendTo(conn net.Conn) {
+	enc := json.NewEncoder(conn)
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		conn.SetWriteDeadline(time.Now().Add(l.interval))
+		if err := enc.Encode(l.heartbeat()); err != nil {
+			return
+		}
+		select {
+		case <-l.closed:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package producer
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/common/types"
+)
+
+type testLeaseNode struct {
+	*lease
+	mu      sync.Mutex
+	changes []bool
+}
+
+func newTestLeaseNode(coinbase types.Address, listen string, peers ...string) *testLeaseNode {
+	n := &testLeaseNode{lease: newLease(coinbase, listen, peers, "secret")}
+	n.interval = 20 * time.Millisecond
+	n.timeout = 3 * n.interval
+	n.grace = 2 * n.timeout
+	return n
+}
+
+func (n *testLeaseNode) start(t *testing.T) {
+	err := n.lease.start(func(leader bool) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		n.changes = append(n.changes, leader)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitLeader(t *testing.T, n *testLeaseNode, leader bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if n.isLeader() == leader {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("lease %s is not leader:%v", n.id, leader)
+}
+
+func TestLease(t *testing.T) {
+	coinbase, _, _ := types.CreateAddress()
+	a := newTestLeaseNode(coinbase, "127.0.0.1:48481", "127.0.0.1:48482")
+	b := newTestLeaseNode(coinbase, "127.0.0.1:48482", "127.0.0.1:48481")
+
+	a.start(t)
+	waitLeader(t, a, true)
+	b.start(t)
+
+	// the standby keeps following an alive leader
+	time.Sleep(5 * b.timeout)
+	if b.isLeader() {
+		t.Fatal("standby takes the lease of an alive leader")
+	}
+	if s := b.status(); len(s.Peers) != 1 || s.Peers[0].Id != a.id || !s.Peers[0].Leader {
+		t.Fatalf("unexpected status of the standby %+v", s)
+	}
+
+	// the standby takes over after the leader goes silent
+	a.stop()
+	waitLeader(t, b, true)
+	b.stop()
+
+	if len(a.changes) != 1 || !a.changes[0] {
+		t.Fatalf("unexpected lease changes of the leader %v", a.changes)
+	}
+	if len(b.changes) != 1 || !b.changes[0] {
+		t.Fatalf("unexpected lease changes of the standby %v", b.changes)
+	}
+}
+
+func TestLeaseConflict(t *testing.T) {
+	coinbase, _, _ := types.CreateAddress()
+	a := newTestLeaseNode(coinbase, "127.0.0.1:48483", "127.0.0.1:48484")
+	b := newTestLeaseNode(coinbase, "127.0.0.1:48484", "127.0.0.1:48483")
+
+	// both nodes take the lease while they can not hear each other
+	now := time.Now()
+	a.started, b.started = now.Add(-a.grace), now.Add(-b.grace)
+	a.seen, b.seen = make(map[string]*LeasePeer), make(map[string]*LeasePeer)
+	a.check(now)
+	b.check(now.Add(time.Millisecond))
+	if !a.isLeader() || !b.isLeader() {
+		t.Fatal("expected both nodes to hold the lease")
+	}
+
+	// the node holding the lease longer keeps it
+	b.seen[a.id] = &LeasePeer{Id: a.id, Leader: true, Since: a.since.UnixNano(), LastSeen: now.UnixNano()}
+	if changed, leader := b.check(now.Add(2 * time.Millisecond)); !changed || leader {
+		t.Fatal("expected the later leader to give up the lease")
+	}
+	a.seen[b.id] = &LeasePeer{Id: b.id, Leader: true, Since: b.since.UnixNano(), LastSeen: now.UnixNano()}
+	if changed, leader := a.check(now.Add(2 * time.Millisecond)); changed || !leader {
+		t.Fatal("expected the earlier leader to keep the lease")
+	}
+}
+
+func TestLeaseSimultaneousStart(t *testing.T) {
+	coinbase, _, _ := types.CreateAddress()
+	a := newTestLeaseNode(coinbase, "127.0.0.1:48485", "127.0.0.1:48486")
+	b := newTestLeaseNode(coinbase, "127.0.0.1:48486", "127.0.0.1:48485")
+	first, second := a, b
+	if b.id < a.id {
+		first, second = b, a
+	}
+
+	// the standby with the smaller id takes the lease after the grace period
+	a.start(t)
+	b.start(t)
+	waitLeader(t, first, true)
+	time.Sleep(5 * second.timeout)
+	a.stop()
+	b.stop()
+
+	if len(second.changes) != 0 {
+		t.Fatalf("unexpected lease changes of the standby %v", second.changes)
+	}
+}
+
+func TestLeaseHeartbeatMac(t *testing.T) {
+	coinbase, _, _ := types.CreateAddress()
+	a := newTestLeaseNode(coinbase, "127.0.0.1:48487")
+	b := newTestLeaseNode(coinbase, "127.0.0.1:48488")
+	hb := a.heartbeat()
+	now := time.Unix(0, hb.Time)
+	if !b.verify(hb, now) {
+		t.Fatal("expected the heartbeat to be verified")
+	}
+	if b.verify(hb, now.Add(b.timeout)) {
+		t.Fatal("a stale heartbeat is verified")
+	}
+
+	hb.Leader = true
+	if b.verify(hb, now) {
+		t.Fatal("a forged heartbeat is verified")
+	}
+
+	c := &testLeaseNode{lease: newLease(coinbase, "127.0.0.1:48489", nil, "another secret")}
+	if b.verify(c.heartbeat(), now) {
+		t.Fatal("the heartbeat of another secret is verified")
+	}
+}
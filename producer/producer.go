@@ -12,9 +12,9 @@ import (
 	"github.com/vitelabs/go-vite/log15"
 	"github.com/vitelabs/go-vite/pool"
 	"github.com/vitelabs/go-vite/producer/producerevent"
+	"github.com/vitelabs/go-vite/signer"
 	"github.com/vitelabs/go-vite/verifier"
 	"github.com/vitelabs/go-vite/vite/net"
-	"github.com/vitelabs/go-vite/wallet"
 )
 
 // Package producer implements vite block creation
@@ -33,6 +33,14 @@ type Producer interface {
 	Start() error
 	Stop() error
 	GetCoinBase() types.Address
+	Status() *Status
+}
+
+// Status shows whether the node is producing, a standby of a hot-standby pair is not
+type Status struct {
+	Coinbase  types.Address `json:"coinbase"`
+	Producing bool          `json:"producing"`
+	Lease     *LeaseStatus  `json:"lease"`
 }
 
 // Backend wraps all methods required for mining.
@@ -79,6 +87,8 @@ type producer struct {
 	accountFn            func(producerevent.AccountEvent)
 	syncState            net.SyncState
 	netSyncId            int
+	lease                *lease
+	producing            int32
 }
 
 // todo syncDone
@@ -87,9 +97,10 @@ func NewProducer(rw chain.Chain,
 	coinbase *AddressContext,
 	cs consensus.Subscriber,
 	verifier *verifier.SnapshotVerifier,
-	wt *wallet.Manager,
+	sn signer.Signer,
+	protection *signer.Protection,
 	p pool.SnapshotProducerWriter) *producer {
-	chain := newChainRw(rw, verifier, wt, p)
+	chain := newChainRw(rw, verifier, sn, protection, p)
 	miner := &producer{tools: chain, coinbase: coinbase}
 
 	miner.cs = cs
@@ -99,6 +110,13 @@ func NewProducer(rw chain.Chain,
 	miner.dwlFinished = false
 	return miner
 }
+
+// SetLease makes the producer a member of a hot-standby pair, only the node holding the lease
+// subscribes to the consensus events, the lease is negotiated with the peers over tcp and
+// the heartbeats are authenticated with the secret
+func (self *producer) SetLease(listen string, peers []string, secret string) {
+	self.lease = newLease(self.coinbase.Address, listen, peers, secret)
+}
 func (self *producer) Init() error {
 	if !self.PreInit() {
 		return errors.New("pre init fail.")
@@ -131,29 +149,62 @@ func (self *producer) Start() error {
 		return errors.New("coinbase must not be nil.")
 	}
 
+	self.syncState = self.subscriber.SyncState()
+	id := self.subscriber.SubscribeSyncStatus(func(state net.SyncState) {
+		self.syncState = state
+	})
+	self.netSyncId = id
+
+	if self.lease == nil {
+		self.subscribe()
+	} else if err := self.lease.start(self.leaseChanged); err != nil {
+		return err
+	}
+	wLog.Info("started.")
+	return nil
+}
+
+func (self *producer) leaseChanged(leader bool) {
+	if leader {
+		mLog.Warn("take over the production.", "addr", self.coinbase.Address)
+		self.subscribe()
+	} else {
+		mLog.Warn("hand over the production.", "addr", self.coinbase.Address)
+		self.unSubscribe()
+	}
+}
+
+func (self *producer) subscribe() {
 	snapshotId := self.coinbase.Address.String() + "_snapshot"
 	contractId := self.coinbase.Address.String() + "_contract"
 
+	atomic.StoreInt32(&self.producing, 1)
 	self.cs.Subscribe(types.SNAPSHOT_GID, snapshotId, &self.coinbase.Address, func(e consensus.Event) {
 		mLog.Info("snapshot producer trigger.", "addr", self.coinbase.Address, "syncState", self.syncState, "e", e)
-		if self.syncState == net.Syncdone {
+		if self.syncState == net.Syncdone && self.isProducing() {
 			self.worker.produceSnapshot(e)
 		}
 	})
 	self.cs.Subscribe(types.DELEGATE_GID, contractId, &self.coinbase.Address, func(e consensus.Event) {
 		mLog.Info("contract producer trigger.", "addr", self.coinbase.Address, "syncState", self.syncState, "e", e)
-		if self.syncState == net.Syncdone {
+		if self.syncState == net.Syncdone && self.isProducing() {
 			self.producerContract(e)
 		}
 	})
+}
 
-	self.syncState = self.subscriber.SyncState()
-	id := self.subscriber.SubscribeSyncStatus(func(state net.SyncState) {
-		self.syncState = state
-	})
-	self.netSyncId = id
-	wLog.Info("started.")
-	return nil
+func (self *producer) unSubscribe() {
+	snapshotId := self.coinbase.Address.String() + "_snapshot"
+	contractId := self.coinbase.Address.String() + "_contract"
+
+	// the events of the current round may be fired after unsubscribing, they are dropped by the producing flag
+	atomic.StoreInt32(&self.producing, 0)
+	self.cs.UnSubscribe(types.SNAPSHOT_GID, snapshotId)
+	self.cs.UnSubscribe(types.DELEGATE_GID, contractId)
+}
+
+func (self *producer) isProducing() bool {
+	return atomic.LoadInt32(&self.producing) == 1
 }
 
 func (self *producer) Stop() error {
@@ -162,11 +213,10 @@ func (self *producer) Stop() error {
 	}
 	defer self.PostStop()
 
-	snapshotId := self.coinbase.Address.String() + "_snapshot"
-	contractId := self.coinbase.Address.String() + "_contract"
-
-	self.cs.UnSubscribe(types.SNAPSHOT_GID, snapshotId)
-	self.cs.UnSubscribe(types.DELEGATE_GID, contractId)
+	if self.lease != nil {
+		self.lease.stop()
+	}
+	self.unSubscribe()
 
 	self.subscriber.UnsubscribeSyncStatus(self.netSyncId)
 	self.netSyncId = 0
@@ -210,3 +260,11 @@ func (self *producer) SetAccountEventFunc(accountFn func(producerevent.AccountEv
 func (self *producer) GetCoinBase() types.Address {
 	return self.coinbase.Address
 }
+
+func (self *producer) Status() *Status {
+	s := &Status{Coinbase: self.coinbase.Address, Producing: self.isProducing()}
+	if self.lease != nil {
+		s.Lease = self.lease.status()
+	}
+	return s
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"flag"
-	"fmt"
 
 	"github.com/vitelabs/go-vite/chain"
 	"github.com/vitelabs/go-vite/common"
@@ -15,6 +14,7 @@ import (
 	"github.com/vitelabs/go-vite/consensus"
 	"github.com/vitelabs/go-vite/log15"
 	"github.com/vitelabs/go-vite/pool"
+	"github.com/vitelabs/go-vite/signer"
 	"github.com/vitelabs/go-vite/verifier"
 	"github.com/vitelabs/go-vite/vite/net"
 	"github.com/vitelabs/go-vite/wallet"
@@ -33,11 +33,9 @@ var log = log15.New("module", "producerTest")
 
 var accountPrivKeyStr string
 
+// the flag is parsed by the testing package, parsing it in init breaks the test flags
 func init() {
 	flag.StringVar(&accountPrivKeyStr, "k", "", "")
-	flag.Parse()
-	fmt.Println(accountPrivKeyStr)
-
 }
 
 func genConsensus(c chain.Chain, t *testing.T) consensus.Consensus {
@@ -107,9 +105,10 @@ func TestSnapshot(t *testing.T) {
 
 	sv := verifier.NewSnapshotVerifier(c, cs)
 	w := wallet.New(nil)
+	protection, _ := signer.NewProtection("")
 	av := verifier.NewAccountVerifier(c, cs)
 	p1 := pool.NewPool(c)
-	p := NewProducer(c, &testSubscriber{}, coinbase, cs, sv, w, p1)
+	p := NewProducer(c, &testSubscriber{}, coinbase, cs, sv, signer.NewLocalSigner(w), protection, p1)
 
 	p1.Init(&pool.MockSyncer{}, w, sv, av)
 	p.Init()
@@ -146,9 +145,10 @@ func TestProducer_Init(t *testing.T) {
 	cs := &consensus.MockConsensus{}
 	sv := verifier.NewSnapshotVerifier(c, cs)
 	w := wallet.New(nil)
+	protection, _ := signer.NewProtection("")
 	av := verifier.NewAccountVerifier(c, cs)
 	p1 := pool.NewPool(c)
-	p := NewProducer(c, &testSubscriber{}, coinbase, cs, sv, w, p1)
+	p := NewProducer(c, &testSubscriber{}, coinbase, cs, sv, signer.NewLocalSigner(w), protection, p1)
 
 	c.Init()
 	c.Start()
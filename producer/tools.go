@@ -11,16 +11,17 @@ import (
 	"github.com/vitelabs/go-vite/log15"
 	"github.com/vitelabs/go-vite/monitor"
 	"github.com/vitelabs/go-vite/pool"
+	"github.com/vitelabs/go-vite/signer"
 	"github.com/vitelabs/go-vite/verifier"
-	"github.com/vitelabs/go-vite/wallet"
 )
 
 type tools struct {
-	log       log15.Logger
-	wt        *wallet.Manager
-	pool      pool.SnapshotProducerWriter
-	chain     chain.Chain
-	sVerifier *verifier.SnapshotVerifier
+	log        log15.Logger
+	signer     signer.Signer
+	protection *signer.Protection
+	pool       pool.SnapshotProducerWriter
+	chain      chain.Chain
+	sVerifier  *verifier.SnapshotVerifier
 }
 
 func (self *tools) ledgerLock() {
@@ -50,15 +51,11 @@ func (self *tools) generateSnapshot(e *consensus.Event, coinbase *AddressContext
 	}
 
 	block.Hash = block.ComputeHash()
-	manager, err := self.wt.GetEntropyStoreManager(coinbase.EntryPath)
-	if err != nil {
-		return nil, err
-	}
-	_, key, err := manager.DeriveForIndexPath(coinbase.Index)
-	if err != nil {
+	// never sign a block conflicting with a signed one, even if another instance of the producer is running
+	if err := self.protection.Protect(coinbase.Address, signer.NewSlot(e.Gid, e.Index, block)); err != nil {
 		return nil, err
 	}
-	signedData, pubkey, err := key.SignData(block.Hash.Bytes())
+	signedData, pubkey, err := self.signer.SignSnapshotBlock(coinbase.Address, e.Gid, e.Index, block)
 
 	if err != nil {
 		return nil, err
@@ -74,9 +71,9 @@ func (self *tools) insertSnapshot(block *ledger.SnapshotBlock) error {
 	return self.pool.AddDirectSnapshotBlock(block)
 }
 
-func newChainRw(ch chain.Chain, sVerifier *verifier.SnapshotVerifier, wt *wallet.Manager, p pool.SnapshotProducerWriter) *tools {
+func newChainRw(ch chain.Chain, sVerifier *verifier.SnapshotVerifier, sn signer.Signer, protection *signer.Protection, p pool.SnapshotProducerWriter) *tools {
 	log := log15.New("module", "tools")
-	return &tools{chain: ch, log: log, sVerifier: sVerifier, wt: wt, pool: p}
+	return &tools{chain: ch, log: log, sVerifier: sVerifier, signer: sn, protection: protection, pool: p}
 }
 
 func (self *tools) checkAddressLock(address types.Address, coinbase *AddressContext) error {
@@ -84,7 +81,7 @@ func (self *tools) checkAddressLock(address types.Address, coinbase *AddressCont
 		return errors.Errorf("addres not equals.%s-%s", address, coinbase.Address)
 	}
 
-	return self.wt.MatchAddress(coinbase.EntryPath, coinbase.Address, coinbase.Index)
+	return self.signer.CheckAddress(coinbase.Address)
 }
 
 func (self *tools) generateAccounts(head *ledger.SnapshotBlock) (ledger.SnapshotContent, error) {
@@ -4,4 +4,8 @@ import "errors"
 
 var (
 	ErrStrToBigInt = errors.New("convert to big.Int failed")
+
+	ErrSnapshotBlockNotFound = errors.New("snapshot block not found")
+	ErrStateNotFound         = errors.New("state of the snapshot block has been garbage collected")
+	ErrLedgerPruned          = errors.New("ledger of the snapshot block has been pruned")
 )
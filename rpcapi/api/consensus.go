@@ -0,0 +1,282 @@
+package api
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/consensus"
+	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/vite"
+)
+
+// the number of periods a performance query may cover
+const maxPerformancePeriods = 288
+
+type ConsensusApi struct {
+	chain  chain.Chain
+	reader consensus.Reader
+	log    log15.Logger
+}
+
+func NewConsensusApi(vite *vite.Vite) *ConsensusApi {
+	return &ConsensusApi{
+		chain:  vite.Chain(),
+		reader: vite.Consensus(),
+		log:    log15.New("module", "rpc_api/consensus_api"),
+	}
+}
+
+func (c ConsensusApi) String() string {
+	return "ConsensusApi"
+}
+
+type PeriodTime struct {
+	Index     string `json:"index"`
+	StartTime int64  `json:"startTime"`
+	EndTime   int64  `json:"endTime"`
+}
+
+type PlanSlot struct {
+	Producer  types.Address `json:"producer"`
+	StartTime int64         `json:"startTime"`
+	EndTime   int64         `json:"endTime"`
+}
+
+type ConsensusPlan struct {
+	PeriodTime
+	SnapshotHeight string      `json:"snapshotHeight"`
+	SnapshotHash   types.Hash  `json:"snapshotHash"`
+	Slots          []*PlanSlot `json:"slots"`
+}
+
+type ProducerPerformance struct {
+	Producer    types.Address `json:"producer"`
+	PlanNum     string        `json:"planNum"`
+	ActualNum   string        `json:"actualNum"`
+	MissedNum   string        `json:"missedNum"`
+	BlockRate   float64       `json:"blockRate"`
+	MissedSlots []int64       `json:"missedSlots"`
+}
+
+type PerformanceList struct {
+	StartIndex   string                 `json:"startIndex"`
+	EndIndex     string                 `json:"endIndex"`
+	StartTime    int64                  `json:"startTime"`
+	EndTime      int64                  `json:"endTime"`
+	Performances []*ProducerPerformance `json:"performances"`
+}
+
+type VoteRank struct {
+	Rank       int           `json:"rank"`
+	Name       string        `json:"name"`
+	NodeAddr   types.Address `json:"nodeAddr"`
+	Balance    string        `json:"balance"`
+	VoterCount int           `json:"voterCount"`
+}
+
+type VoteRanking struct {
+	Index          string      `json:"index"`
+	SnapshotHeight string      `json:"snapshotHeight"`
+	SnapshotHash   types.Hash  `json:"snapshotHash"`
+	Votes          []*VoteRank `json:"votes"`
+}
+
+func (c *ConsensusApi) periodTime(gid types.Gid, index uint64) (*PeriodTime, error) {
+	sTime, eTime, err := c.reader.VoteIndexToTime(gid, index)
+	if err != nil {
+		return nil, err
+	}
+	return &PeriodTime{uint64ToString(index), sTime.Unix(), eTime.Unix()}, nil
+}
+
+// GetIndexByTime returns the index of the period the timestamp is in, the timestamp is in seconds
+func (c *ConsensusApi) GetIndexByTime(gid types.Gid, timestamp int64) (*PeriodTime, error) {
+	index, err := c.reader.VoteTimeToIndex(gid, time.Unix(timestamp, 0))
+	if err != nil {
+		return nil, err
+	}
+	return c.periodTime(gid, index)
+}
+
+func (c *ConsensusApi) GetTimeByIndex(gid types.Gid, index uint64) (*PeriodTime, error) {
+	return c.periodTime(gid, index)
+}
+
+// GetCurrentPlan returns the producers of every slot in the current period
+func (c *ConsensusApi) GetCurrentPlan(gid types.Gid) (*ConsensusPlan, error) {
+	index, err := c.reader.VoteTimeToIndex(gid, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return c.GetPlan(gid, index)
+}
+
+// GetPlan returns the producers of every slot in the period of the index
+func (c *ConsensusApi) GetPlan(gid types.Gid, index uint64) (*ConsensusPlan, error) {
+	events, index, err := c.reader.ReadByIndex(gid, index)
+	if err != nil {
+		return nil, err
+	}
+	period, err := c.periodTime(gid, index)
+	if err != nil {
+		return nil, err
+	}
+	plan := &ConsensusPlan{PeriodTime: *period, Slots: make([]*PlanSlot, len(events))}
+	for i, e := range events {
+		plan.Slots[i] = &PlanSlot{e.Address, e.Stime.Unix(), e.Etime.Unix()}
+		plan.SnapshotHeight = uint64ToString(e.SnapshotHeight)
+		plan.SnapshotHash = e.SnapshotHash
+	}
+	return plan, nil
+}
+
+// GetUpcomingPlan returns the slots which are not over yet in the current period, followed by the slots of the next period
+func (c *ConsensusApi) GetUpcomingPlan(gid types.Gid) ([]*PlanSlot, error) {
+	now := time.Now()
+	index, err := c.reader.VoteTimeToIndex(gid, now)
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]*PlanSlot, 0)
+	for i := index; i <= index+1; i++ {
+		plan, err := c.GetPlan(gid, i)
+		if err != nil {
+			return nil, err
+		}
+		for _, slot := range plan.Slots {
+			if slot.EndTime > now.Unix() {
+				slots = append(slots, slot)
+			}
+		}
+	}
+	return slots, nil
+}
+
+// GetProducerPerformance compares the plans of the periods from the start index to the end index with the produced
+// snapshot blocks, the slots in which the planned producer did not produce a block are missed.
+// Only the snapshot consensus group is supported, the slots after the latest snapshot block are not counted.
+func (c *ConsensusApi) GetProducerPerformance(gid types.Gid, startIndex uint64, endIndex uint64) (*PerformanceList, error) {
+	if gid != types.SNAPSHOT_GID {
+		return nil, errors.New("performance is only tracked for the snapshot consensus group")
+	}
+	head := c.chain.GetLatestSnapshotBlock()
+	headIndex, err := c.reader.VoteTimeToIndex(gid, *head.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if endIndex > headIndex {
+		endIndex = headIndex
+	}
+	if startIndex > endIndex {
+		return nil, errors.New("start index is greater than end index")
+	}
+	if endIndex-startIndex >= maxPerformancePeriods {
+		return nil, errors.New("too many periods")
+	}
+
+	sTime, _, err := c.reader.VoteIndexToTime(gid, startIndex)
+	if err != nil {
+		return nil, err
+	}
+	_, eTime, err := c.reader.VoteIndexToTime(gid, endIndex)
+	if err != nil {
+		return nil, err
+	}
+	produced, err := c.producedSnapshotBlocks(*sTime, *eTime)
+	if err != nil {
+		return nil, err
+	}
+
+	performanceMap := make(map[types.Address]*ProducerPerformance)
+	planNum := make(map[types.Address]uint64)
+	actualNum := make(map[types.Address]uint64)
+	for i := startIndex; i <= endIndex; i++ {
+		events, _, err := c.reader.ReadByIndex(gid, i)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			if e.Timestamp.After(*head.Timestamp) {
+				continue
+			}
+			p, ok := performanceMap[e.Address]
+			if !ok {
+				p = &ProducerPerformance{Producer: e.Address, MissedSlots: []int64{}}
+				performanceMap[e.Address] = p
+			}
+			planNum[e.Address]++
+			if producer, ok := produced[e.Timestamp.Unix()]; ok && producer == e.Address {
+				actualNum[e.Address]++
+			} else {
+				p.MissedSlots = append(p.MissedSlots, e.Timestamp.Unix())
+			}
+		}
+	}
+
+	list := &PerformanceList{
+		StartIndex:   uint64ToString(startIndex),
+		EndIndex:     uint64ToString(endIndex),
+		StartTime:    sTime.Unix(),
+		EndTime:      eTime.Unix(),
+		Performances: make([]*ProducerPerformance, 0, len(performanceMap)),
+	}
+	for addr, p := range performanceMap {
+		p.PlanNum = uint64ToString(planNum[addr])
+		p.ActualNum = uint64ToString(actualNum[addr])
+		p.MissedNum = uint64ToString(planNum[addr] - actualNum[addr])
+		p.BlockRate = float64(actualNum[addr]) / float64(planNum[addr])
+		list.Performances = append(list.Performances, p)
+	}
+	sort.Slice(list.Performances, func(i, j int) bool {
+		return list.Performances[i].Producer.String() < list.Performances[j].Producer.String()
+	})
+	return list, nil
+}
+
+// producedSnapshotBlocks returns the producers of the snapshot blocks from the start time to the end time by timestamp
+func (c *ConsensusApi) producedSnapshotBlocks(sTime, eTime time.Time) (map[int64]types.Address, error) {
+	produced := make(map[int64]types.Address)
+	block, err := c.chain.GetSnapshotBlockBeforeTime(&eTime)
+	if err != nil {
+		return nil, err
+	}
+	for block != nil && !block.Timestamp.Before(sTime) {
+		produced[block.Timestamp.Unix()] = block.Producer()
+		if block.Height <= types.GenesisHeight {
+			break
+		}
+		block, err = c.chain.GetSnapshotBlockHeadByHeight(block.Height - 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return produced, nil
+}
+
+// GetVoteRanking returns the registrations of the consensus group ordered by the votes counted for the period of the index
+func (c *ConsensusApi) GetVoteRanking(gid types.Gid, index uint64) (*VoteRanking, error) {
+	details, hashH, err := c.reader.ReadVoteMapByTime(gid, index)
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(consensus.ByBalance(details))
+	ranking := &VoteRanking{
+		Index:          uint64ToString(index),
+		SnapshotHeight: uint64ToString(hashH.Height),
+		SnapshotHash:   hashH.Hash,
+		Votes:          make([]*VoteRank, len(details)),
+	}
+	for i, d := range details {
+		ranking.Votes[i] = &VoteRank{
+			Rank:       i + 1,
+			Name:       d.Name,
+			NodeAddr:   d.CurrentAddr,
+			Balance:    *bigIntToString(d.Balance),
+			VoterCount: len(d.Addr),
+		}
+	}
+	return ranking, nil
+}
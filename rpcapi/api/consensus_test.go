@@ -0,0 +1,164 @@
+package api
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/consensus"
+	"github.com/vitelabs/go-vite/consensus/core"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+var testConsensusGenesis = time.Unix(1541640427, 0)
+
+// testConsensusReader converts the indexes and times like the consensus groups do, a period has 2 producers with 3 slots each
+type testConsensusReader struct {
+	info      *core.GroupInfo
+	producers []types.Address
+	details   []*consensus.VoteDetails
+}
+
+func newTestConsensusReader() *testConsensusReader {
+	a, _, _ := types.CreateAddress()
+	b, _, _ := types.CreateAddress()
+	info := core.NewGroupInfo(testConsensusGenesis, types.ConsensusGroupInfo{
+		Gid:       types.SNAPSHOT_GID,
+		NodeCount: 2,
+		Interval:  1,
+		PerCount:  3,
+	})
+	return &testConsensusReader{info: info, producers: []types.Address{a, b}}
+}
+
+func (r *testConsensusReader) ReadByIndex(gid types.Gid, index uint64) ([]*consensus.Event, uint64, error) {
+	var events []*consensus.Event
+	sTime := r.info.GenSTime(index)
+	for i := 0; i < int(r.info.PlanInterval); i++ {
+		stime := sTime.Add(time.Duration(i) * time.Second)
+		events = append(events, &consensus.Event{
+			Gid:            gid,
+			Address:        r.producers[i/int(r.info.PerCount)],
+			Stime:          stime,
+			Etime:          stime.Add(time.Second),
+			Index:          index,
+			Timestamp:      stime,
+			SnapshotHeight: index + 1,
+		})
+	}
+	return events, index, nil
+}
+
+func (r *testConsensusReader) ReadByTime(gid types.Gid, t time.Time) ([]*consensus.Event, uint64, error) {
+	return r.ReadByIndex(gid, r.info.Time2Index(t))
+}
+
+func (r *testConsensusReader) ReadVoteMapByTime(gid types.Gid, index uint64) ([]*consensus.VoteDetails, *ledger.HashHeight, error) {
+	return r.details, &ledger.HashHeight{Height: index + 1}, nil
+}
+
+func (r *testConsensusReader) ReadVoteMapForAPI(gid types.Gid, t time.Time) ([]*consensus.VoteDetails, *ledger.HashHeight, error) {
+	return r.ReadVoteMapByTime(gid, r.info.Time2Index(t))
+}
+
+func (r *testConsensusReader) VoteTimeToIndex(gid types.Gid, t2 time.Time) (uint64, error) {
+	return r.info.Time2Index(t2), nil
+}
+
+func (r *testConsensusReader) VoteIndexToTime(gid types.Gid, i uint64) (*time.Time, *time.Time, error) {
+	sTime, eTime := r.info.GenSTime(i), r.info.GenETime(i)
+	return &sTime, &eTime, nil
+}
+
+func TestConsensusApi_IndexAndTime(t *testing.T) {
+	c := &ConsensusApi{reader: newTestConsensusReader()}
+	start := testConsensusGenesis.Unix()
+
+	for _, tc := range []struct {
+		timestamp int64
+		index     string
+		startTime int64
+	}{
+		{start, "0", start},
+		{start + 5, "0", start},
+		{start + 6, "1", start + 6},
+		{start + 6*100 + 3, "100", start + 6*100},
+	} {
+		period, err := c.GetIndexByTime(types.SNAPSHOT_GID, tc.timestamp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if period.Index != tc.index || period.StartTime != tc.startTime || period.EndTime != tc.startTime+6 {
+			t.Fatalf("unexpected period %+v of the time %d", period, tc.timestamp)
+		}
+	}
+
+	period, err := c.GetTimeByIndex(types.SNAPSHOT_GID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if period.Index != "2" || period.StartTime != start+12 || period.EndTime != start+18 {
+		t.Fatalf("unexpected period %+v of the index 2", period)
+	}
+}
+
+func TestConsensusApi_GetPlan(t *testing.T) {
+	r := newTestConsensusReader()
+	c := &ConsensusApi{reader: r}
+
+	// the index 0 is the first period, not the current one
+	plan, err := c.GetPlan(types.SNAPSHOT_GID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Index != "0" || plan.StartTime != testConsensusGenesis.Unix() || plan.SnapshotHeight != "1" || len(plan.Slots) != 6 {
+		t.Fatalf("unexpected plan %+v", plan)
+	}
+	if plan.Slots[0].Producer != r.producers[0] || plan.Slots[3].Producer != r.producers[1] {
+		t.Fatal("unexpected producers of the plan")
+	}
+
+	current, err := c.GetCurrentPlan(types.SNAPSHOT_GID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Index != uint64ToString(r.info.Time2Index(time.Now())) {
+		t.Fatalf("unexpected index %s of the current plan", current.Index)
+	}
+}
+
+func TestConsensusApi_GetVoteRanking(t *testing.T) {
+	r := newTestConsensusReader()
+	voter, _, _ := types.CreateAddress()
+	detail := func(name string, balance int64, voters int) *consensus.VoteDetails {
+		d := &consensus.VoteDetails{Vote: core.Vote{Name: name, Balance: big.NewInt(balance)}, Addr: make(map[types.Address]*big.Int)}
+		for i := 0; i < voters; i++ {
+			d.Addr[voter] = big.NewInt(balance)
+			voter, _, _ = types.CreateAddress()
+		}
+		return d
+	}
+	r.details = []*consensus.VoteDetails{detail("s3", 10, 1), detail("s1", 30, 2), detail("s4", 10, 0), detail("s2", 20, 1)}
+	c := &ConsensusApi{reader: r}
+
+	ranking, err := c.GetVoteRanking(types.SNAPSHOT_GID, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranking.Index != "5" || ranking.SnapshotHeight != "6" || len(ranking.Votes) != 4 {
+		t.Fatalf("unexpected ranking %+v", ranking)
+	}
+	// ordered by the balance, and by the name for the same balance
+	expected := []struct {
+		name       string
+		balance    string
+		voterCount int
+	}{{"s1", "30", 2}, {"s2", "20", 1}, {"s3", "10", 1}, {"s4", "10", 0}}
+	for i, e := range expected {
+		v := ranking.Votes[i]
+		if v.Rank != i+1 || v.Name != e.name || v.Balance != e.balance || v.VoterCount != e.voterCount {
+			t.Fatalf("unexpected vote rank %+v, expected %+v", v, e)
+		}
+	}
+}
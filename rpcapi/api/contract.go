@@ -1,11 +1,20 @@
 package api
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/vitelabs/go-vite/chain"
 	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
 	"github.com/vitelabs/go-vite/vite"
+	"github.com/vitelabs/go-vite/vm"
+	"github.com/vitelabs/go-vite/vm/abi"
 	"github.com/vitelabs/go-vite/vm/contracts"
+	"github.com/vitelabs/go-vite/vm/util"
+	"github.com/vitelabs/go-vite/vm_context"
+	"github.com/vitelabs/go-vite/vm_context/vmctxt_interface"
 )
 
 type ContractApi struct {
@@ -27,3 +36,150 @@ func (c ContractApi) String() string {
 func (c *ContractApi) GetCreateContractToAddress(selfAddr types.Address, height uint64, prevHash types.Hash, snapshotHash types.Hash) types.Address {
 	return contracts.NewContractAddress(selfAddr, height, prevHash, snapshotHash)
 }
+
+const storagePageSize = 100
+
+type StorageItem struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+type StoragePage struct {
+	Items          []*StorageItem `json:"items"`
+	NextCursor     []byte         `json:"nextCursor"`
+	SnapshotHeight string         `json:"snapshotHeight"`
+	SnapshotHash   types.Hash     `json:"snapshotHash"`
+}
+
+// GetStorageAt returns the storage value of the contract at the snapshot block, snapshot is the height
+// or the hash of the snapshot block, an empty string means the latest one.
+func (c *ContractApi) GetStorageAt(addr types.Address, key []byte, snapshot string) ([]byte, error) {
+	c.log.Info("GetStorageAt")
+	snapshotBlock, err := getSnapshotBlock(c.chain, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	vmContext, err := c.newVmContextAt(addr, snapshotBlock)
+	if err != nil {
+		return nil, err
+	}
+	return vmContext.GetStorage(&addr, key), nil
+}
+
+// IterateStorage lists the storage of the contract with the prefix at the snapshot block in key order,
+// pass the nextCursor of the previous page as cursor to get the next page, nextCursor is nil on the last page.
+func (c *ContractApi) IterateStorage(addr types.Address, prefix []byte, snapshot string, cursor []byte) (*StoragePage, error) {
+	c.log.Info("IterateStorage")
+	snapshotBlock, err := getSnapshotBlock(c.chain, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStateAt(c.chain, addr, snapshotBlock); err != nil {
+		return nil, err
+	}
+	accountBlock, err := c.chain.GetConfirmAccountBlock(snapshotBlock.Height, &addr)
+	if err != nil {
+		c.log.Error("GetConfirmAccountBlock failed, error is "+err.Error(), "method", "IterateStorage")
+		return nil, err
+	}
+
+	page := &StoragePage{
+		Items:          make([]*StorageItem, 0),
+		SnapshotHeight: strconv.FormatUint(snapshotBlock.Height, 10),
+		SnapshotHash:   snapshotBlock.Hash,
+	}
+	if accountBlock == nil {
+		return page, nil
+	}
+	stateTrie := c.chain.GetStateTrie(&accountBlock.StateHash)
+	if stateTrie == nil {
+		return page, nil
+	}
+
+	if len(cursor) == 0 {
+		cursor = nil
+	}
+	iterator := stateTrie.NewSortedIterator(prefix, cursor)
+	for len(page.Items) < storagePageSize {
+		key, value, ok := iterator.Next()
+		if !ok {
+			return page, nil
+		}
+		page.Items = append(page.Items, &StorageItem{Key: key, Value: value})
+	}
+	if _, _, ok := iterator.Next(); ok {
+		page.NextCursor = page.Items[len(page.Items)-1].Key
+	}
+	return page, nil
+}
+
+type CallOffChainResult struct {
+	RawOutput     []byte        `json:"rawOutput"`
+	DecodedOutput []interface{} `json:"decodedOutput,omitempty"`
+}
+
+// CallOffChain executes the contract with data as calldata against the state of the snapshot block,
+// a nil snapshotHash means the latest one, nothing is written to the chain. When abiJson is supplied,
+// the output is decoded by the outputs of the method selected by data.
+func (c *ContractApi) CallOffChain(addr types.Address, data []byte, snapshotHash *types.Hash, abiJson *string) (*CallOffChainResult, error) {
+	c.log.Info("CallOffChain")
+	var snapshotBlock *ledger.SnapshotBlock
+	if snapshotHash == nil {
+		snapshotBlock = c.chain.GetLatestSnapshotBlock()
+	} else {
+		var err error
+		snapshotBlock, err = c.chain.GetSnapshotBlockByHash(snapshotHash)
+		if err != nil {
+			c.log.Error("GetSnapshotBlockByHash failed, error is "+err.Error(), "method", "CallOffChain")
+			return nil, err
+		}
+		if snapshotBlock == nil {
+			return nil, ErrSnapshotBlockNotFound
+		}
+	}
+	if err := checkStateAt(c.chain, addr, snapshotBlock); err != nil {
+		return nil, err
+	}
+
+	prevBlock, err := c.chain.GetConfirmAccountBlock(snapshotBlock.Height, &addr)
+	if err != nil {
+		c.log.Error("GetConfirmAccountBlock failed, error is "+err.Error(), "method", "CallOffChain")
+		return nil, err
+	}
+	if prevBlock == nil {
+		return nil, util.ErrContractCodeNotExist
+	}
+	vmContext, err := vm_context.NewVmContext(c.chain, &snapshotBlock.Hash, &prevBlock.Hash, &addr)
+	if err != nil {
+		c.log.Error("NewVmContext failed, error is "+err.Error(), "method", "CallOffChain")
+		return nil, err
+	}
+
+	output, err := vm.NewVM().RunOffChain(vmContext.CopyAndFreeze(), addr, data)
+	if err != nil {
+		return nil, err
+	}
+	result := &CallOffChainResult{RawOutput: output}
+	if abiJson == nil || len(*abiJson) == 0 {
+		return result, nil
+	}
+
+	result.DecodedOutput, err = abi.UnpackOutputs(strings.NewReader(*abiJson), data, output)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *ContractApi) newVmContextAt(addr types.Address, snapshotBlock *ledger.SnapshotBlock) (vmctxt_interface.VmDatabase, error) {
+	if err := checkStateAt(c.chain, addr, snapshotBlock); err != nil {
+		return nil, err
+	}
+
+	vmContext, err := vm_context.NewVmContext(c.chain, &snapshotBlock.Hash, nil, nil)
+	if err != nil {
+		c.log.Error("NewVmContext failed, error is "+err.Error(), "method", "newVmContextAt")
+		return nil, err
+	}
+	return vmContext, nil
+}
@@ -14,6 +14,9 @@ import (
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/vite"
 	"github.com/vitelabs/go-vite/vite/net"
+	"github.com/vitelabs/go-vite/vm"
+	"github.com/vitelabs/go-vite/vm_context"
+	"github.com/vitelabs/go-vite/vm_context/vmctxt_interface"
 )
 
 type DebugApi struct {
@@ -299,3 +302,101 @@ func (api DebugApi) SetGetTestTokenLimitSize(size int) error {
 	testtokenlruLimitSize = size
 	return nil
 }
+
+// maxTraceSteps caps the steps recorded by the structLogger, a block can execute far more opcodes than a response should hold
+const maxTraceSteps = 10000
+
+// TraceConfig is the optional config of the structLogger, Limit is capped by maxTraceSteps.
+type TraceConfig struct {
+	DisableStack  bool `json:"disableStack"`
+	DisableMemory bool `json:"disableMemory"`
+	Limit         int  `json:"limit"`
+}
+
+type TraceResult struct {
+	Output     []byte          `json:"output"`
+	Error      *string         `json:"error"`
+	Truncated  bool            `json:"truncated,omitempty"`
+	StructLogs []*vm.StructLog `json:"structLogs,omitempty"`
+	Calls      *vm.CallFrame   `json:"calls,omitempty"`
+}
+
+// TraceBlock re-executes the account block against the state before it and returns the trace of the vm,
+// tracer is "structLogger" by default or "callTracer", config only applies to the structLogger.
+func (api DebugApi) TraceBlock(hash types.Hash, tracer *string, config *TraceConfig) (*TraceResult, error) {
+	c := api.v.Chain()
+	block, err := c.GetAccountBlockByHash(&hash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, errors.New("account block not found")
+	}
+
+	var sendBlock *ledger.AccountBlock
+	if block.IsReceiveBlock() {
+		sendBlock, err = c.GetAccountBlockByHash(&block.FromBlockHash)
+		if err != nil {
+			return nil, err
+		}
+		if sendBlock == nil {
+			return nil, errors.New("send block not found")
+		}
+	}
+
+	snapshotBlock, err := c.GetSnapshotBlockHeadByHash(&block.SnapshotHash)
+	if err != nil {
+		return nil, err
+	}
+	if snapshotBlock == nil {
+		return nil, ErrSnapshotBlockNotFound
+	}
+	if err := checkPruned(c, snapshotBlock); err != nil {
+		return nil, err
+	}
+
+	var vmContext vmctxt_interface.VmDatabase
+	if block.Height > 1 {
+		vmContext, err = vm_context.NewVmContext(c, &block.SnapshotHash, &block.PrevHash, &block.AccountAddress)
+	} else {
+		vmContext, err = vm_context.NewVmContextWithEmptyState(c, &block.SnapshotHash, &block.AccountAddress)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	v := vm.NewVM()
+	var structLogger *vm.StructLogger
+	var callTracer *vm.CallTracer
+	if tracer == nil || *tracer == "" || *tracer == "structLogger" {
+		cfg := &vm.StructLoggerConfig{Limit: maxTraceSteps}
+		if config != nil {
+			cfg.DisableStack, cfg.DisableMemory = config.DisableStack, config.DisableMemory
+			if config.Limit > 0 && config.Limit < maxTraceSteps {
+				cfg.Limit = config.Limit
+			}
+		}
+		structLogger = vm.NewStructLogger(cfg)
+		v.Tracer = structLogger
+	} else if *tracer == "callTracer" {
+		callTracer = vm.NewCallTracer()
+		v.Tracer = callTracer
+	} else {
+		return nil, errors.New("unknown tracer " + *tracer)
+	}
+
+	result := &TraceResult{}
+	if _, _, err := v.Run(vmContext, block, sendBlock); err != nil {
+		errMsg := err.Error()
+		result.Error = &errMsg
+	}
+	if structLogger != nil {
+		result.Output = structLogger.Output()
+		result.StructLogs = structLogger.StructLogs()
+		result.Truncated = structLogger.Truncated()
+	} else if root := callTracer.Result(); root != nil {
+		result.Output = root.Output
+		result.Calls = root
+	}
+	return result, nil
+}
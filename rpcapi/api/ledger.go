@@ -3,6 +3,7 @@ package api
 import (
 	"github.com/pkg/errors"
 	"github.com/vitelabs/go-vite/chain"
+	chainindex "github.com/vitelabs/go-vite/chain/index"
 	"github.com/vitelabs/go-vite/chain/trie_gc"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/generator"
@@ -218,6 +219,122 @@ func (l *LedgerApi) GetLatestBlock(addr types.Address) (*AccountBlock, error) {
 	return l.ledgerBlockToRpcBlock(block)
 }
 
+type BalanceAt struct {
+	Balance        string     `json:"balance"`
+	SnapshotHeight string     `json:"snapshotHeight"`
+	SnapshotHash   types.Hash `json:"snapshotHash"`
+}
+
+// GetBalanceAt returns the balance of addr at the snapshot block, snapshot is the height or the hash
+// of the snapshot block, an empty string means the latest one.
+func (l *LedgerApi) GetBalanceAt(addr types.Address, tokenId types.TokenTypeId, snapshot string) (*BalanceAt, error) {
+	l.log.Info("GetBalanceAt")
+	snapshotBlock, err := getSnapshotBlock(l.chain, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStateAt(l.chain, addr, snapshotBlock); err != nil {
+		return nil, err
+	}
+
+	balanceList, err := l.chain.GetBalanceList(snapshotBlock.Hash, tokenId, []types.Address{addr})
+	if err != nil {
+		l.log.Error("GetBalanceList failed, error is "+err.Error(), "method", "GetBalanceAt")
+		return nil, err
+	}
+
+	return &BalanceAt{
+		Balance:        balanceList[addr].String(),
+		SnapshotHeight: strconv.FormatUint(snapshotBlock.Height, 10),
+		SnapshotHash:   snapshotBlock.Hash,
+	}, nil
+}
+
+type StorageProof struct {
+	SnapshotHash      types.Hash `json:"snapshotHash"`
+	SnapshotHeight    string     `json:"snapshotHeight"`
+	SnapshotStateHash types.Hash `json:"snapshotStateHash"`
+
+	// AccountProof proves that the state hash of the account is recorded in the state trie of the snapshot block
+	AccountProof [][]byte `json:"accountProof"`
+
+	AccountBlockHash   *types.Hash        `json:"accountBlockHash"`
+	AccountBlockHeight string             `json:"accountBlockHeight"`
+	StateHash          *types.Hash        `json:"stateHash"`
+	StorageProofs      []*StorageKeyProof `json:"storageProofs"`
+}
+
+type StorageKeyProof struct {
+	Key   []byte   `json:"key"`
+	Value []byte   `json:"value"`
+	Proof [][]byte `json:"proof"`
+}
+
+// GetStorageProof returns the merkle proofs of the storage keys of addr at the snapshot block. The account proof
+// links the state hash of the account to the state hash of the snapshot block, every storage proof links the
+// value of a key to the state hash of the account, both can be checked by trie.VerifyProof.
+// The balance of a token is stored under the key vm_context.BalanceKey(tokenId).
+func (l *LedgerApi) GetStorageProof(addr types.Address, keys [][]byte, snapshotHash types.Hash) (*StorageProof, error) {
+	l.log.Info("GetStorageProof")
+	snapshotBlock, err := l.chain.GetSnapshotBlockHeadByHash(&snapshotHash)
+	if err != nil {
+		l.log.Error("GetSnapshotBlockHeadByHash failed, error is "+err.Error(), "method", "GetStorageProof")
+		return nil, err
+	}
+	if snapshotBlock == nil {
+		return nil, ErrSnapshotBlockNotFound
+	}
+	if err := checkPruned(l.chain, snapshotBlock); err != nil {
+		return nil, err
+	}
+
+	snapshotTrie := l.chain.GetStateTrie(&snapshotBlock.StateHash)
+	if snapshotTrie == nil || snapshotTrie.Root == nil {
+		return nil, ErrStateNotFound
+	}
+	accountProof, err := snapshotTrie.Prove(addr.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StorageProof{
+		SnapshotHash:      snapshotBlock.Hash,
+		SnapshotHeight:    strconv.FormatUint(snapshotBlock.Height, 10),
+		SnapshotStateHash: snapshotBlock.StateHash,
+		AccountProof:      accountProof,
+		StorageProofs:     make([]*StorageKeyProof, 0, len(keys)),
+	}
+
+	accountBlock, err := l.chain.GetConfirmAccountBlock(snapshotBlock.Height, &addr)
+	if err != nil {
+		l.log.Error("GetConfirmAccountBlock failed, error is "+err.Error(), "method", "GetStorageProof")
+		return nil, err
+	}
+	if accountBlock == nil {
+		return result, nil
+	}
+	result.AccountBlockHash = &accountBlock.Hash
+	result.AccountBlockHeight = strconv.FormatUint(accountBlock.Height, 10)
+	result.StateHash = &accountBlock.StateHash
+
+	stateTrie := l.chain.GetStateTrie(&accountBlock.StateHash)
+	if stateTrie == nil || stateTrie.Root == nil {
+		return nil, ErrStateNotFound
+	}
+	for _, key := range keys {
+		proof, err := stateTrie.Prove(key)
+		if err != nil {
+			return nil, err
+		}
+		result.StorageProofs = append(result.StorageProofs, &StorageKeyProof{
+			Key:   key,
+			Value: stateTrie.GetValue(key),
+			Proof: proof,
+		})
+	}
+	return result, nil
+}
+
 func (l *LedgerApi) GetTokenMintage(tti types.TokenTypeId) (*RpcTokenInfo, error) {
 	l.log.Info("GetTokenMintage")
 	if t, err := l.chain.GetTokenInfoById(&tti); err != nil {
@@ -355,6 +472,150 @@ func (l *LedgerApi) GetVmLogList(blockHash types.Hash) (ledger.VmLogList, error)
 	return l.chain.GetVmLogList(block.LogHash)
 }
 
+type LogsQueryParam struct {
+	FromHeight uint64          `json:"fromHeight"`
+	ToHeight   uint64          `json:"toHeight"`
+	Addresses  []types.Address `json:"addresses"`
+	Topics     [][]types.Hash  `json:"topics"`
+}
+
+type LogRecord struct {
+	Log              *ledger.VmLog `json:"log"`
+	Addr             types.Address `json:"addr"`
+	AccountBlockHash types.Hash    `json:"accountBlockHash"`
+	AccountHeight    string        `json:"accountHeight"`
+	SnapshotHeight   string        `json:"snapshotHeight"`
+	LogIndex         uint64        `json:"logIndex"`
+}
+
+type LogsPage struct {
+	List       []*LogRecord `json:"list"`
+	NextCursor []byte       `json:"nextCursor"`
+}
+
+// maxLogsPageSize is the max count of the logs in a page of GetLogs
+const maxLogsPageSize = 1000
+
+// GetLogs queries the log index with the filter, a toHeight of 0 means the latest snapshot block. Pass the nextCursor
+// of a page as the cursor to get the next page, count is at most 1000.
+// The index must be opened by OpenLogIndex in the node config.
+func (l *LedgerApi) GetLogs(filter LogsQueryParam, cursor []byte, count int) (*LogsPage, error) {
+	l.log.Info("GetLogs")
+	if count <= 0 || count > maxLogsPageSize {
+		return nil, errors.New("count should be between 1 and " + strconv.Itoa(maxLogsPageSize))
+	}
+	records, nextCursor, err := l.chain.GetLogs(&chainindex.LogFilter{
+		FromHeight: filter.FromHeight,
+		ToHeight:   filter.ToHeight,
+		Addresses:  filter.Addresses,
+		Topics:     filter.Topics,
+	}, cursor, count)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &LogsPage{List: make([]*LogRecord, len(records)), NextCursor: nextCursor}
+	for i, record := range records {
+		page.List[i] = &LogRecord{
+			Log:              record.Log,
+			Addr:             record.Addr,
+			AccountBlockHash: record.AccountBlockHash,
+			AccountHeight:    strconv.FormatUint(record.AccountHeight, 10),
+			SnapshotHeight:   strconv.FormatUint(record.SnapshotHeight, 10),
+			LogIndex:         record.LogIndex,
+		}
+	}
+	return page, nil
+}
+
+// transactionsPageSize is the count of the transactions in a page of GetTransactions
+const transactionsPageSize = 100
+
+type TxFilterParam struct {
+	TokenId   *types.TokenTypeId `json:"tokenId"`
+	Direction string             `json:"direction"` // "in", "out" or "" for both
+	FromTime  int64              `json:"fromTime"`
+	ToTime    int64              `json:"toTime"`
+}
+
+type TxRecord struct {
+	SendBlock      *AccountBlock   `json:"sendBlock"`
+	ReceiveBlocks  []*AccountBlock `json:"receiveBlocks"`
+	Direction      string          `json:"direction"`
+	SnapshotHeight string          `json:"snapshotHeight"`
+}
+
+type TxPage struct {
+	List       []*TxRecord `json:"list"`
+	NextCursor []byte      `json:"nextCursor"`
+}
+
+// GetTransactions queries the transactions of addr with the filter from the newest to the oldest, each send block
+// is joined with its receive blocks. Pass the nextCursor of a page as the cursor to get the next page,
+// the index must be opened by OpenTxIndex in the node config.
+func (l *LedgerApi) GetTransactions(addr types.Address, filter TxFilterParam, cursor []byte) (*TxPage, error) {
+	l.log.Info("GetTransactions")
+
+	txFilter := &chainindex.TxFilter{
+		TokenId:  filter.TokenId,
+		FromTime: filter.FromTime,
+		ToTime:   filter.ToTime,
+	}
+	switch filter.Direction {
+	case "":
+	case "in":
+		txFilter.Direction = chainindex.DirectionIn
+	case "out":
+		txFilter.Direction = chainindex.DirectionOut
+	default:
+		return nil, errors.New("direction should be in or out")
+	}
+
+	records, nextCursor, err := l.chain.GetTransactions(addr, txFilter, cursor, transactionsPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &TxPage{
+		List:       make([]*TxRecord, len(records)),
+		NextCursor: nextCursor,
+	}
+	for i, record := range records {
+		sendBlock, err := ledgerToRpcBlock(record.SendBlock, l.chain)
+		if err != nil {
+			l.log.Error("ledgerToRpcBlock failed, error is "+err.Error(), "method", "GetTransactions")
+			return nil, err
+		}
+		receiveBlocks := make([]*AccountBlock, len(record.ReceiveBlocks))
+		for j, receiveBlock := range record.ReceiveBlocks {
+			if receiveBlocks[j], err = ledgerToRpcBlock(receiveBlock, l.chain); err != nil {
+				l.log.Error("ledgerToRpcBlock failed, error is "+err.Error(), "method", "GetTransactions")
+				return nil, err
+			}
+		}
+
+		// a send to self is reported from the view of the sender
+		direction := "in"
+		if record.Direction&chainindex.DirectionOut > 0 {
+			direction = "out"
+		}
+		page.List[i] = &TxRecord{
+			SendBlock:      sendBlock,
+			ReceiveBlocks:  receiveBlocks,
+			Direction:      direction,
+			SnapshotHeight: strconv.FormatUint(record.SnapshotHeight, 10),
+		}
+	}
+	return page, nil
+}
+
+// GetPrunedHeight returns the height of the snapshot block below or at which the ledger is pruned,
+// the blocks confirmed by them can only be found in the ledger files.
+func (l *LedgerApi) GetPrunedHeight() string {
+	l.log.Info("GetPrunedHeight")
+	return strconv.FormatUint(l.chain.PrunedHeight(), 10)
+}
+
 func (l *LedgerApi) GetGcStatus() *GcStatus {
 	statusCode := l.chain.TrieGc().Status()
 
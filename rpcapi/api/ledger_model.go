@@ -128,6 +128,8 @@ type RpcTokenInfo struct {
 	TotalSupply    *string           `json:"totalSupply,omitempty"` // *big.Int
 	Decimals       uint8             `json:"decimals"`
 	Owner          types.Address     `json:"owner"`
+	IsReIssuable   bool              `json:"isReIssuable"`
+	MaxSupply      *string           `json:"maxSupply,omitempty"`    // *big.Int
 	PledgeAmount   *string           `json:"pledgeAmount,omitempty"` // *big.Int
 	WithdrawHeight string            `json:"withdrawHeight"`         // uint64
 	TokenId        types.TokenTypeId `json:"tokenId"`
@@ -142,6 +144,8 @@ func RawTokenInfoToRpc(tinfo *types.TokenInfo, tti types.TokenTypeId) *RpcTokenI
 			TotalSupply:    nil,
 			Decimals:       tinfo.Decimals,
 			Owner:          tinfo.Owner,
+			IsReIssuable:   tinfo.IsReIssuable,
+			MaxSupply:      nil,
 			PledgeAmount:   nil,
 			WithdrawHeight: strconv.FormatUint(tinfo.WithdrawHeight, 10),
 			TokenId:        tti,
@@ -150,6 +154,10 @@ func RawTokenInfoToRpc(tinfo *types.TokenInfo, tti types.TokenTypeId) *RpcTokenI
 			s := tinfo.TotalSupply.String()
 			rt.TotalSupply = &s
 		}
+		if tinfo.MaxSupply != nil {
+			s := tinfo.MaxSupply.String()
+			rt.MaxSupply = &s
+		}
 		if tinfo.PledgeAmount != nil {
 			s := tinfo.PledgeAmount.String()
 			rt.PledgeAmount = &s
@@ -166,8 +174,10 @@ type KafkaSendInfo struct {
 
 type KafkaProducerInfo struct {
 	ProducerId uint8    `json:"producerId"`
+	SinkType   string   `json:"sinkType"`
 	BrokerList []string `json:"brokerList"`
 	Topic      string   `json:"topic"`
+	Target     string   `json:"target"`
 	HasSend    uint64   `json:"hasSend"`
 	Status     string   `json:"status"`
 }
@@ -183,8 +193,10 @@ func createKafkaProducerInfo(producer *sender.Producer) *KafkaProducerInfo {
 
 	producerInfo := &KafkaProducerInfo{
 		ProducerId: producer.ProducerId(),
+		SinkType:   producer.SinkType(),
 		BrokerList: producer.BrokerList(),
 		Topic:      producer.Topic(),
+		Target:     producer.Target(),
 		HasSend:    producer.HasSend(),
 		Status:     status,
 	}
@@ -34,6 +34,7 @@ type MintageParams struct {
 	TokenSymbol  string
 	TotalSupply  *big.Int
 	Decimals     uint8
+	MaxSupply    *big.Int // only used by the reissuable token
 }
 
 func (m *MintageApi) GetMintageData(param MintageParams) ([]byte, error) {
@@ -43,3 +44,22 @@ func (m *MintageApi) GetMintageData(param MintageParams) ([]byte, error) {
 func (m *MintageApi) GetMintageCancelPledgeData(tokenId types.TokenTypeId) ([]byte, error) {
 	return abi.ABIMintage.PackMethod(abi.MethodNameMintageCancelPledge, tokenId)
 }
+func (m *MintageApi) GetMintageReIssuableData(param MintageParams) ([]byte, error) {
+	tokenId := abi.NewTokenId(param.SelfAddr, param.Height, param.PrevHash, param.SnapshotHash)
+	return abi.ABIMintage.PackMethod(abi.MethodNameMintageReIssuable, tokenId, param.TokenName, param.TokenSymbol, param.TotalSupply, param.Decimals, param.MaxSupply)
+}
+func (m *MintageApi) GetReIssueData(tokenId types.TokenTypeId, amount string, beneficial types.Address) ([]byte, error) {
+	if bAmount, err := stringToBigInt(&amount); err == nil {
+		return abi.ABIMintage.PackMethod(abi.MethodNameMintageReIssue, tokenId, bAmount, beneficial)
+	} else {
+		return nil, err
+	}
+}
+
+// GetBurnData builds the data of the send block burning the token of the block amount
+func (m *MintageApi) GetBurnData() ([]byte, error) {
+	return abi.ABIMintage.PackMethod(abi.MethodNameMintageBurn)
+}
+func (m *MintageApi) GetTransferOwnerData(tokenId types.TokenTypeId, newOwner types.Address) ([]byte, error) {
+	return abi.ABIMintage.PackMethod(abi.MethodNameMintageTransferOwner, tokenId, newOwner)
+}
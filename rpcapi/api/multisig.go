@@ -0,0 +1,104 @@
+package api
+
+import (
+	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/vite"
+	"github.com/vitelabs/go-vite/vm/contracts/abi"
+	"github.com/vitelabs/go-vite/vm_context"
+)
+
+type MultisigApi struct {
+	chain chain.Chain
+	log   log15.Logger
+}
+
+func NewMultisigApi(vite *vite.Vite) *MultisigApi {
+	return &MultisigApi{
+		chain: vite.Chain(),
+		log:   log15.New("module", "rpc_api/multisig_api"),
+	}
+}
+
+func (m MultisigApi) String() string {
+	return "MultisigApi"
+}
+
+// GetCreateMultisigData builds the data of the send block creating a multisig wallet,
+// the id of the wallet is the hash of the send block
+func (m *MultisigApi) GetCreateMultisigData(owners []types.Address, threshold uint8) ([]byte, error) {
+	return abi.ABIMultisig.PackMethod(abi.MethodNameCreateMultisig, owners, threshold)
+}
+
+func (m *MultisigApi) GetDepositData(walletId types.Hash) ([]byte, error) {
+	return abi.ABIMultisig.PackMethod(abi.MethodNameMultisigDeposit, walletId)
+}
+
+// GetProposeData builds the data of the send block proposing a transfer,
+// the id of the proposal is the hash of the send block
+func (m *MultisigApi) GetProposeData(walletId types.Hash, to types.Address, tokenId types.TokenTypeId, amount string) ([]byte, error) {
+	if bAmount, err := stringToBigInt(&amount); err == nil {
+		return abi.ABIMultisig.PackMethod(abi.MethodNameMultisigPropose, walletId, to, tokenId, bAmount)
+	} else {
+		return nil, err
+	}
+}
+
+func (m *MultisigApi) GetApproveData(walletId types.Hash, proposalId types.Hash) ([]byte, error) {
+	return abi.ABIMultisig.PackMethod(abi.MethodNameMultisigApprove, walletId, proposalId)
+}
+
+func (m *MultisigApi) GetRevokeData(walletId types.Hash, proposalId types.Hash) ([]byte, error) {
+	return abi.ABIMultisig.PackMethod(abi.MethodNameMultisigRevoke, walletId, proposalId)
+}
+
+type MultisigWallet struct {
+	Owners    []types.Address              `json:"owners"`
+	Threshold uint8                        `json:"threshold"`
+	Balances  map[types.TokenTypeId]string `json:"balances"`
+}
+
+type MultisigProposal struct {
+	ProposalId types.Hash        `json:"proposalId"`
+	To         types.Address     `json:"to"`
+	TokenId    types.TokenTypeId `json:"tokenId"`
+	Amount     string            `json:"amount"`
+	Approvers  []types.Address   `json:"approvers"`
+}
+
+func (m *MultisigApi) GetMultisigWallet(walletId types.Hash) (*MultisigWallet, error) {
+	snapshotBlock := m.chain.GetLatestSnapshotBlock()
+	vmContext, err := vm_context.NewVmContext(m.chain, &snapshotBlock.Hash, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	wallet := abi.GetMultisigWallet(vmContext, walletId)
+	if wallet == nil {
+		return nil, nil
+	}
+	balances := make(map[types.TokenTypeId]string)
+	for tokenId, amount := range abi.GetMultisigBalances(vmContext, walletId) {
+		balances[tokenId] = *bigIntToString(amount)
+	}
+	return &MultisigWallet{wallet.Owners, wallet.Threshold, balances}, nil
+}
+
+func (m *MultisigApi) GetProposalList(walletId types.Hash) ([]*MultisigProposal, error) {
+	snapshotBlock := m.chain.GetLatestSnapshotBlock()
+	vmContext, err := vm_context.NewVmContext(m.chain, &snapshotBlock.Hash, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	list := abi.GetMultisigProposalList(vmContext, walletId)
+	targetList := make([]*MultisigProposal, len(list))
+	for i, proposal := range list {
+		targetList[i] = &MultisigProposal{
+			proposal.ProposalId,
+			proposal.To,
+			proposal.TokenId,
+			*bigIntToString(proposal.Amount),
+			proposal.Approvers}
+	}
+	return targetList, nil
+}
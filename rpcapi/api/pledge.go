@@ -41,6 +41,16 @@ func (p *PledgeApi) GetCancelPledgeData(beneficialAddr types.Address, amount str
 	}
 }
 
+// GetAgentPledgeData builds the data of the send block pledging its amount as an agent for the beneficial,
+// the pledge height is counted in snapshot blocks
+func (p *PledgeApi) GetAgentPledgeData(beneficialAddr types.Address, pledgeHeight uint64) ([]byte, error) {
+	return abi.ABIPledge.PackMethod(abi.MethodNameAgentPledge, beneficialAddr, pledgeHeight)
+}
+
+func (p *PledgeApi) GetCancelAgentPledgeData(beneficialAddrList []types.Address) ([]byte, error) {
+	return abi.ABIPledge.PackMethod(abi.MethodNameCancelAgentPledge, beneficialAddrList)
+}
+
 type QuotaAndTxNum struct {
 	Quota string `json:"quota"`
 	TxNum string `json:"txNum"`
@@ -105,3 +115,48 @@ func (p *PledgeApi) GetPledgeList(addr types.Address, index int, count int) (*Pl
 	}
 	return &PledgeInfoList{*bigIntToString(amount), len(list), targetList}, nil
 }
+
+type AgentPledgeInfoList struct {
+	TotalPledgeAmount string             `json:"totalPledgeAmount"`
+	Count             int                `json:"totalCount"`
+	List              []*AgentPledgeInfo `json:"pledgeInfoList"`
+}
+type AgentPledgeInfo struct {
+	Amount         string        `json:"amount"`
+	WithdrawHeight string        `json:"withdrawHeight"`
+	BeneficialAddr types.Address `json:"beneficialAddr"`
+	WithdrawTime   int64         `json:"withdrawTime"`
+	Quota          string        `json:"quota"`
+	TxNum          string        `json:"txNum"`
+}
+
+// GetAgentPledgeList returns the agent pledges of the agent, with the current quota of each beneficial
+func (p *PledgeApi) GetAgentPledgeList(agentAddr types.Address) (*AgentPledgeInfoList, error) {
+	snapshotBlock := p.chain.GetLatestSnapshotBlock()
+	vmContext, err := vm_context.NewVmContext(p.chain, &snapshotBlock.Hash, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	list, amount := abi.GetAgentPledgeInfoList(vmContext, agentAddr)
+	sort.Sort(byWithdrawHeight(list))
+	beneficialList := make([]types.Address, len(list))
+	for i, info := range list {
+		beneficialList[i] = info.BeneficialAddr
+	}
+	quotas, err := p.chain.GetPledgeQuotas(snapshotBlock.Hash, beneficialList)
+	if err != nil {
+		return nil, err
+	}
+	targetList := make([]*AgentPledgeInfo, len(list))
+	for i, info := range list {
+		q := quotas[info.BeneficialAddr]
+		targetList[i] = &AgentPledgeInfo{
+			*bigIntToString(info.Amount),
+			uint64ToString(info.WithdrawHeight),
+			info.BeneficialAddr,
+			getWithdrawTime(snapshotBlock.Timestamp, snapshotBlock.Height, info.WithdrawHeight),
+			uint64ToString(q),
+			uint64ToString(q / util.TxGas)}
+	}
+	return &AgentPledgeInfoList{*bigIntToString(amount), len(list), targetList}, nil
+}
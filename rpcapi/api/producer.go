@@ -0,0 +1,31 @@
+package api
+
+import (
+	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/producer"
+	"github.com/vitelabs/go-vite/vite"
+)
+
+type ProducerApi struct {
+	vite *vite.Vite
+	log  log15.Logger
+}
+
+func NewProducerApi(vite *vite.Vite) *ProducerApi {
+	return &ProducerApi{
+		vite: vite,
+		log:  log15.New("module", "rpc_api/producer_api"),
+	}
+}
+
+func (p ProducerApi) String() string {
+	return "ProducerApi"
+}
+
+// GetStatus returns nil if the node is not a producer, a standby of a hot-standby pair is not producing
+func (p *ProducerApi) GetStatus() *producer.Status {
+	if p.vite.Producer() == nil {
+		return nil
+	}
+	return p.vite.Producer().Status()
+}
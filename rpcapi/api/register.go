@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"sort"
 	"time"
 
@@ -9,8 +10,10 @@ import (
 	"github.com/vitelabs/go-vite/consensus"
 	"github.com/vitelabs/go-vite/log15"
 	"github.com/vitelabs/go-vite/vite"
+	"github.com/vitelabs/go-vite/vm/contracts"
 	"github.com/vitelabs/go-vite/vm/contracts/abi"
 	"github.com/vitelabs/go-vite/vm_context"
+	"github.com/vitelabs/go-vite/vm_context/vmctxt_interface"
 )
 
 type RegisterApi struct {
@@ -40,6 +43,9 @@ func (r *RegisterApi) GetCancelRegisterData(gid types.Gid, name string) ([]byte,
 func (r *RegisterApi) GetRewardData(gid types.Gid, name string, beneficialAddr types.Address) ([]byte, error) {
 	return abi.ABIRegister.PackMethod(abi.MethodNameReward, gid, name, beneficialAddr)
 }
+func (r *RegisterApi) GetWithdrawVoterRewardData(beneficialAddr types.Address) ([]byte, error) {
+	return abi.ABIRegister.PackMethod(abi.MethodNameWithdrawVoterReward, beneficialAddr)
+}
 func (r *RegisterApi) GetUpdateRegistrationData(gid types.Gid, name string, nodeAddr types.Address) ([]byte, error) {
 	return abi.ABIRegister.PackMethod(abi.MethodNameUpdateRegistration, gid, name, nodeAddr)
 }
@@ -162,3 +168,118 @@ func (r *RegisterApi) GetCandidateList(gid types.Gid) ([]*CandidateInfo, error)
 	}
 	return result, nil
 }
+
+type RewardByDay struct {
+	StartIndex string  `json:"startIndex"`
+	EndIndex   string  `json:"endIndex"`
+	StartTime  int64   `json:"startTime"`
+	EndTime    int64   `json:"endTime"`
+	PlanNum    string  `json:"planNum"`
+	ActualNum  string  `json:"actualNum"`
+	BlockRate  float64 `json:"blockRate"`
+	Reward     string  `json:"reward"`
+}
+
+// GetRewardByDay returns the snapshot block reward which can be withdrawn by the registration
+// in reward time units, with the planned and actually produced block counts of each day
+func (r *RegisterApi) GetRewardByDay(gid types.Gid, name string) ([]*RewardByDay, error) {
+	vmContext, registration, err := r.getRegistrationContext(gid, name)
+	if err != nil {
+		return nil, err
+	}
+	_, _, rewardList, periodTime, err := contracts.CalcRewardByDay(vmContext, registration, gid)
+	if err != nil {
+		return nil, err
+	}
+	genesisTime := vmContext.GetGenesisSnapshotBlock().Timestamp.Unix()
+	targetList := make([]*RewardByDay, len(rewardList))
+	for i, rewardOfDay := range rewardList {
+		targetList[i] = &RewardByDay{
+			StartIndex: uint64ToString(rewardOfDay.StartIndex),
+			EndIndex:   uint64ToString(rewardOfDay.EndIndex),
+			StartTime:  contracts.IndexToTime(rewardOfDay.StartIndex, genesisTime, periodTime),
+			EndTime:    contracts.IndexToTime(rewardOfDay.EndIndex+1, genesisTime, periodTime),
+			PlanNum:    uint64ToString(rewardOfDay.PlanNum),
+			ActualNum:  uint64ToString(rewardOfDay.ActualNum),
+			BlockRate:  blockRate(rewardOfDay.PlanNum, rewardOfDay.ActualNum),
+			Reward:     *bigIntToString(rewardOfDay.Reward),
+		}
+	}
+	return targetList, nil
+}
+
+type PendingReward struct {
+	TotalReward    string            `json:"totalReward"`
+	ProducerReward string            `json:"producerReward"`
+	VoterReward    map[string]string `json:"voterReward"`
+	StartIndex     string            `json:"startIndex"`
+	EndIndex       string            `json:"endIndex"`
+	PlanNum        string            `json:"planNum"`
+	ActualNum      string            `json:"actualNum"`
+	BlockRate      float64           `json:"blockRate"`
+}
+
+// GetPendingReward previews the reward of the next withdrawal of the registration,
+// split into the part of the producer and the shares of the voters
+func (r *RegisterApi) GetPendingReward(gid types.Gid, name string) (*PendingReward, error) {
+	vmContext, registration, err := r.getRegistrationContext(gid, name)
+	if err != nil {
+		return nil, err
+	}
+	_, endIndex, rewardList, _, err := contracts.CalcRewardByDay(vmContext, registration, gid)
+	if err != nil {
+		return nil, err
+	}
+	startIndex, endIndex := contracts.RewardIndexRange(registration, endIndex)
+	reward := contracts.SumRewardByDay(rewardList)
+	planNum, actualNum := uint64(0), uint64(0)
+	for _, rewardOfDay := range rewardList {
+		planNum = planNum + rewardOfDay.PlanNum
+		actualNum = actualNum + rewardOfDay.ActualNum
+	}
+	shareMap, left := contracts.CalcVoterRewardShares(rewardList, reward)
+	voterReward := make(map[string]string, len(shareMap))
+	for voter, share := range shareMap {
+		voterReward[voter.String()] = *bigIntToString(share)
+	}
+	return &PendingReward{
+		TotalReward:    *bigIntToString(reward),
+		ProducerReward: *bigIntToString(left),
+		VoterReward:    voterReward,
+		StartIndex:     uint64ToString(startIndex),
+		EndIndex:       uint64ToString(endIndex),
+		PlanNum:        uint64ToString(planNum),
+		ActualNum:      uint64ToString(actualNum),
+		BlockRate:      blockRate(planNum, actualNum),
+	}, nil
+}
+
+// GetVoterReward returns the shared reward which is not withdrawn by the voter yet
+func (r *RegisterApi) GetVoterReward(voterAddr types.Address) (string, error) {
+	snapshotBlock := r.chain.GetLatestSnapshotBlock()
+	vmContext, err := vm_context.NewVmContext(r.chain, &snapshotBlock.Hash, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	return *bigIntToString(abi.GetVoterReward(vmContext, voterAddr)), nil
+}
+
+func (r *RegisterApi) getRegistrationContext(gid types.Gid, name string) (vmctxt_interface.VmDatabase, *types.Registration, error) {
+	snapshotBlock := r.chain.GetLatestSnapshotBlock()
+	vmContext, err := vm_context.NewVmContext(r.chain, &snapshotBlock.Hash, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	registration := abi.GetRegistration(vmContext, gid, name)
+	if registration == nil {
+		return nil, nil, errors.New("registration not exist")
+	}
+	return vmContext, registration, nil
+}
+
+func blockRate(planNum, actualNum uint64) float64 {
+	if planNum == 0 {
+		return 0
+	}
+	return float64(actualNum) / float64(planNum)
+}
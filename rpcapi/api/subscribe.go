@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/rpc"
+	"github.com/vitelabs/go-vite/vite"
+)
+
+// SubscribeApi pushes chain changes to websocket and ipc clients, a rollback of the chain
+// is delivered as the same messages with removed set to true.
+type SubscribeApi struct {
+	es  *eventSystem
+	log log15.Logger
+}
+
+func NewSubscribeApi(vite *vite.Vite) *SubscribeApi {
+	return &SubscribeApi{
+		es:  sharedEventSystem(vite.Chain()),
+		log: log15.New("module", "rpc_api/subscribe_api"),
+	}
+}
+
+func (s SubscribeApi) String() string {
+	return "SubscribeApi"
+}
+
+type LogsFilterParam struct {
+	Addrs  []types.Address `json:"addrs"`
+	Topics [][]types.Hash  `json:"topics"`
+}
+
+func (s *SubscribeApi) NewSnapshotBlocks(ctx context.Context) (*rpc.Subscription, error) {
+	s.log.Info("NewSnapshotBlocks")
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	sub := &subscription{
+		id:         rpcSub.ID,
+		typ:        snapshotBlocksSubscription,
+		snapshotCh: make(chan []*SnapshotBlockMsg, subscribeNotifyChanSize),
+	}
+	s.es.subscribe(sub)
+
+	go func() {
+		defer s.es.unsubscribe(sub)
+		for {
+			select {
+			case msgs := <-sub.snapshotCh:
+				for _, msg := range msgs {
+					notifier.Notify(rpcSub.ID, msg)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+func (s *SubscribeApi) NewAccountBlocks(ctx context.Context, addrs []types.Address) (*rpc.Subscription, error) {
+	s.log.Info("NewAccountBlocks")
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	sub := &subscription{
+		id:        rpcSub.ID,
+		typ:       accountBlocksSubscription,
+		addrs:     addrSet(addrs),
+		accountCh: make(chan []*AccountBlockMsg, subscribeNotifyChanSize),
+	}
+	s.es.subscribe(sub)
+
+	go func() {
+		defer s.es.unsubscribe(sub)
+		for {
+			select {
+			case msgs := <-sub.accountCh:
+				for _, msg := range msgs {
+					notifier.Notify(rpcSub.ID, msg)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+func (s *SubscribeApi) NewLogs(ctx context.Context, param LogsFilterParam) (*rpc.Subscription, error) {
+	s.log.Info("NewLogs")
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	sub := &subscription{
+		id:     rpcSub.ID,
+		typ:    logsSubscription,
+		addrs:  addrSet(param.Addrs),
+		topics: param.Topics,
+		logsCh: make(chan []*LogsMsg, subscribeNotifyChanSize),
+	}
+	s.es.subscribe(sub)
+
+	go func() {
+		defer s.es.unsubscribe(sub)
+		for {
+			select {
+			case msgs := <-sub.logsCh:
+				for _, msg := range msgs {
+					notifier.Notify(rpcSub.ID, msg)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+func addrSet(addrs []types.Address) map[types.Address]struct{} {
+	set := make(map[types.Address]struct{}, len(addrs))
+	for _, addr := range addrs {
+		set[addr] = struct{}{}
+	}
+	return set
+}
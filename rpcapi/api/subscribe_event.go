@@ -0,0 +1,353 @@
+package api
+
+import (
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/rpc"
+	"github.com/vitelabs/go-vite/vm_context"
+)
+
+const (
+	snapshotBlocksSubscription = iota + 1
+	accountBlocksSubscription
+	logsSubscription
+)
+
+const (
+	subscribeEventChanSize  = 1024
+	subscribeNotifyChanSize = 128
+)
+
+type SnapshotBlockMsg struct {
+	Hash    types.Hash `json:"hash"`
+	Height  uint64     `json:"height"`
+	Removed bool       `json:"removed"`
+}
+
+type AccountBlockMsg struct {
+	Hash           types.Hash    `json:"hash"`
+	Height         uint64        `json:"height"`
+	AccountAddress types.Address `json:"accountAddress"`
+	Removed        bool          `json:"removed"`
+}
+
+type LogsMsg struct {
+	Log              *ledger.VmLog `json:"log"`
+	AccountBlockHash types.Hash    `json:"accountBlockHash"`
+	AccountHeight    uint64        `json:"accountHeight"`
+	Addr             types.Address `json:"addr"`
+	Removed          bool          `json:"removed"`
+}
+
+type subscription struct {
+	id  rpc.ID
+	typ int
+
+	addrs  map[types.Address]struct{}
+	topics [][]types.Hash
+
+	snapshotCh chan []*SnapshotBlockMsg
+	accountCh  chan []*AccountBlockMsg
+	logsCh     chan []*LogsMsg
+}
+
+func (s *subscription) matchAddr(addr types.Address) bool {
+	if len(s.addrs) == 0 {
+		return true
+	}
+	_, ok := s.addrs[addr]
+	return ok
+}
+
+// matchTopics follows the positional topic rule: an empty position is a wildcard,
+// otherwise the log topic at that position must equal one of the listed hashes.
+func (s *subscription) matchTopics(topics []types.Hash) bool {
+	if len(s.topics) > len(topics) {
+		return false
+	}
+	for i, sub := range s.topics {
+		if len(sub) == 0 {
+			continue
+		}
+		match := false
+		for _, topic := range sub {
+			if topic == topics[i] {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
+
+type chainEvent struct {
+	snapshotBlocks []*SnapshotBlockMsg
+	accountBlocks  []*AccountBlockMsg
+	logs           []*LogsMsg
+}
+
+// the event system of a chain is shared by the subscribe apis of all the endpoints
+var (
+	eventSystems     = make(map[chain.Chain]*eventSystem)
+	eventSystemsLock sync.Mutex
+)
+
+func sharedEventSystem(c chain.Chain) *eventSystem {
+	eventSystemsLock.Lock()
+	defer eventSystemsLock.Unlock()
+	es, ok := eventSystems[c]
+	if !ok {
+		es = newEventSystem(c)
+		eventSystems[c] = es
+	}
+	return es
+}
+
+// StopEventSystem unregisters the chain hooks of the shared event system and stops its loop
+func StopEventSystem(c chain.Chain) {
+	eventSystemsLock.Lock()
+	defer eventSystemsLock.Unlock()
+	if es, ok := eventSystems[c]; ok {
+		es.stop()
+		delete(eventSystems, c)
+	}
+}
+
+// eventSystem turns the chain insert and delete hooks into notifications for the
+// installed subscriptions. The hooks only enqueue events, all filtering happens in loop.
+// An event is dropped rather than blocking the chain when the queue is full.
+type eventSystem struct {
+	chain       chain.Chain
+	listenerIds []uint64
+
+	install   chan *subscription
+	uninstall chan *subscription
+	events    chan *chainEvent
+	closed    chan struct{}
+	wg        sync.WaitGroup
+
+	removedLogs     map[types.Hash]ledger.VmLogList
+	removedLogsLock sync.Mutex
+
+	log log15.Logger
+}
+
+func newEventSystem(c chain.Chain) *eventSystem {
+	es := &eventSystem{
+		chain:       c,
+		install:     make(chan *subscription),
+		uninstall:   make(chan *subscription),
+		events:      make(chan *chainEvent, subscribeEventChanSize),
+		closed:      make(chan struct{}),
+		removedLogs: make(map[types.Hash]ledger.VmLogList),
+		log:         log15.New("module", "rpc_api/subscribe_event"),
+	}
+
+	es.listenerIds = append(es.listenerIds,
+		c.RegisterInsertAccountBlocksSuccess(es.insertAccountBlocks),
+		c.RegisterDeleteAccountBlocks(es.prepareDeleteAccountBlocks),
+		c.RegisterDeleteAccountBlocksSuccess(es.deleteAccountBlocks),
+		c.RegisterInsertSnapshotBlocksSuccess(es.insertSnapshotBlocks),
+		c.RegisterDeleteSnapshotBlocksSuccess(es.deleteSnapshotBlocks))
+
+	es.wg.Add(1)
+	go es.loop()
+	return es
+}
+
+func (es *eventSystem) stop() {
+	for _, id := range es.listenerIds {
+		es.chain.UnRegister(id)
+	}
+	close(es.closed)
+	es.wg.Wait()
+}
+
+func (es *eventSystem) subscribe(sub *subscription) {
+	select {
+	case es.install <- sub:
+	case <-es.closed:
+	}
+}
+
+func (es *eventSystem) unsubscribe(sub *subscription) {
+	select {
+	case es.uninstall <- sub:
+	case <-es.closed:
+	}
+}
+
+func (es *eventSystem) send(e *chainEvent) {
+	select {
+	case es.events <- e:
+	default:
+		es.log.Warn("chain event dropped, the event queue is full")
+	}
+}
+
+func (es *eventSystem) loop() {
+	defer es.wg.Done()
+	subs := make(map[rpc.ID]*subscription)
+	for {
+		select {
+		case <-es.closed:
+			return
+		case sub := <-es.install:
+			subs[sub.id] = sub
+		case sub := <-es.uninstall:
+			delete(subs, sub.id)
+		case e := <-es.events:
+			for _, sub := range subs {
+				es.dispatch(sub, e)
+			}
+		}
+	}
+}
+
+func (es *eventSystem) dispatch(sub *subscription, e *chainEvent) {
+	switch sub.typ {
+	case snapshotBlocksSubscription:
+		if len(e.snapshotBlocks) == 0 {
+			return
+		}
+		select {
+		case sub.snapshotCh <- e.snapshotBlocks:
+		default:
+			es.log.Warn("snapshot block notification dropped", "id", sub.id)
+		}
+	case accountBlocksSubscription:
+		var msgs []*AccountBlockMsg
+		for _, m := range e.accountBlocks {
+			if sub.matchAddr(m.AccountAddress) {
+				msgs = append(msgs, m)
+			}
+		}
+		if len(msgs) == 0 {
+			return
+		}
+		select {
+		case sub.accountCh <- msgs:
+		default:
+			es.log.Warn("account block notification dropped", "id", sub.id)
+		}
+	case logsSubscription:
+		var msgs []*LogsMsg
+		for _, m := range e.logs {
+			if sub.matchAddr(m.Addr) && sub.matchTopics(m.Log.Topics) {
+				msgs = append(msgs, m)
+			}
+		}
+		if len(msgs) == 0 {
+			return
+		}
+		select {
+		case sub.logsCh <- msgs:
+		default:
+			es.log.Warn("logs notification dropped", "id", sub.id)
+		}
+	}
+}
+
+func (es *eventSystem) insertAccountBlocks(blocks []*vm_context.VmAccountBlock) {
+	e := &chainEvent{}
+	for _, vmBlock := range blocks {
+		block := vmBlock.AccountBlock
+		e.accountBlocks = append(e.accountBlocks, &AccountBlockMsg{
+			Hash:           block.Hash,
+			Height:         block.Height,
+			AccountAddress: block.AccountAddress,
+		})
+		if block.LogHash == nil || vmBlock.VmContext == nil {
+			continue
+		}
+		for _, vmLog := range vmBlock.VmContext.UnsavedCache().LogList() {
+			e.logs = append(e.logs, &LogsMsg{
+				Log:              vmLog,
+				AccountBlockHash: block.Hash,
+				AccountHeight:    block.Height,
+				Addr:             block.AccountAddress,
+			})
+		}
+	}
+	es.send(e)
+}
+
+// prepareDeleteAccountBlocks runs before the delete batch is committed, it is the last chance
+// to read the log lists of the blocks which are going to be removed.
+func (es *eventSystem) prepareDeleteAccountBlocks(batch *leveldb.Batch, subLedger map[types.Address][]*ledger.AccountBlock) error {
+	es.removedLogsLock.Lock()
+	defer es.removedLogsLock.Unlock()
+
+	es.removedLogs = make(map[types.Hash]ledger.VmLogList)
+	for _, blocks := range subLedger {
+		for _, block := range blocks {
+			if block.LogHash == nil {
+				continue
+			}
+			logList, err := es.chain.GetVmLogList(block.LogHash)
+			if err != nil {
+				es.log.Error("GetVmLogList failed, error is "+err.Error(), "method", "prepareDeleteAccountBlocks")
+				continue
+			}
+			es.removedLogs[block.Hash] = logList
+		}
+	}
+	return nil
+}
+
+func (es *eventSystem) deleteAccountBlocks(subLedger map[types.Address][]*ledger.AccountBlock) {
+	es.removedLogsLock.Lock()
+	removedLogs := es.removedLogs
+	es.removedLogs = make(map[types.Hash]ledger.VmLogList)
+	es.removedLogsLock.Unlock()
+
+	e := &chainEvent{}
+	for addr, blocks := range subLedger {
+		for _, block := range blocks {
+			e.accountBlocks = append(e.accountBlocks, &AccountBlockMsg{
+				Hash:           block.Hash,
+				Height:         block.Height,
+				AccountAddress: addr,
+				Removed:        true,
+			})
+			for _, vmLog := range removedLogs[block.Hash] {
+				e.logs = append(e.logs, &LogsMsg{
+					Log:              vmLog,
+					AccountBlockHash: block.Hash,
+					AccountHeight:    block.Height,
+					Addr:             addr,
+					Removed:          true,
+				})
+			}
+		}
+	}
+	es.send(e)
+}
+
+func (es *eventSystem) insertSnapshotBlocks(blocks []*ledger.SnapshotBlock) {
+	es.send(&chainEvent{snapshotBlocks: snapshotBlockMsgs(blocks, false)})
+}
+
+func (es *eventSystem) deleteSnapshotBlocks(blocks []*ledger.SnapshotBlock) {
+	es.send(&chainEvent{snapshotBlocks: snapshotBlockMsgs(blocks, true)})
+}
+
+func snapshotBlockMsgs(blocks []*ledger.SnapshotBlock, removed bool) []*SnapshotBlockMsg {
+	msgs := make([]*SnapshotBlockMsg, 0, len(blocks))
+	for _, block := range blocks {
+		msgs = append(msgs, &SnapshotBlockMsg{
+			Hash:    block.Hash,
+			Height:  block.Height,
+			Removed: removed,
+		})
+	}
+	return msgs
+}
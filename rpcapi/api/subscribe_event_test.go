@@ -0,0 +1,73 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/log15"
+)
+
+func TestSubscriptionMatchTopics(t *testing.T) {
+	t1 := types.DataHash([]byte{1})
+	t2 := types.DataHash([]byte{2})
+	t3 := types.DataHash([]byte{3})
+
+	cases := []struct {
+		filter [][]types.Hash
+		topics []types.Hash
+		match  bool
+	}{
+		{nil, []types.Hash{t1}, true},
+		{[][]types.Hash{{t1}}, []types.Hash{t1, t2}, true},
+		{[][]types.Hash{{t2}}, []types.Hash{t1, t2}, false},
+		{[][]types.Hash{{}, {t2, t3}}, []types.Hash{t1, t3}, true},
+		{[][]types.Hash{{t1}, {t2}}, []types.Hash{t1}, false},
+	}
+	for i, c := range cases {
+		sub := &subscription{topics: c.filter}
+		if got := sub.matchTopics(c.topics); got != c.match {
+			t.Fatalf("case %v: expected %v, got %v", i, c.match, got)
+		}
+	}
+}
+
+func TestEventSystemDispatch(t *testing.T) {
+	addr1, _, _ := types.CreateAddress()
+	addr2, _, _ := types.CreateAddress()
+	topic := types.DataHash([]byte("Transfer"))
+
+	es := &eventSystem{log: log15.New("module", "test")}
+	sub := &subscription{
+		typ:    logsSubscription,
+		addrs:  addrSet([]types.Address{addr1}),
+		topics: [][]types.Hash{{topic}},
+		logsCh: make(chan []*LogsMsg, 1),
+	}
+
+	es.dispatch(sub, &chainEvent{logs: []*LogsMsg{
+		{Log: &ledger.VmLog{Topics: []types.Hash{topic}}, Addr: addr1},
+		{Log: &ledger.VmLog{Topics: []types.Hash{topic}}, Addr: addr2},
+		{Log: &ledger.VmLog{Topics: []types.Hash{types.DataHash([]byte("Other"))}}, Addr: addr1, Removed: true},
+	}})
+
+	msgs := <-sub.logsCh
+	if len(msgs) != 1 || msgs[0].Addr != addr1 {
+		t.Fatalf("unexpected dispatch result %v", msgs)
+	}
+
+	// a full channel must not block the event loop
+	es.dispatch(sub, &chainEvent{logs: msgs})
+	es.dispatch(sub, &chainEvent{logs: msgs})
+}
+
+func TestEventSystemSendNotBlocking(t *testing.T) {
+	es := &eventSystem{events: make(chan *chainEvent, 1), log: log15.New("module", "test")}
+
+	// the hooks run on the chain insert path, a full queue drops the event
+	es.send(&chainEvent{})
+	es.send(&chainEvent{})
+	if len(es.events) != 1 {
+		t.Fatalf("unexpected queue length %v", len(es.events))
+	}
+}
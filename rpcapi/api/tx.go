@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"errors"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/crypto/ed25519"
@@ -8,7 +9,10 @@ import (
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/verifier"
 	"github.com/vitelabs/go-vite/vite"
+	"github.com/vitelabs/go-vite/vm_context"
 	"math/big"
+	"sort"
+	"strconv"
 )
 
 type Tx struct {
@@ -145,3 +149,101 @@ type SendTxWithPrivateKeyParam struct {
 	Difficulty   *string           `json:"difficulty,omitempty"`
 	PreBlockHash *types.Hash       `json:"preBlockHash,omitempty"`
 }
+
+type StorageDiffItem struct {
+	Key       []byte `json:"key"`
+	PrevValue []byte `json:"prevValue"`
+	Value     []byte `json:"value"`
+}
+
+type SimulateResult struct {
+	Block      *AccountBlock      `json:"block"`
+	Quota      string             `json:"quota"`
+	Fee        string             `json:"fee"`
+	SendBlocks []*AccountBlock    `json:"sendBlocks"`
+	Logs       ledger.VmLogList   `json:"logs"`
+	Storage    []*StorageDiffItem `json:"storage"`
+	Error      *string            `json:"error"`
+}
+
+// Simulate runs the generator and vm for an unsigned send or receive block on a new vm context,
+// nothing is added to the pool. The quota of the account is not checked, so that the quota used
+// is estimated for the accounts without pledge too. The error of the vm, such as revert, is returned in the result.
+func (t Tx) Simulate(block *AccountBlock) (*SimulateResult, error) {
+	log.Info("Simulate")
+	if block == nil {
+		return nil, errors.New("empty block")
+	}
+
+	lb, err := block.LedgerAccountBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var prevHash *types.Hash
+	if lb.Height > 1 {
+		prevHash = &lb.PrevHash
+	}
+	if lb.SnapshotHash == types.ZERO_HASH {
+		lb.SnapshotHash = t.vite.Chain().GetLatestSnapshotBlock().Hash
+	}
+	g, err := generator.NewGenerator(t.vite.Chain(), &lb.SnapshotHash, prevHash, &lb.AccountAddress)
+	if err != nil {
+		return nil, err
+	}
+	g.SetUnlimitedQuota(true)
+
+	genResult, err := g.GenerateWithBlock(lb, nil)
+	if err != nil {
+		newerr, _ := TryMakeConcernedError(err)
+		return nil, newerr
+	}
+
+	result := &SimulateResult{
+		SendBlocks: make([]*AccountBlock, 0),
+		Storage:    make([]*StorageDiffItem, 0),
+	}
+	if genResult.Err != nil {
+		errMsg := genResult.Err.Error()
+		result.Error = &errMsg
+	}
+	if len(genResult.BlockGenList) == 0 {
+		return result, nil
+	}
+
+	genBlock := genResult.BlockGenList[0]
+	if result.Block, err = ledgerToRpcBlock(genBlock.AccountBlock, t.vite.Chain()); err != nil {
+		return nil, err
+	}
+	result.Quota = strconv.FormatUint(genBlock.AccountBlock.Quota, 10)
+	result.Fee = "0"
+	if genBlock.AccountBlock.Fee != nil {
+		result.Fee = genBlock.AccountBlock.Fee.String()
+	}
+	for _, sendBlock := range genResult.BlockGenList[1:] {
+		rpcBlock, err := ledgerToRpcBlock(sendBlock.AccountBlock, t.vite.Chain())
+		if err != nil {
+			return nil, err
+		}
+		result.SendBlocks = append(result.SendBlocks, rpcBlock)
+	}
+
+	// the previous values are read from a new vm context on the same snapshot block and previous block
+	prevContext, err := vm_context.NewVmContext(t.vite.Chain(), &lb.SnapshotHash, prevHash, &lb.AccountAddress)
+	if err != nil {
+		return nil, err
+	}
+	unsavedCache := genBlock.VmContext.UnsavedCache()
+	result.Logs = unsavedCache.LogList()
+	for key, value := range unsavedCache.Storage() {
+		result.Storage = append(result.Storage, &StorageDiffItem{
+			Key:       []byte(key),
+			PrevValue: prevContext.GetStorage(&lb.AccountAddress, []byte(key)),
+			Value:     value,
+		})
+	}
+	sort.Slice(result.Storage, func(i, j int) bool {
+		return bytes.Compare(result.Storage[i].Key, result.Storage[j].Key) < 0
+	})
+	return result, nil
+}
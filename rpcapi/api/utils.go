@@ -5,7 +5,10 @@ import (
 	"github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 	"github.com/robfig/cron"
+	"github.com/vitelabs/go-vite/chain"
 	"github.com/vitelabs/go-vite/common"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
 	"math/big"
 	"path/filepath"
@@ -113,3 +116,62 @@ const (
 func getWithdrawTime(snapshotTime *time.Time, snapshotHeight uint64, withdrawHeight uint64) int64 {
 	return snapshotTime.Unix() + int64(withdrawHeight-snapshotHeight)*secondBetweenSnapshotBlocks
 }
+
+// getSnapshotBlock accepts the height or the hash of a snapshot block, an empty string means the latest one.
+func getSnapshotBlock(c chain.Chain, snapshot string) (*ledger.SnapshotBlock, error) {
+	if snapshot == "" {
+		return c.GetLatestSnapshotBlock(), nil
+	}
+
+	var block *ledger.SnapshotBlock
+	var err error
+	if len(snapshot) == 2*types.HashSize {
+		hash, hashErr := types.HexToHash(snapshot)
+		if hashErr != nil {
+			return nil, hashErr
+		}
+		block, err = c.GetSnapshotBlockHeadByHash(&hash)
+	} else {
+		height, parseErr := strconv.ParseUint(snapshot, 10, 64)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		block, err = c.GetSnapshotBlockHeadByHeight(height)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, ErrSnapshotBlockNotFound
+	}
+	return block, nil
+}
+
+// checkPruned makes sure the account blocks confirmed by the snapshot block are not pruned, otherwise the
+// confirmed block of an account would be resolved to an older one.
+func checkPruned(c chain.Chain, snapshotBlock *ledger.SnapshotBlock) error {
+	if snapshotBlock.Height <= c.PrunedHeight() {
+		return ErrLedgerPruned
+	}
+	return nil
+}
+
+// checkStateAt makes sure the state of addr confirmed by the snapshot block is still in the trie db,
+// the historical state may have been removed by the trie gc or the pruner.
+func checkStateAt(c chain.Chain, addr types.Address, snapshotBlock *ledger.SnapshotBlock) error {
+	if err := checkPruned(c, snapshotBlock); err != nil {
+		return err
+	}
+	accountBlock, err := c.GetConfirmAccountBlock(snapshotBlock.Height, &addr)
+	if err != nil {
+		return err
+	}
+	if accountBlock == nil || accountBlock.StateHash == (types.Hash{}) {
+		return nil
+	}
+	if stateTrie := c.GetStateTrie(&accountBlock.StateHash); stateTrie == nil || stateTrie.Root == nil {
+		return ErrStateNotFound
+	}
+	return nil
+}
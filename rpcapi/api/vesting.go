@@ -0,0 +1,97 @@
+package api
+
+import (
+	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/vite"
+	"github.com/vitelabs/go-vite/vm/contracts/abi"
+	"github.com/vitelabs/go-vite/vm_context"
+)
+
+type VestingApi struct {
+	chain chain.Chain
+	log   log15.Logger
+}
+
+func NewVestingApi(vite *vite.Vite) *VestingApi {
+	return &VestingApi{
+		chain: vite.Chain(),
+		log:   log15.New("module", "rpc_api/vesting_api"),
+	}
+}
+
+func (v VestingApi) String() string {
+	return "VestingApi"
+}
+
+// GetLockData builds the data of the send block locking its amount for the beneficial,
+// the cliff height and the vesting height are counted in snapshot blocks
+func (v *VestingApi) GetLockData(beneficial types.Address, cliffHeight uint64, vestingHeight uint64) ([]byte, error) {
+	return abi.ABIVesting.PackMethod(abi.MethodNameVestingLock, beneficial, cliffHeight, vestingHeight)
+}
+
+func (v *VestingApi) GetClaimData(vestingId types.Hash) ([]byte, error) {
+	return abi.ABIVesting.PackMethod(abi.MethodNameVestingClaim, vestingId)
+}
+
+type VestingInfo struct {
+	VestingId   types.Hash        `json:"vestingId"`
+	Locker      types.Address     `json:"locker"`
+	TokenId     types.TokenTypeId `json:"tokenId"`
+	Amount      string            `json:"amount"`
+	Claimed     string            `json:"claimed"`
+	Remaining   string            `json:"remaining"`
+	Claimable   string            `json:"claimable"`
+	StartHeight string            `json:"startHeight"`
+	CliffHeight string            `json:"cliffHeight"`
+	EndHeight   string            `json:"endHeight"`
+	CliffTime   int64             `json:"cliffTime"`
+	EndTime     int64             `json:"endTime"`
+}
+
+func newVestingInfo(info *abi.VestingInfo, snapshotBlock *ledger.SnapshotBlock) *VestingInfo {
+	return &VestingInfo{
+		VestingId:   info.VestingId,
+		Locker:      info.Locker,
+		TokenId:     info.TokenId,
+		Amount:      *bigIntToString(info.Amount),
+		Claimed:     *bigIntToString(info.Claimed),
+		Remaining:   *bigIntToString(info.Remaining()),
+		Claimable:   *bigIntToString(info.Claimable(snapshotBlock.Height)),
+		StartHeight: uint64ToString(info.StartHeight),
+		CliffHeight: uint64ToString(info.CliffHeight),
+		EndHeight:   uint64ToString(info.EndHeight),
+		CliffTime:   getWithdrawTime(snapshotBlock.Timestamp, snapshotBlock.Height, info.CliffHeight),
+		EndTime:     getWithdrawTime(snapshotBlock.Timestamp, snapshotBlock.Height, info.EndHeight),
+	}
+}
+
+// GetVestingList returns the vestings of the beneficial with the remaining and claimable amounts at the latest snapshot block
+func (v *VestingApi) GetVestingList(beneficial types.Address) ([]*VestingInfo, error) {
+	snapshotBlock := v.chain.GetLatestSnapshotBlock()
+	vmContext, err := vm_context.NewVmContext(v.chain, &snapshotBlock.Hash, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	list := abi.GetVestingInfoList(vmContext, beneficial)
+	targetList := make([]*VestingInfo, len(list))
+	for i, info := range list {
+		targetList[i] = newVestingInfo(info, snapshotBlock)
+	}
+	return targetList, nil
+}
+
+func (v *VestingApi) GetVestingInfo(beneficial types.Address, vestingId types.Hash) (*VestingInfo, error) {
+	snapshotBlock := v.chain.GetLatestSnapshotBlock()
+	vmContext, err := vm_context.NewVmContext(v.chain, &snapshotBlock.Hash, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	info := abi.GetVestingInfo(vmContext, beneficial, vestingId)
+	if info == nil {
+		return nil, nil
+	}
+	return newVestingInfo(info, snapshotBlock), nil
+}
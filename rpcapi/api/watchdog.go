@@ -0,0 +1,59 @@
+package api
+
+import (
+	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/vite"
+	"github.com/vitelabs/go-vite/watchdog"
+)
+
+type WatchdogApi struct {
+	vite *vite.Vite
+	log  log15.Logger
+}
+
+func NewWatchdogApi(vite *vite.Vite) *WatchdogApi {
+	return &WatchdogApi{
+		vite: vite,
+		log:  log15.New("module", "rpc_api/watchdog_api"),
+	}
+}
+
+func (w WatchdogApi) String() string {
+	return "WatchdogApi"
+}
+
+type WatchdogStatus struct {
+	Enabled   bool              `json:"enabled"`
+	LastCheck int64             `json:"lastCheck"`
+	Firing    int               `json:"firing"`
+	Alerts    []*watchdog.Alert `json:"alerts"`
+}
+
+// GetStatus returns the firing alerts and the resolved ones, the watchdog is disabled unless it's enabled in the config
+func (w *WatchdogApi) GetStatus() *WatchdogStatus {
+	wd := w.vite.Watchdog()
+	if wd == nil {
+		return &WatchdogStatus{Alerts: []*watchdog.Alert{}}
+	}
+	status := &WatchdogStatus{Enabled: true, Alerts: wd.Alerts()}
+	if lastCheck := wd.LastCheck(); !lastCheck.IsZero() {
+		status.LastCheck = lastCheck.Unix()
+	}
+	for _, alert := range status.Alerts {
+		if alert.State == watchdog.StateFiring {
+			status.Firing++
+		}
+	}
+	return status
+}
+
+// GetFiringAlerts returns the alerts firing now
+func (w *WatchdogApi) GetFiringAlerts() []*watchdog.Alert {
+	alerts := make([]*watchdog.Alert, 0)
+	for _, alert := range w.GetStatus().Alerts {
+		if alert.State == watchdog.StateFiring {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
@@ -12,6 +12,11 @@ func Init(dir, lvl string, testApi_prikey, testApi_tti string) {
 	api.InitGetTestTokenLimitPolicy()
 }
 
+// Stop releases the resources shared by the apis of all the endpoints
+func Stop(vite *vite.Vite) {
+	api.StopEventSystem(vite.Chain())
+}
+
 func GetApi(vite *vite.Vite, apiModule string) rpc.API {
 	switch apiModule {
 	// private IPC
@@ -94,6 +99,41 @@ func GetApi(vite *vite.Vite, apiModule string) rpc.API {
 			Service:   api.NewPledgeApi(vite),
 			Public:    true,
 		}
+	case "multisig":
+		return rpc.API{
+			Namespace: "multisig",
+			Version:   "1.0",
+			Service:   api.NewMultisigApi(vite),
+			Public:    true,
+		}
+	case "vesting":
+		return rpc.API{
+			Namespace: "vesting",
+			Version:   "1.0",
+			Service:   api.NewVestingApi(vite),
+			Public:    true,
+		}
+	case "producer":
+		return rpc.API{
+			Namespace: "producer",
+			Version:   "1.0",
+			Service:   api.NewProducerApi(vite),
+			Public:    true,
+		}
+	case "consensus":
+		return rpc.API{
+			Namespace: "consensus",
+			Version:   "1.0",
+			Service:   api.NewConsensusApi(vite),
+			Public:    true,
+		}
+	case "watchdog":
+		return rpc.API{
+			Namespace: "watchdog",
+			Version:   "1.0",
+			Service:   api.NewWatchdogApi(vite),
+			Public:    true,
+		}
 	case "consensusGroup":
 		return rpc.API{
 			Namespace: "consensusGroup",
@@ -108,6 +148,13 @@ func GetApi(vite *vite.Vite, apiModule string) rpc.API {
 			Service:   api.NewTxApi(vite),
 			Public:    true,
 		}
+	case "subscribe":
+		return rpc.API{
+			Namespace: "subscribe",
+			Version:   "1.0",
+			Service:   api.NewSubscribeApi(vite),
+			Public:    true,
+		}
 		// test
 	case "testapi":
 		return rpc.API{
@@ -144,9 +191,9 @@ func GetApis(vite *vite.Vite, apiModule ...string) []rpc.API {
 }
 
 func GetPublicApis(vite *vite.Vite) []rpc.API {
-	return GetApis(vite, "ledger", "public_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "testapi", "pow", "tx", "debug", "dashboard")
+	return GetApis(vite, "ledger", "public_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "multisig", "vesting", "consensusGroup", "consensus", "testapi", "pow", "tx", "subscribe", "debug", "dashboard")
 }
 
 func GetAllApis(vite *vite.Vite) []rpc.API {
-	return GetApis(vite, "ledger", "wallet", "private_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "testapi", "pow", "tx", "debug", "dashboard")
+	return GetApis(vite, "ledger", "wallet", "private_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "multisig", "vesting", "producer", "consensusGroup", "consensus", "watchdog", "testapi", "pow", "tx", "subscribe", "debug", "dashboard")
 }
@@ -0,0 +1,83 @@
+package signer
+
+import (
+	"net"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/rpc"
+)
+
+type SignResult struct {
+	Signature []byte `json:"signature"`
+	PublicKey []byte `json:"publicKey"`
+}
+
+// SignerApi is served by the signing daemon, it signs with a local signer. The blocks are decoded
+// and hashed by the daemon, every snapshot block is checked against the protection before signing,
+// and only the receive blocks of the contracts produced by the address are signed as account blocks,
+// so that a compromised node can not sign transfers from the account of the producer
+type SignerApi struct {
+	signer     Signer
+	protection *Protection
+}
+
+func NewSignerApi(signer Signer, protection *Protection) *SignerApi {
+	return &SignerApi{signer: signer, protection: protection}
+}
+
+func (s SignerApi) String() string {
+	return "SignerApi"
+}
+
+func (s *SignerApi) CheckAddress(addr types.Address) error {
+	return s.signer.CheckAddress(addr)
+}
+
+func (s *SignerApi) SignAccountBlock(addr types.Address, data []byte) (*SignResult, error) {
+	block := new(ledger.AccountBlock)
+	if err := block.Deserialize(data); err != nil || block.Height == 0 {
+		return nil, ErrInvalidBlock
+	}
+	if !block.IsReceiveBlock() || block.AccountAddress == addr {
+		return nil, ErrInvalidBlock
+	}
+	signedData, pubkey, err := s.signer.SignAccountBlock(addr, block)
+	if err != nil {
+		return nil, err
+	}
+	return &SignResult{signedData, pubkey}, nil
+}
+
+func (s *SignerApi) SignSnapshotBlock(addr types.Address, gid types.Gid, index uint64, data []byte) (*SignResult, error) {
+	block := new(ledger.SnapshotBlock)
+	if err := block.Deserialize(data); err != nil || block.Height == 0 {
+		return nil, ErrInvalidBlock
+	}
+	if err := s.signer.CheckAddress(addr); err != nil {
+		return nil, err
+	}
+	slot := NewSlot(gid, index, block)
+	if err := s.protection.Protect(addr, slot); err != nil {
+		return nil, err
+	}
+	signedData, pubkey, err := s.signer.SignSnapshotBlock(addr, gid, index, block)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("sign snapshot block.", "addr", addr, "slot", slot)
+	return &SignResult{signedData, pubkey}, nil
+}
+
+// StartDaemon serves the signer api on the local socket of the endpoint
+func StartDaemon(endpoint string, signer Signer, protection *Protection) (net.Listener, error) {
+	listener, _, err := rpc.StartIPCEndpoint(endpoint, []rpc.API{
+		{
+			Namespace: "signer",
+			Version:   "1.0",
+			Service:   NewSignerApi(signer, protection),
+			Public:    true,
+		},
+	})
+	return listener, err
+}
@@ -0,0 +1,138 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/vitelabs/go-vite/common/types"
+)
+
+var ErrSlotConflict = errors.New("conflict with a signed slot")
+
+// Protection records the last signed slot of every producer and consensus group, and refuses
+// to sign a different block for the same slot or height, or for an earlier slot or period.
+// The records are saved to the file before the signature is returned, if the file is set.
+type Protection struct {
+	file  string
+	slots map[string]*Slot
+	mu    sync.Mutex
+}
+
+func NewProtection(file string) (*Protection, error) {
+	p := &Protection{file: file, slots: make(map[string]*Slot)}
+	if file == "" {
+		return p, nil
+	}
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &p.slots); err != nil {
+		return nil, errors.Wrap(err, "invalid protection file")
+	}
+	return p, nil
+}
+
+func protectionKey(addr types.Address, gid types.Gid) string {
+	return addr.String() + "_" + gid.String()
+}
+
+// Protect checks the slot against the last signed slot of the address and records it
+func (p *Protection) Protect(addr types.Address, slot Slot) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := protectionKey(addr, slot.Gid)
+	last, ok := p.slots[key]
+	if ok {
+		if last.Hash == slot.Hash {
+			return nil
+		}
+		if slot.Timestamp <= last.Timestamp || slot.Index < last.Index || slot.Height == last.Height {
+			slog.Error("refuse to sign a conflict snapshot block.", "addr", addr, "slot", slot, "last", last)
+			return errors.Wrap(ErrSlotConflict, fmt.Sprintf("last signed %s", last))
+		}
+	}
+	p.slots[key] = &slot
+	if err := p.save(); err != nil {
+		if ok {
+			p.slots[key] = last
+		} else {
+			delete(p.slots, key)
+		}
+		return err
+	}
+	return nil
+}
+
+// LastSlot returns the last signed slot of the address in the consensus group
+func (p *Protection) LastSlot(addr types.Address, gid types.Gid) *Slot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if last, ok := p.slots[protectionKey(addr, gid)]; ok {
+		slot := *last
+		return &slot
+	}
+	return nil
+}
+
+// Export writes all the signed slots, the record can be imported on the host the producer is moved to
+func (p *Protection) Export(w io.Writer) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	data, err := json.MarshalIndent(p.slots, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Import merges the exported slots into the record, a slot only replaces a recorded one if it is later
+func (p *Protection) Import(r io.Reader) error {
+	slots := make(map[string]*Slot)
+	if err := json.NewDecoder(r).Decode(&slots); err != nil {
+		return errors.Wrap(err, "invalid protection record")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	old := make(map[string]*Slot, len(p.slots))
+	for key, slot := range p.slots {
+		old[key] = slot
+	}
+	for key, slot := range slots {
+		if last, ok := p.slots[key]; ok && slot.Timestamp <= last.Timestamp {
+			continue
+		}
+		p.slots[key] = slot
+	}
+	if err := p.save(); err != nil {
+		p.slots = old
+		return err
+	}
+	return nil
+}
+
+func (p *Protection) save() error {
+	if p.file == "" {
+		return nil
+	}
+	data, err := json.Marshal(p.slots)
+	if err != nil {
+		return err
+	}
+	tmpFile := p.file + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, p.file)
+}
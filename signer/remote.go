@@ -0,0 +1,87 @@
+package signer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/rpc"
+)
+
+const remoteTimeout = 3 * time.Second
+
+// RemoteSigner asks a signing daemon listening on a local socket to sign,
+// the connection is dialed lazily and dialed again after a failed call
+type RemoteSigner struct {
+	endpoint string
+	client   *rpc.Client
+	mu       sync.Mutex
+}
+
+func NewRemoteSigner(endpoint string) *RemoteSigner {
+	return &RemoteSigner{endpoint: endpoint}
+}
+
+func (s *RemoteSigner) call(result interface{}, method string, args ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteTimeout)
+	defer cancel()
+	if s.client == nil {
+		client, err := rpc.DialIPC(ctx, s.endpoint)
+		if err != nil {
+			return err
+		}
+		s.client = client
+	}
+	err := s.client.CallContext(ctx, result, method, args...)
+	if err != nil {
+		if _, ok := err.(rpc.Error); !ok {
+			s.client.Close()
+			s.client = nil
+		}
+	}
+	return err
+}
+
+func (s *RemoteSigner) CheckAddress(addr types.Address) error {
+	return s.call(nil, "signer_checkAddress", addr)
+}
+
+// SignAccountBlock sends the serialized block, the daemon computes the hash to sign itself
+func (s *RemoteSigner) SignAccountBlock(addr types.Address, block *ledger.AccountBlock) (signedData, pubkey []byte, err error) {
+	data, err := block.Serialize()
+	if err != nil {
+		return nil, nil, err
+	}
+	result := new(SignResult)
+	if err := s.call(result, "signer_signAccountBlock", addr, data); err != nil {
+		return nil, nil, err
+	}
+	return result.Signature, result.PublicKey, nil
+}
+
+// SignSnapshotBlock sends the serialized block without the snapshot content, which is not hashed
+func (s *RemoteSigner) SignSnapshotBlock(addr types.Address, gid types.Gid, index uint64, block *ledger.SnapshotBlock) (signedData, pubkey []byte, err error) {
+	data, err := block.DbSerialize()
+	if err != nil {
+		return nil, nil, err
+	}
+	result := new(SignResult)
+	if err := s.call(result, "signer_signSnapshotBlock", addr, gid, index, data); err != nil {
+		return nil, nil, err
+	}
+	return result.Signature, result.PublicKey, nil
+}
+
+func (s *RemoteSigner) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client != nil {
+		s.client.Close()
+		s.client = nil
+	}
+}
@@ -0,0 +1,89 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/wallet"
+)
+
+// Package signer keeps the keys of the block producer away from the producing code,
+// the keys may be held by the node itself or by an external signing daemon
+
+var (
+	slog = log15.New("module", "signer")
+
+	ErrAddressLocked = errors.New("address is not unlocked in the signer")
+	ErrInvalidBlock  = errors.New("invalid block to sign")
+)
+
+// Slot is the consensus slot of a snapshot block, a producer must never sign two different blocks for one slot
+type Slot struct {
+	Gid       types.Gid  `json:"gid"`
+	Index     uint64     `json:"index"`
+	Timestamp int64      `json:"timestamp"`
+	Height    uint64     `json:"height"`
+	Hash      types.Hash `json:"hash"`
+}
+
+func (s Slot) String() string {
+	return fmt.Sprintf("gid:%s, index:%d, timestamp:%d, height:%d, hash:%s", s.Gid, s.Index, s.Timestamp, s.Height, s.Hash)
+}
+
+// NewSlot returns the slot of a snapshot block produced in the consensus period of the index,
+// the hash is computed from the block instead of taken from it
+func NewSlot(gid types.Gid, index uint64, block *ledger.SnapshotBlock) Slot {
+	return Slot{
+		Gid:       gid,
+		Index:     index,
+		Timestamp: block.Timestamp.Unix(),
+		Height:    block.Height,
+		Hash:      block.ComputeHash(),
+	}
+}
+
+// Signer only signs the hashes it computes from the blocks, so that the keys of a producer
+// can not be used to sign a snapshot block hash without the slot protection
+type Signer interface {
+	// CheckAddress returns an error if the signer can not sign for the address
+	CheckAddress(addr types.Address) error
+	// SignAccountBlock signs the hash of an account block, such as a receive block of a contract, for the address
+	SignAccountBlock(addr types.Address, block *ledger.AccountBlock) (signedData, pubkey []byte, err error)
+	// SignSnapshotBlock signs the hash of a snapshot block produced by the address in the consensus period of the index
+	SignSnapshotBlock(addr types.Address, gid types.Gid, index uint64, block *ledger.SnapshotBlock) (signedData, pubkey []byte, err error)
+}
+
+// LocalSigner signs with the keys of the entropy stores unlocked in the node
+type LocalSigner struct {
+	wt *wallet.Manager
+}
+
+func NewLocalSigner(wt *wallet.Manager) *LocalSigner {
+	return &LocalSigner{wt: wt}
+}
+
+func (s *LocalSigner) CheckAddress(addr types.Address) error {
+	if !s.wt.GlobalCheckAddrUnlock(addr) {
+		return ErrAddressLocked
+	}
+	return nil
+}
+
+func (s *LocalSigner) signData(addr types.Address, data []byte) (signedData, pubkey []byte, err error) {
+	_, key, _, err := s.wt.GlobalFindAddr(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key.SignData(data)
+}
+
+func (s *LocalSigner) SignAccountBlock(addr types.Address, block *ledger.AccountBlock) (signedData, pubkey []byte, err error) {
+	return s.signData(addr, block.ComputeHash().Bytes())
+}
+
+func (s *LocalSigner) SignSnapshotBlock(addr types.Address, gid types.Gid, index uint64, block *ledger.SnapshotBlock) (signedData, pubkey []byte, err error) {
+	return s.signData(addr, block.ComputeHash().Bytes())
+}
@@ -0,0 +1,210 @@
+package signer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+type testSigner struct {
+	addr types.Address
+}
+
+func (s *testSigner) CheckAddress(addr types.Address) error {
+	if addr != s.addr {
+		return ErrAddressLocked
+	}
+	return nil
+}
+
+func (s *testSigner) signData(addr types.Address, data []byte) (signedData, pubkey []byte, err error) {
+	if err := s.CheckAddress(addr); err != nil {
+		return nil, nil, err
+	}
+	return append([]byte("signed:"), data...), addr.Bytes(), nil
+}
+
+func (s *testSigner) SignAccountBlock(addr types.Address, block *ledger.AccountBlock) (signedData, pubkey []byte, err error) {
+	return s.signData(addr, block.ComputeHash().Bytes())
+}
+
+func (s *testSigner) SignSnapshotBlock(addr types.Address, gid types.Gid, index uint64, block *ledger.SnapshotBlock) (signedData, pubkey []byte, err error) {
+	return s.signData(addr, block.ComputeHash().Bytes())
+}
+
+func testSlot(timestamp int64, height uint64, hash string) Slot {
+	return Slot{Gid: types.SNAPSHOT_GID, Timestamp: timestamp, Height: height, Hash: types.DataHash([]byte(hash))}
+}
+
+func testSnapshotBlock(timestamp int64, height uint64, state string) *ledger.SnapshotBlock {
+	t := time.Unix(timestamp, 0)
+	return &ledger.SnapshotBlock{Height: height, Timestamp: &t, StateHash: types.DataHash([]byte(state))}
+}
+
+func TestProtection(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "protection.json")
+
+	addr, _, _ := types.CreateAddress()
+	p, err := NewProtection(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Protect(addr, testSlot(10, 1, "a")); err != nil {
+		t.Fatal(err)
+	}
+	// signing the same block again is allowed
+	if err := p.Protect(addr, testSlot(10, 1, "a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Protect(addr, testSlot(10, 1, "b")); errors.Cause(err) != ErrSlotConflict {
+		t.Fatalf("sign another block in the same slot, err: %v", err)
+	}
+	if err := p.Protect(addr, testSlot(9, 1, "c")); errors.Cause(err) != ErrSlotConflict {
+		t.Fatalf("sign a block in an earlier slot, err: %v", err)
+	}
+	if err := p.Protect(addr, testSlot(12, 1, "c")); errors.Cause(err) != ErrSlotConflict {
+		t.Fatalf("sign another block at the same height, err: %v", err)
+	}
+	if err := p.Protect(addr, testSlot(11, 2, "d")); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewProtection(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := reloaded.LastSlot(addr, types.SNAPSHOT_GID)
+	if last == nil || *last != testSlot(11, 2, "d") {
+		t.Fatalf("last slot not persisted, %v", last)
+	}
+	if err := reloaded.Protect(addr, testSlot(11, 2, "e")); errors.Cause(err) != ErrSlotConflict {
+		t.Fatalf("sign another block in a persisted slot, err: %v", err)
+	}
+}
+
+func TestRemoteSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	endpoint := filepath.Join(dir, "signer.ipc")
+
+	addr, _, _ := types.CreateAddress()
+	other, _, _ := types.CreateAddress()
+	p, _ := NewProtection("")
+	listener, err := StartDaemon(endpoint, &testSigner{addr}, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	remote := NewRemoteSigner(endpoint)
+	defer remote.Close()
+	if err := remote.CheckAddress(addr); err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.CheckAddress(other); err == nil {
+		t.Fatal("expected an error for a locked address")
+	}
+
+	blockTime := time.Unix(10, 0)
+	contract, _, _ := types.CreateAddress()
+	accountBlock := &ledger.AccountBlock{
+		BlockType:      ledger.BlockTypeReceive,
+		Height:         1,
+		AccountAddress: contract,
+		FromBlockHash:  types.DataHash([]byte("send")),
+		Timestamp:      &blockTime,
+	}
+	signedData, pubkey, err := remote.SignAccountBlock(addr, accountBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := accountBlock.ComputeHash()
+	if !bytes.Equal(signedData, append([]byte("signed:"), hash.Bytes()...)) || !bytes.Equal(pubkey, addr.Bytes()) {
+		t.Fatalf("unexpected signature %s %x", signedData, pubkey)
+	}
+
+	// only the receive blocks of the contracts are signed, not the blocks of the producer account
+	sendBlock := &ledger.AccountBlock{
+		BlockType:      ledger.BlockTypeSendCall,
+		Height:         2,
+		AccountAddress: addr,
+		ToAddress:      other,
+		Amount:         big.NewInt(100),
+		TokenId:        ledger.ViteTokenId,
+		Timestamp:      &blockTime,
+	}
+	if _, _, err := remote.SignAccountBlock(addr, sendBlock); err == nil {
+		t.Fatal("expected the daemon to refuse a send block")
+	}
+	sendBlock.AccountAddress = contract
+	if _, _, err := remote.SignAccountBlock(addr, sendBlock); err == nil {
+		t.Fatal("expected the daemon to refuse a send block of a contract")
+	}
+	accountBlock.AccountAddress = addr
+	if _, _, err := remote.SignAccountBlock(addr, accountBlock); err == nil {
+		t.Fatal("expected the daemon to refuse a receive block of the producer account")
+	}
+
+	// the daemon signs the hash it computes, not the hash in the block
+	block := testSnapshotBlock(10, 1, "a")
+	block.Hash = types.DataHash([]byte("forged"))
+	signedData, _, err = remote.SignSnapshotBlock(addr, types.SNAPSHOT_GID, 1, block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash = block.ComputeHash()
+	if !bytes.Equal(signedData, append([]byte("signed:"), hash.Bytes()...)) {
+		t.Fatalf("unexpected signature %s", signedData)
+	}
+	if _, _, err := remote.SignSnapshotBlock(addr, types.SNAPSHOT_GID, 1, testSnapshotBlock(10, 1, "b")); err == nil {
+		t.Fatal("expected the daemon to refuse a conflict block")
+	}
+	if last := p.LastSlot(addr, types.SNAPSHOT_GID); last == nil || *last != NewSlot(types.SNAPSHOT_GID, 1, block) {
+		t.Fatalf("unexpected last slot %v", last)
+	}
+}
+
+func TestProtectionExportImport(t *testing.T) {
+	addr, _, _ := types.CreateAddress()
+	other, _, _ := types.CreateAddress()
+	from, _ := NewProtection("")
+	from.Protect(addr, testSlot(20, 2, "a"))
+	from.Protect(other, testSlot(5, 1, "b"))
+
+	buf := new(bytes.Buffer)
+	if err := from.Export(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	to, _ := NewProtection("")
+	to.Protect(other, testSlot(8, 3, "c"))
+	if err := to.Import(buf); err != nil {
+		t.Fatal(err)
+	}
+	if last := to.LastSlot(addr, types.SNAPSHOT_GID); last == nil || *last != testSlot(20, 2, "a") {
+		t.Fatalf("slot not imported, %v", last)
+	}
+	// the imported slot is earlier than the recorded one
+	if last := to.LastSlot(other, types.SNAPSHOT_GID); last == nil || *last != testSlot(8, 3, "c") {
+		t.Fatalf("later slot replaced by import, %v", last)
+	}
+	if err := to.Protect(addr, testSlot(20, 2, "d")); errors.Cause(err) != ErrSlotConflict {
+		t.Fatalf("sign another block in an imported slot, err: %v", err)
+	}
+}
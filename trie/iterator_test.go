@@ -1,7 +1,10 @@
 package trie
 
 import (
+	"bytes"
 	"fmt"
+	"math/rand"
+	"sort"
 	"testing"
 )
 
@@ -86,3 +89,58 @@ func TestNewIterator(t *testing.T) {
 	}
 	fmt.Println()
 }
+
+func TestSortedIterator(t *testing.T) {
+	trie, _, close := newTempTrie()
+	defer close()
+
+	values := make(map[string][]byte)
+	for i := 0; i < 500; i++ {
+		key := make([]byte, 1+rand.Intn(4))
+		for j := range key {
+			key[j] = byte('a' + rand.Intn(4))
+		}
+		values[string(key)] = []byte(fmt.Sprintf("value%d", i))
+		trie.SetValue(key, values[string(key)])
+	}
+	var keys []string
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	cases := []struct {
+		prefix []byte
+		after  []byte
+	}{
+		{nil, nil},
+		{[]byte("a"), nil},
+		{nil, []byte("bc")},
+		{[]byte("b"), []byte("bcd")},
+		{[]byte("cc"), []byte("d")},
+	}
+	for _, c := range cases {
+		var expected []string
+		for _, key := range keys {
+			if bytes.HasPrefix([]byte(key), c.prefix) && (c.after == nil || key > string(c.after)) {
+				expected = append(expected, key)
+			}
+		}
+
+		var got []string
+		iterator := trie.NewSortedIterator(c.prefix, c.after)
+		for {
+			key, value, ok := iterator.Next()
+			if !ok {
+				break
+			}
+			if !bytes.Equal(value, values[string(key)]) {
+				t.Fatalf("unexpected value of %s: %s", key, value)
+			}
+			got = append(got, string(key))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Fatalf("prefix %s after %s: expected %v, got %v", c.prefix, c.after, expected, got)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package trie
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/crypto"
+)
+
+var ErrInvalidProof = errors.New("invalid proof")
+
+// Prove returns the nodes on the path from the root to the leaf of key, every node is serialized by DbSerialize.
+// When the leaf is a hash node, the referenced value is appended as the last item. If key is not in the trie,
+// the returned nodes prove its absence.
+func (trie *Trie) Prove(key []byte) ([][]byte, error) {
+	var proof [][]byte
+
+	node := trie.Root
+	for node != nil {
+		buf, err := node.DbSerialize()
+		if err != nil {
+			return nil, err
+		}
+		proof = append(proof, buf)
+
+		var next *TrieNode
+		switch node.NodeType() {
+		case TRIE_FULL_NODE:
+			if len(key) == 0 {
+				next = node.child
+			} else {
+				next = node.children[key[0]]
+				key = key[1:]
+			}
+		case TRIE_SHORT_NODE:
+			if !bytes.HasPrefix(key, node.key) {
+				return proof, nil
+			}
+			key = key[len(node.key):]
+			next = node.child
+		case TRIE_HASH_NODE:
+			if len(key) == 0 {
+				value, err := trie.getRefValue(node.value)
+				if err != nil {
+					return nil, err
+				}
+				proof = append(proof, value)
+			}
+			return proof, nil
+		default:
+			return proof, nil
+		}
+		node = next
+	}
+	return proof, nil
+}
+
+// VerifyProof checks the proof generated by Prove against rootHash, it returns the value of key,
+// or nil when the proof shows that key is not in the trie.
+func VerifyProof(rootHash types.Hash, key []byte, proof [][]byte) ([]byte, error) {
+	expectedHash := rootHash
+	for index := 0; index < len(proof); index++ {
+		node := &TrieNode{}
+		if err := node.DbDeserialize(proof[index]); err != nil {
+			return nil, err
+		}
+		if *node.Hash() != expectedHash {
+			return nil, ErrInvalidProof
+		}
+
+		isLast := index == len(proof)-1
+
+		var next *TrieNode
+		switch node.NodeType() {
+		case TRIE_FULL_NODE:
+			if len(key) == 0 {
+				next = node.child
+			} else {
+				next = node.children[key[0]]
+				key = key[1:]
+			}
+		case TRIE_SHORT_NODE:
+			if !bytes.HasPrefix(key, node.key) {
+				if !isLast {
+					return nil, ErrInvalidProof
+				}
+				return nil, nil
+			}
+			key = key[len(node.key):]
+			next = node.child
+		case TRIE_VALUE_NODE:
+			if !isLast {
+				return nil, ErrInvalidProof
+			}
+			if len(key) != 0 {
+				return nil, nil
+			}
+			return node.value, nil
+		case TRIE_HASH_NODE:
+			if len(key) != 0 {
+				if !isLast {
+					return nil, ErrInvalidProof
+				}
+				return nil, nil
+			}
+			if index != len(proof)-2 {
+				return nil, ErrInvalidProof
+			}
+			value := proof[index+1]
+			if !bytes.Equal(crypto.Hash256(value), node.value) {
+				return nil, ErrInvalidProof
+			}
+			return value, nil
+		default:
+			return nil, ErrInvalidProof
+		}
+
+		if next == nil {
+			if !isLast {
+				return nil, ErrInvalidProof
+			}
+			return nil, nil
+		}
+		expectedHash = *next.Hash()
+	}
+	return nil, ErrInvalidProof
+}
@@ -0,0 +1,92 @@
+package trie
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vitelabs/go-vite/chain_db/database"
+)
+
+// newTempTrie opens a trie on a db in a temporary directory, the directory is removed by the returned func
+func newTempTrie() (*Trie, *leveldb.DB, func()) {
+	dir, _ := ioutil.TempDir("", "trie")
+	db, _ := database.NewLevelDb(dir)
+	return NewTrie(db, nil, NewTrieNodePool()), db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestProve(t *testing.T) {
+	trie, _, close := newTempTrie()
+	defer close()
+
+	values := map[string][]byte{
+		"":         []byte("root"),
+		"tesab":    []byte("short value"),
+		"tesabcd":  bytes.Repeat([]byte("long value"), 10),
+		"tesabce":  []byte("sibling"),
+		"abcdefgh": []byte("other branch"),
+	}
+	for key, value := range values {
+		trie.SetValue([]byte(key), value)
+	}
+	rootHash := *trie.Hash()
+
+	for key, value := range values {
+		proof, err := trie.Prove([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		provedValue, err := VerifyProof(rootHash, []byte(key), proof)
+		if err != nil {
+			t.Fatalf("verify %s failed, error is %s", key, err)
+		}
+		if !bytes.Equal(provedValue, value) {
+			t.Fatalf("verify %s: expected %s, got %s", key, value, provedValue)
+		}
+	}
+
+	for _, key := range []string{"tes", "tesabc", "tesabcdx", "zzz"} {
+		proof, err := trie.Prove([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		provedValue, err := VerifyProof(rootHash, []byte(key), proof)
+		if err != nil {
+			t.Fatalf("verify absence of %s failed, error is %s", key, err)
+		}
+		if provedValue != nil {
+			t.Fatalf("%s should be absent, got %s", key, provedValue)
+		}
+	}
+}
+
+func TestVerifyProofTampered(t *testing.T) {
+	trie, _, close := newTempTrie()
+	defer close()
+
+	trie.SetValue([]byte("tesabcd"), []byte("value1"))
+	trie.SetValue([]byte("tesabce"), bytes.Repeat([]byte("value2"), 10))
+	rootHash := *trie.Hash()
+
+	proof, _ := trie.Prove([]byte("tesabce"))
+
+	tampered := make([][]byte, len(proof))
+	copy(tampered, proof)
+	tampered[len(tampered)-1] = bytes.Repeat([]byte("value3"), 10)
+	if _, err := VerifyProof(rootHash, []byte("tesabce"), tampered); err != ErrInvalidProof {
+		t.Fatalf("tampered value should be rejected, error is %v", err)
+	}
+
+	if _, err := VerifyProof(rootHash, []byte("tesabcd"), proof); err != ErrInvalidProof {
+		t.Fatalf("proof of another key should be rejected, error is %v", err)
+	}
+
+	if _, err := VerifyProof(rootHash, []byte("tesabce"), proof[1:]); err != ErrInvalidProof {
+		t.Fatalf("incomplete proof should be rejected, error is %v", err)
+	}
+}
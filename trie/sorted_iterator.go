@@ -0,0 +1,81 @@
+package trie
+
+import (
+	"bytes"
+)
+
+// SortedIterator walks the leaves with the prefix in key order and only returns the keys greater than after,
+// the subtrees whose keys are all before after are skipped without being walked.
+type SortedIterator struct {
+	prefix []byte
+	after  []byte
+	trie   *Trie
+
+	stack []middleKeyAndNode
+}
+
+func NewSortedIterator(trie *Trie, prefix []byte, after []byte) *SortedIterator {
+	iterator := &SortedIterator{
+		trie:   trie,
+		prefix: prefix,
+		after:  after,
+	}
+	iterator.push([]byte{}, trie.Root)
+	return iterator
+}
+
+func (iterator *SortedIterator) Next() (key, value []byte, ok bool) {
+	for len(iterator.stack) > 0 {
+		item := iterator.stack[len(iterator.stack)-1]
+		iterator.stack = iterator.stack[:len(iterator.stack)-1]
+
+		node := item.middleNode
+		switch node.NodeType() {
+		case TRIE_FULL_NODE:
+			// push in reverse order, so the smallest key is popped first
+			sorted := newSortedChildren(node.children)
+			for i := len(sorted) - 1; i >= 0; i-- {
+				iterator.push(joinKey(item.key, []byte{sorted[i].Key}), sorted[i].Value)
+			}
+			// the value of the full node itself is the shortest key
+			iterator.push(item.key, node.child)
+		case TRIE_SHORT_NODE:
+			iterator.push(joinKey(item.key, node.key), node.child)
+		default:
+			return item.key, iterator.trie.LeafNodeValue(node), true
+		}
+	}
+	return nil, nil, false
+}
+
+func (iterator *SortedIterator) push(key []byte, node *TrieNode) {
+	if node == nil {
+		return
+	}
+	middle := node.NodeType() == TRIE_FULL_NODE || node.NodeType() == TRIE_SHORT_NODE
+	if !iterator.match(key, middle) {
+		return
+	}
+	iterator.stack = append(iterator.stack, middleKeyAndNode{key: key, middleNode: node})
+}
+
+// match reports whether the keys under key may be returned, a middle node matches if some of its keys may
+func (iterator *SortedIterator) match(key []byte, middle bool) bool {
+	if middle {
+		if !bytes.HasPrefix(key, iterator.prefix) && !bytes.HasPrefix(iterator.prefix, key) {
+			return false
+		}
+		// every key under a middle node which is before after and not a prefix of it is before after too
+		return iterator.after == nil || bytes.Compare(key, iterator.after) >= 0 || bytes.HasPrefix(iterator.after, key)
+	}
+	if !bytes.HasPrefix(key, iterator.prefix) {
+		return false
+	}
+	return iterator.after == nil || bytes.Compare(key, iterator.after) > 0
+}
+
+func joinKey(prefix []byte, key []byte) []byte {
+	newKey := make([]byte, len(prefix), len(prefix)+len(key))
+	copy(newKey, prefix)
+	return append(newKey, key...)
+}
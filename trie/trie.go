@@ -383,6 +383,10 @@ func (trie *Trie) NewIterator(prefix []byte) *Iterator {
 	return NewIterator(trie, prefix)
 }
 
+func (trie *Trie) NewSortedIterator(prefix []byte, after []byte) *SortedIterator {
+	return NewSortedIterator(trie, prefix, after)
+}
+
 func (trie *Trie) getLeafNode(node *TrieNode, key []byte) *TrieNode {
 	if node == nil {
 		return nil
@@ -0,0 +1,75 @@
+package trie
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/crypto"
+)
+
+// VerifyTrie checks that every node of the trie of rootHash is in the db and hashes to the key it is saved with,
+// and that the referenced values of the hash nodes are complete. It's used to check the imported tries.
+func VerifyTrie(db *leveldb.DB, rootHash *types.Hash) error {
+	trie := &Trie{db: db}
+
+	visited := make(map[types.Hash]struct{})
+	pending := []types.Hash{*rootHash}
+	for len(pending) > 0 {
+		hash := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if _, ok := visited[hash]; ok {
+			continue
+		}
+		visited[hash] = struct{}{}
+
+		node := trie.getNodeFromDb(&hash)
+		if node == nil {
+			return errors.New(fmt.Sprintf("trie node %s is missing", hash))
+		}
+		if *node.Hash() != hash {
+			return errors.New(fmt.Sprintf("trie node %s is broken", hash))
+		}
+
+		switch node.NodeType() {
+		case TRIE_FULL_NODE:
+			for _, child := range node.children {
+				pending = append(pending, *child.Hash())
+			}
+			if node.child != nil {
+				pending = append(pending, *node.child.Hash())
+			}
+		case TRIE_SHORT_NODE:
+			pending = append(pending, *node.child.Hash())
+		case TRIE_HASH_NODE:
+			value, err := trie.getRefValue(node.value)
+			if err != nil {
+				return errors.New(fmt.Sprintf("referenced value of trie node %s is missing", hash))
+			}
+			if !bytes.Equal(crypto.Hash256(value), node.value) {
+				return errors.New(fmt.Sprintf("referenced value of trie node %s is broken", hash))
+			}
+		}
+	}
+	return nil
+}
+
+// SaveNodes saves the serialized nodes and the referenced values in the batch, the key of a node is the hash of it.
+func SaveNodes(batch *leveldb.Batch, nodes [][]byte, refValues [][]byte) error {
+	for _, buf := range nodes {
+		node := &TrieNode{}
+		if err := node.DbDeserialize(buf); err != nil {
+			return err
+		}
+		dbKey, _ := database.EncodeKey(database.DBKP_TRIE_NODE, node.Hash().Bytes())
+		batch.Put(dbKey, buf)
+	}
+	for _, value := range refValues {
+		dbKey, _ := database.EncodeKey(database.DBKP_TRIE_REF_VALUE, crypto.Hash256(value))
+		batch.Put(dbKey, value)
+	}
+	return nil
+}
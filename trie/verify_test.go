@@ -0,0 +1,58 @@
+package trie
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+)
+
+func TestVerifyTrie(t *testing.T) {
+	trie, db, close := newTempTrie()
+	defer close()
+
+	trie.SetValue([]byte("tesab"), []byte("short value"))
+	trie.SetValue([]byte("tesabcd"), bytes.Repeat([]byte("long value"), 10))
+	trie.SetValue([]byte("abcdefgh"), []byte("other branch"))
+	rootHash := *trie.Hash()
+
+	// export the nodes and save them to another db
+	var nodes, refValues [][]byte
+	ni := trie.NewNodeIterator()
+	for ni.Next(nil) {
+		node := ni.Node()
+		buf, err := node.DbSerialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes = append(nodes, buf)
+		if node.NodeType() == TRIE_HASH_NODE {
+			refValues = append(refValues, trie.LeafNodeValue(node))
+		}
+	}
+	batch := new(leveldb.Batch)
+	if err := SaveNodes(batch, nodes, refValues); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Write(batch, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := VerifyTrie(db, &rootHash); err != nil {
+		t.Fatal(err)
+	}
+	if value := NewTrie(db, &rootHash, nil).GetValue([]byte("abcdefgh")); string(value) != "other branch" {
+		t.Fatalf("unexpected value %s", value)
+	}
+
+	// a missing referenced value
+	for _, value := range refValues {
+		key, _ := database.EncodeKey(database.DBKP_TRIE_REF_VALUE, types.DataHash(value).Bytes())
+		db.Delete(key, nil)
+	}
+	if err := VerifyTrie(db, &rootHash); err == nil {
+		t.Fatal("expected the missing referenced value to be detected")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/pkg/errors"
 	"github.com/vitelabs/go-vite/common"
+	"github.com/vitelabs/go-vite/compress"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
 	"github.com/vitelabs/go-vite/p2p"
@@ -11,6 +12,7 @@ import (
 	"io"
 	"math/rand"
 	net2 "net"
+	"os"
 	"sort"
 	"strconv"
 	"sync"
@@ -128,7 +130,19 @@ func (s *fileServer) handleConn(conn net2.Conn) {
 			// send files
 			var n int64
 			for _, filename := range req.Names {
-				n, err = io.Copy(conn, s.chain.Compressor().FileReader(filename))
+				var reader io.ReadCloser
+				if req.Compressed {
+					reader = s.chain.Compressor().FileReader(filename)
+				} else {
+					reader = s.chain.Compressor().DecompressedFileReader(filename)
+				}
+				if reader == nil {
+					s.log.Error(fmt.Sprintf("open file<%s> for %s failed", filename, conn.RemoteAddr()))
+					return
+				}
+
+				n, err = io.Copy(conn, reader)
+				reader.Close()
 
 				if err != nil {
 					s.log.Error(fmt.Sprintf("send file<%s> to %s error: %v", filename, conn.RemoteAddr(), err))
@@ -145,12 +159,11 @@ func (s *fileServer) handleConn(conn net2.Conn) {
 
 type conn struct {
 	net2.Conn
-	file   *ledger.CompressedFileMeta
-	height uint64 // block has got
-	peer   Peer
-	idle   bool
-	idleT  time.Time
-	done   bool // file request done
+	file  *ledger.CompressedFileMeta
+	peer  Peer
+	idle  bool
+	idleT time.Time
+	done  bool // file request done
 }
 
 type filesEvent struct {
@@ -158,10 +171,17 @@ type filesEvent struct {
 	sender Peer
 }
 
+// mirrorEvent is the result of a file which no peer has and is downloaded from the mirrors
+type mirrorEvent struct {
+	file *ledger.CompressedFileMeta
+	done bool
+}
+
 type fileClient struct {
-	idleChan  chan *conn
-	delChan   chan *conn
-	filesChan chan *filesEvent
+	idleChan   chan *conn
+	delChan    chan *conn
+	filesChan  chan *filesEvent
+	mirrorChan chan *mirrorEvent
 
 	finishCallbacks []func(end uint64)
 
@@ -171,6 +191,10 @@ type fileClient struct {
 
 	dialer *net2.Dialer
 
+	mirror *LedgerMirror // the files are downloaded from the mirrors first if it's not nil
+	// count of the files downloading from the mirrors, it's only accessed in loop
+	mirroring int
+
 	pool cPool
 
 	running int32
@@ -186,16 +210,18 @@ type cPool interface {
 	start()
 }
 
-func newFileClient(chain Chain, pool cPool, rec blockReceiver) *fileClient {
+func newFileClient(chain Chain, pool cPool, rec blockReceiver, mirror *LedgerMirror) *fileClient {
 	return &fileClient{
-		idleChan:  make(chan *conn, 1),
-		delChan:   make(chan *conn, 1),
-		filesChan: make(chan *filesEvent, 10),
-		chain:     chain,
-		log:       log15.New("module", "net/fileClient"),
-		dialer:    &net2.Dialer{Timeout: 3 * time.Second},
-		pool:      pool,
-		rec:       rec,
+		idleChan:   make(chan *conn, 1),
+		delChan:    make(chan *conn, 1),
+		filesChan:  make(chan *filesEvent, 10),
+		mirrorChan: make(chan *mirrorEvent, 1),
+		chain:      chain,
+		log:        log15.New("module", "net/fileClient"),
+		dialer:     &net2.Dialer{Timeout: 3 * time.Second},
+		pool:       pool,
+		rec:        rec,
+		mirror:     mirror,
 	}
 }
 
@@ -311,7 +337,16 @@ func (fc *fileClient) requestFile(conns map[string]*conn, record map[string]*fil
 			}
 		}
 
-		// no peers
+		// no peers, try the mirrors before the blocks are requested one by one
+		if fc.mirror != nil {
+			r.state = reqPending
+			fc.mirroring++
+			common.Go(func() {
+				fc.execMirror(file)
+			})
+			return
+		}
+
 		r.state = reqDone
 		fc.pool.add(file.StartHeight, file.EndHeight)
 		return
@@ -451,10 +486,19 @@ loop:
 
 			fc.requestFile(conns, record, pFiles, file)
 
+		case e := <-fc.mirrorChan: // a file from the mirrors done
+			fc.mirroring--
+			if r, ok := record[e.file.Filename]; ok {
+				r.state = reqDone
+				if !e.done {
+					fc.pool.add(e.file.StartHeight, e.file.EndHeight)
+				}
+			}
+
 		case <-jobTicker:
 			if file := fc.nextFile(fileList, record); file == nil {
 				// some files are downloading
-				done := true
+				done := fc.mirroring == 0
 				for _, c := range conns {
 					if !c.idle {
 						done = false
@@ -509,8 +553,19 @@ func (fc *fileClient) exec(ctx *conn) {
 
 	ctx.idle = false
 
+	if fc.mirror != nil {
+		if err := fc.receiveMirrorFile(ctx.file); err == nil {
+			ctx.done = true
+			fc.idle(ctx)
+			return
+		} else {
+			fc.log.Warn(fmt.Sprintf("receive file %s from mirrors error: %v, request it from %s", ctx.file.Filename, err, ctx.RemoteAddr()))
+		}
+	}
+
 	getFiles := &message.GetFiles{
-		Names: []string{ctx.file.Filename},
+		Names:      []string{ctx.file.Filename},
+		Compressed: true,
 	}
 
 	msg, err := p2p.PackMsg(CmdSet, p2p.Cmd(GetFilesCode), 0, getFiles)
@@ -541,7 +596,46 @@ func (fc *fileClient) exec(ctx *conn) {
 
 var errFlieClientStopped = errors.New("fileClient stopped")
 
+func (fc *fileClient) execMirror(file *ledger.CompressedFileMeta) {
+	err := fc.receiveMirrorFile(file)
+	if err != nil {
+		fc.log.Warn(fmt.Sprintf("receive file %s from mirrors error: %v, request the blocks from peers", file.Filename, err))
+	}
+
+	select {
+	case <-fc.term:
+	case fc.mirrorChan <- &mirrorEvent{file, err == nil}:
+	}
+}
+
+// receiveMirrorFile downloads the file from the mirrors and removes it after the blocks are received
+func (fc *fileClient) receiveMirrorFile(file *ledger.CompressedFileMeta) error {
+	filename, err := fc.mirror.Download(file)
+	if err != nil {
+		return err
+	}
+
+	reader, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	err = fc.receiveBlocks(file, reader, "mirrors")
+	reader.Close()
+	if err != nil {
+		return err
+	}
+
+	if err := fc.mirror.Remove(file); err != nil {
+		fc.log.Warn(fmt.Sprintf("remove file %s of mirrors error: %v", file.Filename, err))
+	}
+	return nil
+}
+
 func (fc *fileClient) receiveFile(ctx *conn) error {
+	return fc.receiveBlocks(ctx.file, ctx, ctx.RemoteAddr().String())
+}
+
+func (fc *fileClient) receiveBlocks(file *ledger.CompressedFileMeta, reader io.Reader, source string) error {
 	select {
 	case <-fc.term:
 		return errFlieClientStopped
@@ -549,9 +643,14 @@ func (fc *fileClient) receiveFile(ctx *conn) error {
 		// total blocks: snapshotblocks & accountblocks
 		var sCount, aCount uint64
 
-		file := ctx.file
+		codec, err := compress.GetCodec(file.Codec)
+		if err != nil {
+			return err
+		}
+		decoder := codec.NewReader(reader)
+		defer decoder.Close()
 
-		fc.chain.Compressor().BlockParser(ctx, file.BlockNumbers, func(block ledger.Block, err error) {
+		fc.chain.Compressor().BlockParser(decoder, file.BlockNumbers, func(block ledger.Block, err error) {
 			if err != nil {
 				return
 			}
@@ -565,7 +664,6 @@ func (fc *fileClient) receiveFile(ctx *conn) error {
 
 				sCount++
 				fc.rec.receiveSnapshotBlock(block)
-				ctx.height = block.Height
 
 			case *ledger.AccountBlock:
 				block := block.(*ledger.AccountBlock)
@@ -583,7 +681,7 @@ func (fc *fileClient) receiveFile(ctx *conn) error {
 			return fmt.Errorf("incomplete file %s %d/%d", file.Filename, sCount, sTotal)
 		}
 
-		fc.log.Info(fmt.Sprintf("receive %d SnapshotBlocks %d AccountBlocks of file %s from %s", sCount, aCount, file.Filename, ctx.RemoteAddr()))
+		fc.log.Info(fmt.Sprintf("receive %d SnapshotBlocks %d AccountBlocks of file %s from %s", sCount, aCount, file.Filename, source))
 		return nil
 	}
 }
@@ -0,0 +1,178 @@
+package net
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/vitelabs/go-vite/common"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/log15"
+	net2 "net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	httpFilePrefix   = "/ledger_files/"
+	httpManifestName = "index"
+)
+
+// httpFileMeta is the item of the manifest served by httpFileServer
+type httpFileMeta struct {
+	StartHeight  uint64 `json:"startHeight"`
+	EndHeight    uint64 `json:"endHeight"`
+	Filename     string `json:"filename"`
+	FileSize     int64  `json:"fileSize"`
+	BlockNumbers uint64 `json:"blockNumbers"`
+	Checksum     string `json:"checksum,omitempty"`
+	Codec        string `json:"codec,omitempty"`
+}
+
+func newHttpFileMeta(file *ledger.CompressedFileMeta) *httpFileMeta {
+	meta := &httpFileMeta{
+		StartHeight:  file.StartHeight,
+		EndHeight:    file.EndHeight,
+		Filename:     file.Filename,
+		FileSize:     file.FileSize,
+		BlockNumbers: file.BlockNumbers,
+		Codec:        file.Codec,
+	}
+	if file.Checksum != nil {
+		meta.Checksum = file.Checksum.String()
+	}
+	return meta
+}
+
+func (meta *httpFileMeta) compressedFileMeta() (*ledger.CompressedFileMeta, error) {
+	file := &ledger.CompressedFileMeta{
+		StartHeight:  meta.StartHeight,
+		EndHeight:    meta.EndHeight,
+		Filename:     meta.Filename,
+		FileSize:     meta.FileSize,
+		BlockNumbers: meta.BlockNumbers,
+		Codec:        meta.Codec,
+	}
+	if meta.Checksum != "" {
+		checksum, err := types.HexToHash(meta.Checksum)
+		if err != nil {
+			return nil, err
+		}
+		file.Checksum = &checksum
+	}
+	return file, nil
+}
+
+// httpFileServer serves the index and the ledger files of the compressor over http read-only,
+// the files support range requests, so the downloads can be resumed.
+type httpFileServer struct {
+	addr  string
+	chain Chain
+	srv   *http.Server
+	log   log15.Logger
+}
+
+func newHttpFileServer(addr string, chain Chain) *httpFileServer {
+	return &httpFileServer{
+		addr:  addr,
+		chain: chain,
+		log:   log15.New("module", "net/httpFileServer"),
+	}
+}
+
+func (s *httpFileServer) start() error {
+	ln, err := net2.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	s.srv = &http.Server{
+		Handler:      s,
+		ReadTimeout:  fReadTimeout,
+		WriteTimeout: 10 * time.Minute,
+	}
+
+	common.Go(func() {
+		if err := s.srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			s.log.Error(fmt.Sprintf("serve ledger files on %s error: %v", s.addr, err))
+		}
+	})
+
+	s.log.Info(fmt.Sprintf("serve ledger files on http://%s%s", ln.Addr(), httpFilePrefix))
+	return nil
+}
+
+func (s *httpFileServer) stop() {
+	if s.srv != nil {
+		s.srv.Close()
+	}
+}
+
+func (s *httpFileServer) files() []*ledger.CompressedFileMeta {
+	indexer := s.chain.Compressor().Indexer()
+	return indexer.Get(1, indexer.LatestHeight())
+}
+
+func (s *httpFileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, httpFilePrefix) {
+		http.NotFound(w, r)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, httpFilePrefix)
+	files := s.files()
+
+	if name == httpManifestName {
+		manifest := make([]*httpFileMeta, 0, len(files))
+		for _, file := range files {
+			manifest = append(manifest, newHttpFileMeta(file))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(manifest); err != nil {
+			s.log.Error(fmt.Sprintf("send manifest to %s error: %v", r.RemoteAddr, err))
+		}
+		return
+	}
+
+	// only the indexed files can be served
+	var file *ledger.CompressedFileMeta
+	for _, item := range files {
+		if item.Filename == name {
+			file = item
+			break
+		}
+	}
+	if file == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	fd, err := os.Open(filepath.Join(s.chain.Compressor().Dir(), file.Filename))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer fd.Close()
+
+	fileInfo, err := fd.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if file.Checksum != nil {
+		w.Header().Set("ETag", `"`+file.Checksum.String()+`"`)
+		w.Header().Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(file.Checksum.Bytes()))
+	}
+
+	http.ServeContent(w, r, file.Filename, fileInfo.ModTime(), fd)
+}
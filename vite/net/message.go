@@ -295,8 +295,21 @@ func (s *getSubLedgerHandler) Handle(msg *p2p.Msg, sender Peer) (err error) {
 		return sender.Send(ExceptionCode, msg.Id, message.Missing)
 	}
 
+	// the compressed files are listed as the uncompressed ones for the old peers, they're decompressed by the file server
+	peerFiles := make([]*ledger.CompressedFileMeta, 0, len(files))
+	for _, file := range files {
+		if !sender.SupportCodec(file.Codec) {
+			oldFile := *file
+			oldFile.Codec = ""
+			oldFile.FileSize = 0
+			oldFile.Checksum = nil
+			file = &oldFile
+		}
+		peerFiles = append(peerFiles, file)
+	}
+
 	fileList := &message.FileList{
-		Files:  files,
+		Files:  peerFiles,
 		Chunks: chunks,
 		Nonce:  0,
 	}
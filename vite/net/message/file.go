@@ -92,6 +92,8 @@ func (f *FileList) Deserialize(buf []byte) error {
 type GetFiles struct {
 	Names []string
 	Nonce uint64
+	// the client decodes the files by their codecs, the files are decompressed for the old clients
+	Compressed bool
 }
 
 func (f *GetFiles) String() string {
@@ -111,6 +113,7 @@ func (f *GetFiles) Serialize() ([]byte, error) {
 	pb := new(vitepb.GetFiles)
 	pb.Nonce = f.Nonce
 	pb.Names = f.Names
+	pb.Compressed = f.Compressed
 	return proto.Marshal(pb)
 }
 
@@ -123,6 +126,7 @@ func (f *GetFiles) Deserialize(buf []byte) error {
 
 	f.Names = pb.Names
 	f.Nonce = pb.Nonce
+	f.Compressed = pb.Compressed
 
 	return nil
 }
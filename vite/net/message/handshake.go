@@ -11,6 +11,8 @@ type HandShake struct {
 	Port    uint16
 	Current types.Hash
 	Genesis types.Hash
+	// the codecs of the ledger files which are supported, the old peers only support the uncompressed files
+	Codecs []string
 }
 
 func (h *HandShake) Serialize() ([]byte, error) {
@@ -20,6 +22,7 @@ func (h *HandShake) Serialize() ([]byte, error) {
 	pb.Port = uint32(h.Port)
 	pb.Current = h.Current[:]
 	pb.Genesis = h.Genesis[:]
+	pb.Codecs = h.Codecs
 
 	return proto.Marshal(pb)
 }
@@ -36,6 +39,7 @@ func (h *HandShake) Deserialize(data []byte) error {
 	h.Port = uint16(pb.Port)
 	copy(h.Current[:], pb.Current)
 	copy(h.Genesis[:], pb.Genesis)
+	h.Codecs = pb.Codecs
 
 	return nil
 }
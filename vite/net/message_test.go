@@ -174,6 +174,10 @@ func (m *mock_Peer) FileAddress() *net2.TCPAddr {
 	panic("implement me")
 }
 
+func (m *mock_Peer) SupportCodec(codec string) bool {
+	panic("implement me")
+}
+
 func (m *mock_Peer) SetHead(head types.Hash, height uint64) {
 	panic("implement me")
 }
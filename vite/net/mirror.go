@@ -0,0 +1,290 @@
+package net
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/vitelabs/go-vite/compress"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/log15"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const partFileSuffix = ".part"
+
+const manifestRetryInterval = time.Minute
+
+// the manifest is fetched again after manifestTTL, the mirrors add new files as the ledger grows
+const manifestTTL = 10 * time.Minute
+
+var errNotInMirror = errors.New("file is not in the mirrors")
+
+// LedgerMirror downloads the ledger files from the http mirrors served by httpFileServer on demand, the files are
+// downloaded to dir and removed after the blocks are imported, the partial downloads are resumed by range requests.
+type LedgerMirror struct {
+	urls   []string
+	dir    string
+	client *http.Client
+
+	manifestLock   sync.Mutex
+	manifest       map[string]*ledger.CompressedFileMeta
+	manifestTime   time.Time
+	manifestFailed time.Time // don't fetch the manifest again and again when the mirrors are down
+
+	fileLocks sync.Map // filename -> *sync.Mutex
+	log       log15.Logger
+}
+
+func NewLedgerMirror(urls []string, dir string) *LedgerMirror {
+	mirrorUrls := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if url = strings.TrimSuffix(strings.TrimSpace(url), "/"); url != "" {
+			mirrorUrls = append(mirrorUrls, url)
+		}
+	}
+
+	return &LedgerMirror{
+		urls:   mirrorUrls,
+		dir:    dir,
+		client: &http.Client{Timeout: 10 * time.Minute},
+		log:    log15.New("module", "net/ledgerMirror"),
+	}
+}
+
+// Manifest returns the ledger files of the first mirror which is available, the result is cached for manifestTTL.
+func (m *LedgerMirror) Manifest() ([]*ledger.CompressedFileMeta, error) {
+	m.manifestLock.Lock()
+	defer m.manifestLock.Unlock()
+
+	if err := m.loadManifest(false); err != nil {
+		return nil, err
+	}
+
+	list := make(files, 0, len(m.manifest))
+	for _, file := range m.manifest {
+		list = append(list, file)
+	}
+	sort.Sort(list)
+	return list, nil
+}
+
+// loadManifest fetches the manifest if it's expired or refresh is true, the stale one is kept if the mirrors are down.
+// It must be called with manifestLock held.
+func (m *LedgerMirror) loadManifest(refresh bool) error {
+	if m.manifest != nil && !refresh && time.Since(m.manifestTime) < manifestTTL {
+		return nil
+	}
+	if time.Since(m.manifestFailed) < manifestRetryInterval {
+		if m.manifest != nil {
+			return nil
+		}
+		return errNotInMirror
+	}
+
+	var lastErr = errNotInMirror
+	for _, url := range m.urls {
+		files, err := m.fetchManifest(url)
+		if err != nil {
+			m.log.Warn(fmt.Sprintf("fetch manifest from %s error: %v", url, err))
+			lastErr = err
+			continue
+		}
+
+		m.manifest = make(map[string]*ledger.CompressedFileMeta, len(files))
+		for _, file := range files {
+			m.manifest[file.Filename] = file
+		}
+		m.manifestTime = time.Now()
+		return nil
+	}
+
+	m.manifestFailed = time.Now()
+	if m.manifest != nil {
+		return nil
+	}
+	return lastErr
+}
+
+// lookup returns the file in the manifest, the manifest is fetched again on a miss, at most once per manifestRetryInterval.
+func (m *LedgerMirror) lookup(file *ledger.CompressedFileMeta) (*ledger.CompressedFileMeta, error) {
+	m.manifestLock.Lock()
+	defer m.manifestLock.Unlock()
+
+	if err := m.loadManifest(false); err != nil {
+		return nil, err
+	}
+
+	mirrorFile, ok := m.manifest[file.Filename]
+	if !ok && time.Since(m.manifestTime) >= manifestRetryInterval {
+		if err := m.loadManifest(true); err != nil {
+			return nil, err
+		}
+		mirrorFile, ok = m.manifest[file.Filename]
+	}
+	if !ok || mirrorFile.StartHeight != file.StartHeight || mirrorFile.EndHeight != file.EndHeight {
+		return nil, errNotInMirror
+	}
+	return mirrorFile, nil
+}
+
+func (m *LedgerMirror) fetchManifest(url string) ([]*ledger.CompressedFileMeta, error) {
+	res, err := m.client.Get(url + httpFilePrefix + httpManifestName)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", res.Status)
+	}
+
+	var manifest []*httpFileMeta
+	if err := json.NewDecoder(res.Body).Decode(&manifest); err != nil {
+		return nil, err
+	}
+
+	files := make([]*ledger.CompressedFileMeta, 0, len(manifest))
+	for _, meta := range manifest {
+		file, err := meta.compressedFileMeta()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// Download returns the path of the downloaded file, the file is checked by the checksum of file
+// or the checksum in the manifest of the mirrors.
+func (m *LedgerMirror) Download(file *ledger.CompressedFileMeta) (string, error) {
+	mirrorFile, err := m.lookup(file)
+	if err != nil {
+		return "", err
+	}
+
+	expected := *mirrorFile
+	if file.Checksum != nil {
+		expected.Checksum = file.Checksum
+	}
+
+	lock, _ := m.fileLocks.LoadOrStore(file.Filename, new(sync.Mutex))
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	filename := filepath.Join(m.dir, file.Filename)
+	if _, err := os.Stat(filename); err == nil {
+		return filename, nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return "", err
+	}
+
+	var lastErr error
+	for _, url := range m.urls {
+		if lastErr = m.downloadFrom(url, &expected); lastErr == nil {
+			return filename, nil
+		}
+		m.log.Warn(fmt.Sprintf("download %s from %s error: %v", file.Filename, url, lastErr))
+	}
+	return "", lastErr
+}
+
+// Remove removes the downloaded file, it's called after the blocks of the file are imported.
+func (m *LedgerMirror) Remove(file *ledger.CompressedFileMeta) error {
+	lock, _ := m.fileLocks.LoadOrStore(file.Filename, new(sync.Mutex))
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	if err := os.Remove(filepath.Join(m.dir, file.Filename)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (m *LedgerMirror) downloadFrom(url string, file *ledger.CompressedFileMeta) error {
+	filename := filepath.Join(m.dir, file.Filename)
+	partFilename := filename + partFileSuffix
+
+	var offset int64
+	if fileInfo, err := os.Stat(partFilename); err == nil {
+		offset = fileInfo.Size()
+	}
+
+	if file.FileSize <= 0 || offset < file.FileSize {
+		req, err := http.NewRequest(http.MethodGet, url+httpFilePrefix+file.Filename, nil)
+		if err != nil {
+			return err
+		}
+		if offset > 0 {
+			req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+		}
+
+		res, err := m.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+
+		flag := os.O_CREATE | os.O_WRONLY
+		switch res.StatusCode {
+		case http.StatusPartialContent:
+			flag |= os.O_APPEND
+		case http.StatusOK:
+			// the mirror ignores the range, download from the beginning
+			flag |= os.O_TRUNC
+		case http.StatusRequestedRangeNotSatisfiable:
+			// the part file is broken
+			os.Remove(partFilename)
+			return fmt.Errorf("range %d- is not satisfiable", offset)
+		default:
+			return fmt.Errorf("unexpected status %s", res.Status)
+		}
+
+		partFile, err := os.OpenFile(partFilename, flag, 0600)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(partFile, res.Body)
+		partFile.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := checkDownloadedFile(partFilename, file); err != nil {
+		os.Remove(partFilename)
+		return err
+	}
+	return os.Rename(partFilename, filename)
+}
+
+func checkDownloadedFile(filename string, file *ledger.CompressedFileMeta) error {
+	fileInfo, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	if file.FileSize > 0 && fileInfo.Size() != file.FileSize {
+		return fmt.Errorf("size of %s is %d, expect %d", file.Filename, fileInfo.Size(), file.FileSize)
+	}
+
+	if file.Checksum == nil {
+		return nil
+	}
+	checksum, err := compress.FileChecksum(filename)
+	if err != nil {
+		return err
+	}
+	if *checksum != *file.Checksum {
+		return fmt.Errorf("checksum of %s is %s, expect %s", file.Filename, checksum, file.Checksum)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package net
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+func sha256Hash(data []byte) types.Hash {
+	sum := sha256.Sum256(data)
+	return types.Hash(sum)
+}
+
+func newTestMirrorServer(t *testing.T, content []byte, meta *httpFileMeta, ranges *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, httpFilePrefix)
+		if name == httpManifestName {
+			json.NewEncoder(w).Encode([]*httpFileMeta{meta})
+			return
+		}
+		if name != meta.Filename {
+			http.NotFound(w, r)
+			return
+		}
+
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, name, time.Now(), bytes.NewReader(content))
+	}))
+}
+
+func TestLedgerMirror_Download(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ledger_mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	checksum := sha256Hash(content)
+	file := &ledger.CompressedFileMeta{
+		StartHeight:  1,
+		EndHeight:    10,
+		Filename:     "subgraph_1_10",
+		FileSize:     int64(len(content)),
+		BlockNumbers: 12,
+		Checksum:     &checksum,
+	}
+
+	var ranges []string
+	server := newTestMirrorServer(t, content, newHttpFileMeta(file), &ranges)
+	defer server.Close()
+
+	// a partial download left by the former run
+	partFilename := filepath.Join(dir, file.Filename+partFileSuffix)
+	if err := ioutil.WriteFile(partFilename, content[:10], 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	mirror := NewLedgerMirror([]string{server.URL + "/"}, dir)
+	files, err := mirror.Manifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || *files[0].Checksum != checksum || files[0].FileSize != file.FileSize {
+		t.Fatalf("unexpected manifest %v", files)
+	}
+
+	filename, err := mirror.Download(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=10-" {
+		t.Fatalf("download should be resumed, ranges are %v", ranges)
+	}
+
+	downloaded, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(downloaded, content) {
+		t.Fatalf("unexpected content %s", downloaded)
+	}
+
+	// the files out of the manifest are not downloaded
+	if _, err := mirror.Download(&ledger.CompressedFileMeta{StartHeight: 11, EndHeight: 20, Filename: "subgraph_11_20"}); err != errNotInMirror {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestLedgerMirror_DownloadBadChecksum(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ledger_mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := []byte("0123456789")
+	checksum := sha256Hash([]byte("another content"))
+	file := &ledger.CompressedFileMeta{
+		StartHeight: 1,
+		EndHeight:   10,
+		Filename:    "subgraph_1_10",
+		FileSize:    int64(len(content)),
+		Checksum:    &checksum,
+	}
+
+	var ranges []string
+	server := newTestMirrorServer(t, content, newHttpFileMeta(file), &ranges)
+	defer server.Close()
+
+	mirror := NewLedgerMirror([]string{server.URL}, dir)
+	if _, err := mirror.Download(file); err == nil {
+		t.Fatal("file of bad checksum should not be downloaded")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, file.Filename)); !os.IsNotExist(err) {
+		t.Fatal("file of bad checksum should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, file.Filename+partFileSuffix)); !os.IsNotExist(err) {
+		t.Fatal("part file of bad checksum should be removed")
+	}
+}
+
+func TestLedgerMirror_RefetchManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ledger_mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := []byte("0123456789")
+	checksum := sha256Hash(content)
+	file1 := &ledger.CompressedFileMeta{StartHeight: 1, EndHeight: 10, Filename: "subgraph_1_10", FileSize: int64(len(content)), Checksum: &checksum}
+	file2 := &ledger.CompressedFileMeta{StartHeight: 11, EndHeight: 20, Filename: "subgraph_11_20", FileSize: int64(len(content)), Checksum: &checksum}
+
+	var lock sync.Mutex
+	manifest := []*httpFileMeta{newHttpFileMeta(file1)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		name := strings.TrimPrefix(r.URL.Path, httpFilePrefix)
+		if name == httpManifestName {
+			json.NewEncoder(w).Encode(manifest)
+			return
+		}
+		http.ServeContent(w, r, name, time.Now(), bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	mirror := NewLedgerMirror([]string{server.URL}, dir)
+	if files, err := mirror.Manifest(); err != nil || len(files) != 1 {
+		t.Fatalf("unexpected manifest %v, error %v", files, err)
+	}
+
+	// the mirror adds a new file
+	lock.Lock()
+	manifest = append(manifest, newHttpFileMeta(file2))
+	lock.Unlock()
+
+	// the manifest is just fetched, it's not fetched again on a miss
+	if _, err := mirror.Download(file2); err != errNotInMirror {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	mirror.manifestLock.Lock()
+	mirror.manifestTime = time.Now().Add(-manifestRetryInterval)
+	mirror.manifestLock.Unlock()
+
+	filename, err := mirror.Download(file2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the file is removed after it's imported
+	if err := mirror.Remove(file2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Fatal("downloaded file should be removed")
+	}
+}
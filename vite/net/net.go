@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/pkg/errors"
 	"github.com/vitelabs/go-vite/common"
+	"github.com/vitelabs/go-vite/compress"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
 	"github.com/vitelabs/go-vite/monitor"
@@ -24,6 +25,11 @@ type Config struct {
 	Chain    Chain
 	Verifier Verifier
 
+	// serve the ledger files over http if it's not empty
+	FileHttpAddr string
+	// download the ledger files from the http mirrors if it's not nil
+	Mirror *LedgerMirror
+
 	// for topo
 	Topology     []string
 	Topic        string
@@ -46,6 +52,7 @@ type net struct {
 	protocols []*p2p.Protocol // mount to p2p.Server
 	wg        sync.WaitGroup
 	fs        *fileServer
+	hfs       *httpFileServer
 	handlers  map[ViteCmd]MsgHandler
 	topo      *topo.Topology
 	query     *queryHandler // handle query message (eg. getAccountBlocks, getSnapshotblocks, getChunk, getSubLedger)
@@ -68,7 +75,7 @@ func New(cfg *Config) Net {
 	broadcaster := newBroadcaster(peers)
 	filter := newFilter()
 	receiver := newReceiver(cfg.Verifier, broadcaster, filter)
-	syncer := newSyncer(cfg.Chain, peers, g, receiver)
+	syncer := newSyncer(cfg.Chain, peers, g, receiver, cfg.Mirror)
 	fetcher := newFetcher(filter, peers, g)
 
 	syncer.feed.Sub(receiver.listen) // subscribe sync status
@@ -87,6 +94,10 @@ func New(cfg *Config) Net {
 		log:         netLog,
 	}
 
+	if cfg.FileHttpAddr != "" {
+		n.hfs = newHttpFileServer(cfg.FileHttpAddr, cfg.Chain)
+	}
+
 	n.addHandler(_statusHandler(statusHandler))
 	n.query = newQueryHandler(cfg.Chain)
 	n.addHandler(n.query)
@@ -133,6 +144,12 @@ func (n *net) Start(svr *p2p.Server) (err error) {
 		return
 	}
 
+	if n.hfs != nil {
+		if err = n.hfs.start(); err != nil {
+			return
+		}
+	}
+
 	if n.topo != nil {
 		if err = n.topo.Start(svr); err != nil {
 			return
@@ -163,6 +180,10 @@ func (n *net) Stop() {
 
 		n.fs.stop()
 
+		if n.hfs != nil {
+			n.hfs.stop()
+		}
+
 		if n.topo != nil {
 			n.topo.Stop()
 		}
@@ -186,6 +207,7 @@ func (n *net) handlePeer(p *peer) error {
 		Port:    n.Port,
 		Current: current.Hash,
 		Genesis: genesis.Hash,
+		Codecs:  compress.Codecs(),
 	})
 
 	if err != nil {
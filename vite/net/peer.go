@@ -6,6 +6,7 @@ import (
 	"github.com/seiflotfy/cuckoofilter"
 	"github.com/vitelabs/go-vite/common"
 	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/compress"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
 	"github.com/vitelabs/go-vite/p2p"
@@ -26,6 +27,7 @@ var errDiffGesis = errors.New("different genesis block")
 type Peer interface {
 	RemoteAddr() *net2.TCPAddr
 	FileAddress() *net2.TCPAddr
+	SupportCodec(codec string) bool
 	SetHead(head types.Hash, height uint64)
 	SeeBlock(hash types.Hash)
 	SendSnapshotBlocks(bs []*ledger.SnapshotBlock, msgId uint64) (err error)
@@ -47,6 +49,7 @@ type peer struct {
 	head        types.Hash // hash of the top snapshotblock in snapshotchain
 	height      uint64     // height of the snapshotchain
 	filePort    uint16     // fileServer port, for request file
+	codecs      []string   // codecs of the ledger files it supports
 	CmdSet      p2p.CmdSet // which cmdSet it belongs
 	KnownBlocks *cuckoofilter.CuckooFilter
 	log         log15.Logger
@@ -93,6 +96,20 @@ func (p *peer) FileAddress() *net2.TCPAddr {
 	}
 }
 
+// SupportCodec reports whether the peer is able to decode the ledger files of the codec,
+// the old peers which don't advertise the codecs only support the uncompressed files
+func (p *peer) SupportCodec(codec string) bool {
+	if codec == "" || codec == compress.CodecNone {
+		return true
+	}
+	for _, c := range p.codecs {
+		if c == codec {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *peer) Handshake(our *message.HandShake) error {
 	errch := make(chan error, 1)
 	common.Go(func() {
@@ -117,6 +134,7 @@ func (p *peer) Handshake(our *message.HandShake) error {
 	if p.filePort == 0 {
 		p.filePort = DefaultPort
 	}
+	p.codecs = their.Codecs
 
 	return nil
 }
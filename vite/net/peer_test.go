@@ -6,6 +6,9 @@ import (
 	"fmt"
 	mrand "math/rand"
 	"testing"
+
+	"github.com/vitelabs/go-vite/compress"
+	"github.com/vitelabs/go-vite/vite/net/message"
 )
 
 var peerMap = newPeerSet()
@@ -34,3 +37,24 @@ func TestPeerSet_SyncPeer(t *testing.T) {
 
 	fmt.Println("mid", peerMap.SyncPeer().Height())
 }
+
+func TestPeer_SupportCodec(t *testing.T) {
+	// an old peer doesn't advertise the codecs
+	p := mockPeer()
+	if !p.SupportCodec("") || !p.SupportCodec(compress.CodecNone) || p.SupportCodec(compress.CodecZstd) {
+		t.Fatal("the old peer only supports the uncompressed files")
+	}
+
+	buf, err := (&message.HandShake{Codecs: compress.Codecs()}).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	their := new(message.HandShake)
+	if err := their.Deserialize(buf); err != nil {
+		t.Fatal(err)
+	}
+	p.codecs = their.Codecs
+	if !p.SupportCodec(compress.CodecZstd) || !p.SupportCodec(compress.CodecSnappy) || p.SupportCodec("lz4") {
+		t.Fatalf("unexpected codecs %v", p.codecs)
+	}
+}
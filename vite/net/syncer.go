@@ -107,7 +107,7 @@ type syncer struct {
 	log        log15.Logger
 }
 
-func newSyncer(chain Chain, peers *peerSet, gid MsgIder, receiver Receiver) *syncer {
+func newSyncer(chain Chain, peers *peerSet, gid MsgIder, receiver Receiver, mirror *LedgerMirror) *syncer {
 	s := &syncer{
 		state:      SyncNotStart,
 		term:       make(chan struct{}),
@@ -124,7 +124,7 @@ func newSyncer(chain Chain, peers *peerSet, gid MsgIder, receiver Receiver) *syn
 	peers.Sub(s.pEvent)
 
 	pool := newChunkPool(peers, gid, s)
-	fc := newFileClient(chain, pool, s)
+	fc := newFileClient(chain, pool, s, mirror)
 	fc.subAllFileDownloaded(s.createChunkTasks)
 
 	s.pool = pool
@@ -15,10 +15,12 @@ import (
 	"github.com/vitelabs/go-vite/p2p"
 	"github.com/vitelabs/go-vite/pool"
 	"github.com/vitelabs/go-vite/producer"
+	"github.com/vitelabs/go-vite/signer"
 	"github.com/vitelabs/go-vite/verifier"
 	"github.com/vitelabs/go-vite/vite/net"
 	"github.com/vitelabs/go-vite/vm"
 	"github.com/vitelabs/go-vite/wallet"
+	"github.com/vitelabs/go-vite/watchdog"
 )
 
 var (
@@ -38,6 +40,9 @@ type Vite struct {
 	consensus        consensus.Consensus
 	onRoad           *onroad.Manager
 	p2p              *p2p.Server
+	mirror           *net.LedgerMirror
+	watchdog         *watchdog.Watchdog
+	term             chan struct{}
 }
 
 func New(cfg *config.Config, walletManager *wallet.Manager) (vite *Vite, err error) {
@@ -58,6 +63,7 @@ func New(cfg *config.Config, walletManager *wallet.Manager) (vite *Vite, err err
 
 	// net
 	netVerifier := verifier.NewNetVerifier(sbVerifier, aVerifier)
+	mirror := newLedgerMirror(cfg.LedgerMirrors, cfg.DataDir)
 	net := net.New(&net.Config{
 		Single:       cfg.Single,
 		Port:         uint16(cfg.FilePort),
@@ -67,6 +73,8 @@ func New(cfg *config.Config, walletManager *wallet.Manager) (vite *Vite, err err
 		Topic:        cfg.Topic,
 		Interval:     cfg.Interval,
 		TopoDisabled: cfg.TopoDisabled,
+		FileHttpAddr: cfg.FileHttpAddr,
+		Mirror:       mirror,
 	})
 
 	// vite
@@ -79,6 +87,14 @@ func New(cfg *config.Config, walletManager *wallet.Manager) (vite *Vite, err err
 		consensus:        cs,
 		snapshotVerifier: sbVerifier,
 		accountVerifier:  aVerifier,
+		mirror:           mirror,
+		term:             make(chan struct{}),
+	}
+
+	// signer, the keys stay in the wallet of the node unless a remote signer daemon is configured
+	var sn signer.Signer = signer.NewLocalSigner(walletManager)
+	if cfg.Producer.SignerEndpoint != "" {
+		sn = signer.NewRemoteSigner(cfg.Producer.SignerEndpoint)
 	}
 
 	// producer
@@ -89,10 +105,17 @@ func New(cfg *config.Config, walletManager *wallet.Manager) (vite *Vite, err err
 			log.Error(fmt.Sprintf("coinBase parse fail. %v", cfg.Producer.Coinbase), "err", err)
 			return nil, err
 		}
-		err = walletManager.MatchAddress(cfg.EntropyStorePath, *coinbase, index)
+		if cfg.Producer.SignerEndpoint == "" {
+			err = walletManager.MatchAddress(cfg.EntropyStorePath, *coinbase, index)
 
+			if err != nil {
+				log.Error(fmt.Sprintf("coinBase is not child of entropyStore, coinBase is : %v", cfg.Producer.Coinbase), "err", err)
+				return nil, err
+			}
+		}
+		protection, err := signer.NewProtection(cfg.ProducerProtectionFile())
 		if err != nil {
-			log.Error(fmt.Sprintf("coinBase is not child of entropyStore, coinBase is : %v", cfg.Producer.Coinbase), "err", err)
+			log.Error("load producer protection fail.", "err", err)
 			return nil, err
 		}
 		addressContext := &producer.AddressContext{
@@ -100,14 +123,44 @@ func New(cfg *config.Config, walletManager *wallet.Manager) (vite *Vite, err err
 			Address:   *coinbase,
 			Index:     index,
 		}
-		vite.producer = producer.NewProducer(chain, net, addressContext, cs, sbVerifier, walletManager, pl)
+		p := producer.NewProducer(chain, net, addressContext, cs, sbVerifier, sn, protection, pl)
+		if cfg.Producer.LeaseListen != "" {
+			// the nodes of a hot-standby pair share the protection record of the signer daemon,
+			// which keeps them from signing the same slot while they can not hear each other
+			if cfg.Producer.SignerEndpoint == "" {
+				return nil, errors.New("the lease requires a signer endpoint shared by the nodes")
+			}
+			if cfg.Producer.LeaseSecret == "" {
+				return nil, errors.New("the lease requires a secret shared by the nodes")
+			}
+			p.SetLease(cfg.Producer.LeaseListen, cfg.Producer.LeasePeers, cfg.Producer.LeaseSecret)
+		}
+		vite.producer = p
 	}
 
 	// onroad
 	or := onroad.NewManager(net, pl, vite.producer, walletManager)
+	or.SetSigner(sn)
 
 	// set onroad
 	vite.onRoad = or
+
+	// watchdog
+	if cfg.Watchdog != nil && cfg.Watchdog.Enabled {
+		var producers []types.Address
+		for _, p := range cfg.Watchdog.Producers {
+			addr, err := types.HexToAddress(p)
+			if err != nil {
+				log.Error(fmt.Sprintf("watchdog producer parse fail. %v", p), "err", err)
+				return nil, err
+			}
+			producers = append(producers, addr)
+		}
+		if len(producers) == 0 && vite.producer != nil {
+			producers = append(producers, vite.producer.GetCoinBase())
+		}
+		vite.watchdog = watchdog.New(cfg.Watchdog, producers, chain, cs, net)
+	}
 	return
 }
 
@@ -156,10 +209,22 @@ func (v *Vite) Start(p2p *p2p.Server) (err error) {
 			return err
 		}
 	}
+	if v.watchdog != nil {
+		v.watchdog.Start()
+	}
 	return nil
 }
 
 func (v *Vite) Stop() (err error) {
+	select {
+	case <-v.term:
+	default:
+		close(v.term)
+	}
+
+	if v.watchdog != nil {
+		v.watchdog.Stop()
+	}
 
 	v.net.Stop()
 	v.pool.Stop()
@@ -200,6 +265,10 @@ func (v *Vite) Consensus() consensus.Consensus {
 	return v.consensus
 }
 
+func (v *Vite) Watchdog() *watchdog.Watchdog {
+	return v.watchdog
+}
+
 func (v *Vite) OnRoad() *onroad.Manager {
 	return v.onRoad
 }
@@ -26,6 +26,7 @@ type Handshake struct {
 	Port                 uint32   `protobuf:"varint,3,opt,name=Port,proto3" json:"Port,omitempty"`
 	Current              []byte   `protobuf:"bytes,4,opt,name=Current,proto3" json:"Current,omitempty"`
 	Genesis              []byte   `protobuf:"bytes,5,opt,name=Genesis,proto3" json:"Genesis,omitempty"`
+	Codecs               []string `protobuf:"bytes,6,rep,name=Codecs,proto3" json:"Codecs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -91,6 +92,13 @@ func (m *Handshake) GetGenesis() []byte {
 	return nil
 }
 
+func (m *Handshake) GetCodecs() []string {
+	if m != nil {
+		return m.Codecs
+	}
+	return nil
+}
+
 type BlockID struct {
 	Hash                 []byte   `protobuf:"bytes,1,opt,name=Hash,proto3" json:"Hash,omitempty"`
 	Height               uint64   `protobuf:"varint,2,opt,name=Height,proto3" json:"Height,omitempty"`
@@ -144,6 +152,8 @@ type CompressedFileMeta struct {
 	Filename             string   `protobuf:"bytes,3,opt,name=Filename,proto3" json:"Filename,omitempty"`
 	FileSize             int64    `protobuf:"varint,4,opt,name=FileSize,proto3" json:"FileSize,omitempty"`
 	BlockNumbers         uint64   `protobuf:"varint,5,opt,name=BlockNumbers,proto3" json:"BlockNumbers,omitempty"`
+	Checksum             []byte   `protobuf:"bytes,6,opt,name=Checksum,proto3" json:"Checksum,omitempty"`
+	Codec                string   `protobuf:"bytes,7,opt,name=Codec,proto3" json:"Codec,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -209,6 +219,20 @@ func (m *CompressedFileMeta) GetBlockNumbers() uint64 {
 	return 0
 }
 
+func (m *CompressedFileMeta) GetChecksum() []byte {
+	if m != nil {
+		return m.Checksum
+	}
+	return nil
+}
+
+func (m *CompressedFileMeta) GetCodec() string {
+	if m != nil {
+		return m.Codec
+	}
+	return ""
+}
+
 type FileList struct {
 	Files                []*CompressedFileMeta `protobuf:"bytes,1,rep,name=Files,proto3" json:"Files,omitempty"`
 	Chunks               []uint64              `protobuf:"varint,2,rep,packed,name=Chunks,proto3" json:"Chunks,omitempty"`
@@ -267,6 +291,7 @@ func (m *FileList) GetNonce() uint64 {
 type GetFiles struct {
 	Names                []string `protobuf:"bytes,1,rep,name=Names,proto3" json:"Names,omitempty"`
 	Nonce                uint64   `protobuf:"varint,2,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
+	Compressed           bool     `protobuf:"varint,3,opt,name=Compressed,proto3" json:"Compressed,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -311,6 +336,13 @@ func (m *GetFiles) GetNonce() uint64 {
 	return 0
 }
 
+func (m *GetFiles) GetCompressed() bool {
+	if m != nil {
+		return m.Compressed
+	}
+	return false
+}
+
 type GetChunk struct {
 	Start                uint64   `protobuf:"varint,1,opt,name=Start,proto3" json:"Start,omitempty"`
 	End                  uint64   `protobuf:"varint,2,opt,name=End,proto3" json:"End,omitempty"`
@@ -626,40 +658,42 @@ func init() {
 func init() { proto.RegisterFile("vitepb/message.proto", fileDescriptor_2a6a8486deb9ab39) }
 
 var fileDescriptor_2a6a8486deb9ab39 = []byte{
-	// 548 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0x4d, 0x6f, 0xd3, 0x4c,
-	0x10, 0xc7, 0xe5, 0xd8, 0x79, 0x9b, 0xa4, 0xcf, 0x53, 0x56, 0x01, 0x59, 0x81, 0x83, 0x65, 0x2e,
-	0x39, 0x40, 0x8a, 0x82, 0xe0, 0x86, 0x50, 0x08, 0x4d, 0x82, 0x54, 0x2a, 0xb4, 0xfe, 0x00, 0x68,
-	0x1d, 0x8f, 0x62, 0x93, 0xda, 0x8e, 0x76, 0xd7, 0x20, 0x71, 0x43, 0xdc, 0xf8, 0x34, 0x7c, 0x44,
-	0xb4, 0x2f, 0x4e, 0xe2, 0x42, 0xa5, 0xde, 0xf6, 0x3f, 0x33, 0xff, 0x1d, 0xff, 0x76, 0x26, 0x81,
-	0xd1, 0xd7, 0x4c, 0xe2, 0x3e, 0xbe, 0xc8, 0x51, 0x08, 0xb6, 0xc5, 0xe9, 0x9e, 0x97, 0xb2, 0x24,
-	0x1d, 0x13, 0x1d, 0x8f, 0x6d, 0x96, 0x6d, 0x36, 0x65, 0x55, 0xc8, 0xcf, 0xf1, 0x4d, 0xb9, 0xd9,
-	0x99, 0x9a, 0xf1, 0x63, 0x9b, 0x13, 0x05, 0xdb, 0x8b, 0xb4, 0x6c, 0x24, 0xc3, 0x9f, 0x0e, 0xf4,
-	0xd7, 0xac, 0x48, 0x44, 0xca, 0x76, 0x48, 0x1e, 0x41, 0x67, 0x91, 0x27, 0x11, 0x4a, 0xdf, 0x09,
-	0x9c, 0x89, 0x47, 0xad, 0x52, 0xf1, 0x35, 0x66, 0xdb, 0x54, 0xfa, 0x2d, 0x13, 0x37, 0x8a, 0x10,
-	0xf0, 0x3e, 0x95, 0x5c, 0xfa, 0x6e, 0xe0, 0x4c, 0xce, 0xa8, 0x3e, 0x13, 0x1f, 0xba, 0x8b, 0x8a,
-	0x73, 0x2c, 0xa4, 0xef, 0x05, 0xce, 0x64, 0x48, 0x6b, 0xa9, 0x32, 0x2b, 0x2c, 0x50, 0x64, 0xc2,
-	0x6f, 0x9b, 0x8c, 0x95, 0xe1, 0x2b, 0xe8, 0xbe, 0x53, 0x1f, 0xf5, 0xe1, 0xbd, 0xba, 0x72, 0xcd,
-	0x44, 0xaa, 0x3f, 0x60, 0x48, 0xf5, 0xf9, 0xae, 0xf6, 0xe1, 0x6f, 0x07, 0xc8, 0xa2, 0xcc, 0xf7,
-	0x1c, 0x85, 0xc0, 0x64, 0x99, 0xdd, 0xe0, 0x47, 0x94, 0x8c, 0x04, 0x30, 0x88, 0x24, 0xe3, 0xd2,
-	0x7a, 0x0c, 0xca, 0x69, 0x88, 0x3c, 0x81, 0xfe, 0x65, 0x91, 0x34, 0xee, 0x3c, 0x06, 0xc8, 0x18,
-	0x7a, 0xea, 0xae, 0x82, 0xe5, 0xa8, 0xc9, 0xfa, 0xf4, 0xa0, 0xeb, 0x5c, 0x94, 0x7d, 0x47, 0x8d,
-	0xe7, 0xd2, 0x83, 0x26, 0x21, 0x0c, 0x35, 0xc5, 0x75, 0x95, 0xc7, 0xc8, 0x0d, 0xa4, 0x47, 0x1b,
-	0xb1, 0xf0, 0x8b, 0xf1, 0x5f, 0x65, 0x42, 0x92, 0x17, 0xd0, 0x56, 0x67, 0xe1, 0x3b, 0x81, 0x3b,
-	0x19, 0xcc, 0xc6, 0x53, 0x33, 0xa8, 0xe9, 0xdf, 0x48, 0xd4, 0x14, 0xea, 0xf9, 0xa4, 0x55, 0xb1,
-	0x13, 0x7e, 0x2b, 0x70, 0xf5, 0x7c, 0xb4, 0x22, 0x23, 0x68, 0x5f, 0x97, 0xc5, 0xc6, 0x7c, 0xae,
-	0x47, 0x8d, 0x08, 0x5f, 0x43, 0x6f, 0x85, 0xd2, 0x38, 0x55, 0x05, 0xcb, 0x6d, 0xaf, 0x3e, 0x35,
-	0xe2, 0xe8, 0x6b, 0x9d, 0xfa, 0x66, 0xda, 0xa7, 0xaf, 0x56, 0x15, 0xfa, 0xe1, 0xec, 0x2b, 0x1a,
-	0x41, 0xce, 0xc1, 0xbd, 0x2c, 0x12, 0xeb, 0x52, 0xc7, 0xf0, 0x97, 0x03, 0xfd, 0xa8, 0x8a, 0xaf,
-	0x30, 0xd9, 0x22, 0x27, 0x17, 0xd0, 0x8d, 0x34, 0x76, 0xcd, 0xf6, 0xb0, 0x66, 0x8b, 0xec, 0x12,
-	0xea, 0x2c, 0xad, 0xab, 0xc8, 0x14, 0xba, 0x73, 0x6b, 0x68, 0x69, 0xc3, 0xa8, 0x36, 0xcc, 0xcd,
-	0x46, 0xdb, 0x7a, 0x5b, 0xa4, 0x06, 0x38, 0x8f, 0xed, 0xbb, 0x5a, 0xe8, 0x63, 0x20, 0x4c, 0xe1,
-	0xc1, 0x0a, 0x65, 0xa3, 0x95, 0x20, 0x4f, 0xc1, 0x5b, 0xf2, 0x32, 0xd7, 0x20, 0x83, 0xd9, 0xff,
-	0xf5, 0xfd, 0x76, 0xef, 0xa8, 0x4e, 0x2a, 0xdc, 0x85, 0x6a, 0x57, 0x3f, 0x88, 0x16, 0x6a, 0x71,
-	0x97, 0x25, 0xff, 0xc6, 0x78, 0xa2, 0x7b, 0xf5, 0x68, 0x2d, 0xc3, 0xb7, 0xf0, 0xdf, 0xad, 0x36,
-	0xcf, 0xa1, 0x73, 0x1f, 0x72, 0x5b, 0x14, 0xfe, 0x70, 0xe0, 0x7c, 0x85, 0xf2, 0x94, 0x52, 0xa8,
-	0x7e, 0xf3, 0x24, 0x51, 0x2b, 0x60, 0x7f, 0x06, 0xb5, 0x3c, 0x40, 0xb4, 0xee, 0x05, 0xe1, 0xde,
-	0x01, 0xe1, 0x35, 0x21, 0xde, 0xc0, 0x59, 0xb3, 0xff, 0xb3, 0x5b, 0x0c, 0xff, 0x1e, 0x86, 0xad,
-	0x89, 0x3b, 0xfa, 0x9f, 0xe4, 0xe5, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x82, 0x97, 0xa9, 0x9d,
-	0xa2, 0x04, 0x00, 0x00,
+	// 584 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8d, 0x54, 0xcb, 0x8e, 0xd3, 0x30,
+	0x14, 0x55, 0x9b, 0xf4, 0x75, 0xdb, 0x81, 0xc1, 0x2a, 0x28, 0x0a, 0x08, 0x55, 0x61, 0xd3, 0x05,
+	0xd3, 0x41, 0x45, 0x2c, 0x11, 0x2a, 0x85, 0x4e, 0x91, 0x86, 0x11, 0x72, 0x24, 0xb6, 0x28, 0x0f,
+	0xab, 0x09, 0x9d, 0x24, 0x55, 0xec, 0x80, 0xc4, 0x8e, 0x2d, 0xbf, 0xc0, 0xdf, 0xf1, 0x25, 0xd8,
+	0xd7, 0x4e, 0xdb, 0x0c, 0x8c, 0x34, 0x3b, 0x9f, 0xfb, 0x3a, 0xf7, 0xdc, 0x6b, 0x1b, 0xc6, 0xdf,
+	0x52, 0xc1, 0x76, 0xe1, 0x79, 0xc6, 0x38, 0x0f, 0x36, 0x6c, 0xb6, 0x2b, 0x0b, 0x51, 0x90, 0xae,
+	0xb6, 0xba, 0xae, 0xf1, 0x06, 0x51, 0x54, 0x54, 0xb9, 0xf8, 0x12, 0x5e, 0x17, 0xd1, 0x56, 0xc7,
+	0xb8, 0x8f, 0x8d, 0x8f, 0xe7, 0xc1, 0x8e, 0x27, 0x45, 0xc3, 0xe9, 0xfd, 0x6e, 0xc1, 0x60, 0x1d,
+	0xe4, 0x31, 0x4f, 0x82, 0x2d, 0x23, 0x8f, 0xa0, 0xbb, 0xcc, 0x62, 0x9f, 0x09, 0xa7, 0x35, 0x69,
+	0x4d, 0x6d, 0x6a, 0x90, 0xb2, 0xaf, 0x59, 0xba, 0x49, 0x84, 0xd3, 0xd6, 0x76, 0x8d, 0x08, 0x01,
+	0xfb, 0x53, 0x51, 0x0a, 0xc7, 0x92, 0xd6, 0x13, 0x8a, 0x67, 0xe2, 0x40, 0x6f, 0x59, 0x95, 0x25,
+	0xcb, 0x85, 0x63, 0x4b, 0xf3, 0x88, 0xd6, 0x50, 0x79, 0x2e, 0x58, 0xce, 0x78, 0xca, 0x9d, 0x8e,
+	0xf6, 0x18, 0x88, 0xbc, 0x45, 0xcc, 0x22, 0xee, 0x74, 0x27, 0xd6, 0x74, 0x40, 0x0d, 0xf2, 0x5e,
+	0x41, 0xef, 0xad, 0x6a, 0xf6, 0xc3, 0x3b, 0x45, 0xb5, 0x0e, 0x78, 0x82, 0x8d, 0x8d, 0x28, 0x9e,
+	0x6f, 0x6b, 0xcb, 0xfb, 0xd3, 0x02, 0xb2, 0x2c, 0xb2, 0x5d, 0x29, 0x67, 0xc5, 0xe2, 0x55, 0x7a,
+	0xcd, 0x3e, 0x32, 0x11, 0x90, 0x09, 0x0c, 0x7d, 0x11, 0x94, 0xc2, 0xe4, 0x68, 0x89, 0xc7, 0x26,
+	0xf2, 0x04, 0x06, 0xef, 0xf3, 0xb8, 0x51, 0xf3, 0x60, 0x20, 0x2e, 0xf4, 0x55, 0xad, 0x3c, 0xc8,
+	0x18, 0x2a, 0x1e, 0xd0, 0x3d, 0xae, 0x7d, 0x7e, 0xfa, 0x83, 0xa1, 0x6c, 0x8b, 0xee, 0x31, 0xf1,
+	0x60, 0x84, 0x2a, 0xae, 0xaa, 0x2c, 0x64, 0xa5, 0x16, 0x6f, 0xd3, 0x86, 0x4d, 0xe5, 0x2f, 0x13,
+	0x16, 0x6d, 0x79, 0x95, 0xc9, 0x19, 0x28, 0x89, 0x7b, 0x4c, 0xc6, 0xd0, 0xc1, 0x79, 0x38, 0x3d,
+	0x24, 0xd5, 0xc0, 0xfb, 0xaa, 0x19, 0x2f, 0x53, 0x2e, 0xc8, 0x0b, 0xe8, 0xa8, 0x33, 0x97, 0x9a,
+	0xac, 0xe9, 0x70, 0xee, 0xce, 0xf4, 0xca, 0x67, 0xff, 0x0e, 0x81, 0xea, 0x40, 0x9c, 0x78, 0x52,
+	0xe5, 0x5b, 0x2e, 0x65, 0x5a, 0xb8, 0x69, 0x44, 0x8a, 0xeb, 0xaa, 0xc8, 0x23, 0x2d, 0xd0, 0xa6,
+	0x1a, 0x78, 0x9f, 0xa1, 0x7f, 0xc1, 0x84, 0xce, 0x54, 0x11, 0x52, 0xb1, 0xe6, 0x92, 0xdd, 0x20,
+	0x38, 0xe4, 0xb5, 0x8f, 0xf2, 0xc8, 0x53, 0x80, 0x43, 0x0b, 0x58, 0xb2, 0x4f, 0x8f, 0x2c, 0xde,
+	0x1c, 0xeb, 0x22, 0xb5, 0xaa, 0x80, 0xab, 0x30, 0x7b, 0xd1, 0x80, 0x9c, 0x82, 0x25, 0x17, 0x60,
+	0xaa, 0xaa, 0xa3, 0xf7, 0x4b, 0xde, 0x58, 0xbf, 0x0a, 0x2f, 0x59, 0xbc, 0x61, 0x25, 0x39, 0x87,
+	0x9e, 0x8f, 0x83, 0xac, 0xb5, 0x3f, 0xac, 0xb5, 0xfb, 0xe6, 0xba, 0xa3, 0x97, 0xd6, 0x51, 0x64,
+	0x06, 0xbd, 0x85, 0x49, 0x68, 0x63, 0xc2, 0xb8, 0x4e, 0x58, 0xe8, 0xb7, 0x63, 0xe2, 0x4d, 0x90,
+	0xba, 0x12, 0x8b, 0xd0, 0x6c, 0xca, 0x0c, 0xe5, 0x60, 0xf0, 0x12, 0x78, 0x20, 0x05, 0x34, 0xa8,
+	0x38, 0x79, 0x06, 0xf6, 0xaa, 0x2c, 0x32, 0x14, 0x32, 0x9c, 0xdf, 0xaf, 0xeb, 0x9b, 0x9b, 0x4c,
+	0xd1, 0xa9, 0x97, 0x2a, 0xe9, 0xea, 0x81, 0x21, 0x50, 0x4f, 0x64, 0x55, 0x94, 0xdf, 0x83, 0xb2,
+	0x9e, 0x56, 0x0d, 0xbd, 0x37, 0x70, 0xef, 0x06, 0xcd, 0x19, 0x74, 0xef, 0xa2, 0xdc, 0x04, 0x79,
+	0x3f, 0x5b, 0x70, 0x2a, 0x7b, 0x3d, 0x56, 0xc9, 0x15, 0xdf, 0x22, 0x8e, 0xd5, 0x36, 0xcc, 0xc3,
+	0xaa, 0xe1, 0x5e, 0x44, 0xfb, 0x4e, 0x22, 0xac, 0x5b, 0x44, 0xd8, 0x4d, 0x11, 0xaf, 0xe1, 0xa4,
+	0xc9, 0xff, 0xfc, 0x86, 0x86, 0xff, 0x2f, 0xc3, 0xc4, 0x84, 0x5d, 0xfc, 0xb3, 0x5e, 0xfe, 0x05,
+	0xc9, 0xf3, 0xde, 0x1f, 0x0c, 0x05, 0x00, 0x00,
 }
@@ -23,6 +23,8 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type Producer struct {
 	BrokerList           []string `protobuf:"bytes,1,rep,name=brokerList,proto3" json:"brokerList,omitempty"`
 	Topic                string   `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	SinkType             string   `protobuf:"bytes,3,opt,name=sinkType,proto3" json:"sinkType,omitempty"`
+	Target               string   `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -67,6 +69,20 @@ func (m *Producer) GetTopic() string {
 	return ""
 }
 
+func (m *Producer) GetSinkType() string {
+	if m != nil {
+		return m.SinkType
+	}
+	return ""
+}
+
+func (m *Producer) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Producer)(nil), "vitepb.Producer")
 }
@@ -74,12 +90,14 @@ func init() {
 func init() { proto.RegisterFile("vitepb/producer.proto", fileDescriptor_67ceeaed98d707ec) }
 
 var fileDescriptor_67ceeaed98d707ec = []byte{
-	// 104 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x2d, 0xcb, 0x2c, 0x49,
-	0x2d, 0x48, 0xd2, 0x2f, 0x28, 0xca, 0x4f, 0x29, 0x4d, 0x4e, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f,
-	0xc9, 0x17, 0x62, 0x83, 0x08, 0x2b, 0x39, 0x70, 0x71, 0x04, 0x40, 0x65, 0x84, 0xe4, 0xb8, 0xb8,
-	0x92, 0x8a, 0xf2, 0xb3, 0x53, 0x8b, 0x7c, 0x32, 0x8b, 0x4b, 0x24, 0x18, 0x15, 0x98, 0x35, 0x38,
-	0x83, 0x90, 0x44, 0x84, 0x44, 0xb8, 0x58, 0x4b, 0xf2, 0x0b, 0x32, 0x93, 0x25, 0x98, 0x14, 0x18,
-	0x35, 0x38, 0x83, 0x20, 0x9c, 0x24, 0x36, 0xb0, 0x81, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff,
-	0x4c, 0x8c, 0x3d, 0x70, 0x69, 0x00, 0x00, 0x00,
+	// 130 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0xe3, 0x12, 0x2d, 0xcb, 0x2c, 0x49,
+	0x2d, 0x48, 0xd2, 0x2f, 0x28, 0xca, 0x4f, 0x29, 0x4d, 0x4e, 0x2d, 0xd2, 0x03, 0x32, 0x4a, 0xf2,
+	0x85, 0xd8, 0x20, 0xc2, 0x4a, 0x25, 0x5c, 0x1c, 0x01, 0x50, 0x19, 0x21, 0x39, 0x2e, 0xae, 0xa4,
+	0xa2, 0xfc, 0xec, 0xd4, 0x22, 0x9f, 0xcc, 0xe2, 0x12, 0x09, 0x46, 0x05, 0x66, 0x0d, 0xce, 0x20,
+	0x24, 0x11, 0x21, 0x11, 0x2e, 0xd6, 0x92, 0xfc, 0x82, 0xcc, 0x64, 0x09, 0x26, 0x05, 0x46, 0xa0,
+	0x14, 0x84, 0x23, 0x24, 0xc5, 0xc5, 0x51, 0x9c, 0x99, 0x97, 0x1d, 0x52, 0x59, 0x90, 0x2a, 0xc1,
+	0x0c, 0x96, 0x80, 0xf3, 0x85, 0xc4, 0xb8, 0xd8, 0x4a, 0x12, 0x8b, 0xd2, 0x53, 0x4b, 0x24, 0x58,
+	0xc0, 0x32, 0x50, 0x5e, 0x12, 0x1b, 0xd8, 0x11, 0xc6, 0x00, 0x96, 0x59, 0x20, 0xdb, 0x9d, 0x00,
+	0x00, 0x00,
 }
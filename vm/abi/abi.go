@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"github.com/vitelabs/go-vite/common/types"
 	"io"
+	"reflect"
 )
 
 // The ABIContract holds information about a contract's context and available
@@ -71,6 +73,43 @@ func (abi ABIContract) PackVariable(name string, args ...interface{}) ([]byte, e
 	return arguments, nil
 }
 
+// PackEvent packs the given event name to the topics and data of a vm log.
+// The first topic is the event id, followed by the indexed arguments, which must be static types,
+// the data consists of the non-indexed arguments.
+func (abi ABIContract) PackEvent(name string, args ...interface{}) ([]types.Hash, []byte, error) {
+	event, exist := abi.Events[name]
+	if !exist {
+		return nil, nil, fmt.Errorf("event '%s' not found", name)
+	}
+	if len(args) != len(event.Inputs) {
+		return nil, nil, fmt.Errorf("argument count mismatch: %d for %d", len(args), len(event.Inputs))
+	}
+
+	topics := []types.Hash{event.Id()}
+	var nonIndexedArgs []interface{}
+	for i, input := range event.Inputs {
+		if !input.Indexed {
+			nonIndexedArgs = append(nonIndexedArgs, args[i])
+			continue
+		}
+		if input.Type.requiresLengthPrefix() || input.Type.T == ArrayTy {
+			return nil, nil, fmt.Errorf("abi: indexed argument '%s' of event '%s' is not static", input.Name, name)
+		}
+		packed, err := input.Type.pack(reflect.ValueOf(args[i]))
+		if err != nil {
+			return nil, nil, err
+		}
+		topic, _ := types.BytesToHash(packed)
+		topics = append(topics, topic)
+	}
+
+	data, err := event.Inputs.NonIndexed().Pack(nonIndexedArgs...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return topics, data, nil
+}
+
 // UnpackMethod output in v according to the abi specification
 func (abi ABIContract) UnpackMethod(v interface{}, name string, output []byte) (err error) {
 	if len(output) <= 4 {
@@ -103,6 +142,33 @@ func (abi ABIContract) UnpackVariable(v interface{}, name string, output []byte)
 	return fmt.Errorf("abi: could not locate named variable")
 }
 
+// UnpackOutputs decodes the return values of an off chain call to the method selected by the 4-byte id,
+// the outputs are read from the json abi since they are not kept in Method
+func UnpackOutputs(reader io.Reader, sigdata []byte, output []byte) ([]interface{}, error) {
+	if len(sigdata) < 4 {
+		return nil, fmt.Errorf("method id is not specified")
+	}
+	var fields []struct {
+		Type    string
+		Name    string
+		Inputs  []Argument
+		Outputs []Argument
+	}
+	if err := json.NewDecoder(reader).Decode(&fields); err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		if field.Type != "function" && field.Type != "" {
+			continue
+		}
+		method := Method{Name: field.Name, Inputs: field.Inputs}
+		if bytes.Equal(method.Id(), sigdata[:4]) {
+			return Arguments(field.Outputs).UnpackValues(output)
+		}
+	}
+	return nil, fmt.Errorf("no method with id: %#x", sigdata[:4])
+}
+
 // UnmarshalJSON implements json.Unmarshaler interface
 func (abi *ABIContract) UnmarshalJSON(data []byte) error {
 	var fields []struct {
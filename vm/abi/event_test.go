@@ -342,3 +342,29 @@ func TestEventIndexedWithArrayUnpack(t *testing.T) {
 	require.Equal(t, [2]uint8{0, 0}, rst.Value1)
 	require.Equal(t, stringOut, rst.Value2)
 }
+
+func TestEventPack(t *testing.T) {
+	abi, err := JSONToABIContract(strings.NewReader("[" + string(jsonEventTransfer) + "]"))
+	require.NoError(t, err)
+	from, _ := types.BytesToAddress(helper.HexToBytes("00ce0d46d924cc8437c806721496599fc3ffa268"))
+	to, _ := types.BytesToAddress(helper.HexToBytes("376c47978271565f56deb45495afa69e59c16ab2"))
+
+	topics, data, err := abi.PackEvent("Transfer", from, to, big.NewInt(1000000))
+	require.NoError(t, err)
+	require.Equal(t, []types.Hash{abi.Events["Transfer"].Id(), mustBytesToHash(helper.LeftPadBytes(from.Bytes(), 32)), mustBytesToHash(helper.LeftPadBytes(to.Bytes(), 32))}, topics)
+	require.Equal(t, eventTransferData1, hex.EncodeToString(data))
+
+	var ev struct{ Value *big.Int }
+	require.NoError(t, abi.UnpackEvent(&ev, "Transfer", data))
+	require.Equal(t, int64(1000000), ev.Value.Int64())
+
+	_, _, err = abi.PackEvent("Transfer", from, to)
+	require.Error(t, err)
+	_, _, err = abi.PackEvent("NotExist")
+	require.Error(t, err)
+}
+
+func mustBytesToHash(b []byte) types.Hash {
+	h, _ := types.BytesToHash(b)
+	return h
+}
@@ -0,0 +1,34 @@
+package abi
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/vitelabs/go-vite/common/helper"
+)
+
+const jsonOutputs = `[
+	{"type":"function","name":"balance","constant":true,"inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"send","inputs":[{"name":"amount","type":"uint256"}],"outputs":[]}
+]`
+
+func TestUnpackOutputs(t *testing.T) {
+	abiContract, err := JSONToABIContract(strings.NewReader(jsonOutputs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := abiContract.PackMethod("balance")
+	output := helper.LeftPadBytes(big.NewInt(100).Bytes(), helper.WordSize)
+	values, err := UnpackOutputs(strings.NewReader(jsonOutputs), data, output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 1 || values[0].(*big.Int).Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("unexpected outputs %v", values)
+	}
+
+	if _, err := UnpackOutputs(strings.NewReader(jsonOutputs), []byte{1, 2, 3, 4}, output); err == nil {
+		t.Fatal("expected an error for an unknown method")
+	}
+}
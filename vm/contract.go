@@ -59,5 +59,14 @@ func (c *contract) run(vm *VM) (ret []byte, err error) {
 		c.intPool = nil
 	}()
 
+	if vm.Tracer != nil {
+		vm.depth++
+		quota := c.quotaLeft
+		vm.Tracer.CaptureEnter(c.caller, c.address, c.codeAddr, c.sendBlock.Data, quota, vm.depth)
+		defer func() {
+			vm.Tracer.CaptureExit(ret, quota-c.quotaLeft, err, vm.depth)
+			vm.depth--
+		}()
+	}
 	return vm.i.Run(vm, c)
 }
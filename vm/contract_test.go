@@ -83,3 +83,22 @@ func TestRun(t *testing.T) {
 		}
 	}
 }
+
+func TestRunOffChain(t *testing.T) {
+	db := NewNoDatabase()
+	addr, _, _ := types.CreateAddress()
+	if _, err := NewVM().RunOffChain(db, addr, nil); err != util.ErrContractCodeNotExist {
+		t.Fatalf("run off chain without code, expected %v, got %v", util.ErrContractCodeNotExist, err)
+	}
+
+	// return the first word of calldata
+	db.codeMap[addr] = []byte{byte(PUSH1), 0, byte(CALLDATALOAD), byte(PUSH1), 0, byte(MSTORE), byte(PUSH1), 32, byte(PUSH1), 0, byte(RETURN)}
+	data := bytes.Repeat([]byte{7}, 32)
+	ret, err := NewVM().RunOffChain(db, addr, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ret, data) {
+		t.Fatalf("run off chain failed, expected %v, got %v", data, ret)
+	}
+}
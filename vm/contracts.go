@@ -6,6 +6,7 @@ import (
 	"github.com/vitelabs/go-vite/vm/contracts"
 	cabi "github.com/vitelabs/go-vite/vm/contracts/abi"
 	"github.com/vitelabs/go-vite/vm/util"
+	"github.com/vitelabs/go-vite/vm_context/vmctxt_interface"
 )
 
 type precompiledContract struct {
@@ -16,10 +17,11 @@ type precompiledContract struct {
 var simpleContracts = map[types.Address]*precompiledContract{
 	types.AddressRegister: {
 		map[string]contracts.PrecompiledContractMethod{
-			cabi.MethodNameRegister:       &contracts.MethodRegister{},
-			cabi.MethodNameCancelRegister: &contracts.MethodCancelRegister{},
-			// TODO not support reward this version cabi.MethodNameReward:             &contracts.MethodReward{},
-			cabi.MethodNameUpdateRegistration: &contracts.MethodUpdateRegistration{},
+			cabi.MethodNameRegister:            &contracts.MethodRegister{},
+			cabi.MethodNameCancelRegister:      &contracts.MethodCancelRegister{},
+			cabi.MethodNameReward:              &contracts.MethodReward{},
+			cabi.MethodNameWithdrawVoterReward: &contracts.MethodWithdrawVoterReward{},
+			cabi.MethodNameUpdateRegistration:  &contracts.MethodUpdateRegistration{},
 		},
 		cabi.ABIRegister,
 	},
@@ -32,8 +34,10 @@ var simpleContracts = map[types.Address]*precompiledContract{
 	},
 	types.AddressPledge: {
 		map[string]contracts.PrecompiledContractMethod{
-			cabi.MethodNamePledge:       &contracts.MethodPledge{},
-			cabi.MethodNameCancelPledge: &contracts.MethodCancelPledge{},
+			cabi.MethodNamePledge:            &contracts.MethodPledge{},
+			cabi.MethodNameCancelPledge:      &contracts.MethodCancelPledge{},
+			cabi.MethodNameAgentPledge:       &contracts.MethodAgentPledge{},
+			cabi.MethodNameCancelAgentPledge: &contracts.MethodCancelAgentPledge{},
 		},
 		cabi.ABIPledge,
 	},
@@ -48,19 +52,44 @@ var simpleContracts = map[types.Address]*precompiledContract{
 	},*/
 	types.AddressMintage: {
 		map[string]contracts.PrecompiledContractMethod{
-			cabi.MethodNameMintage:             &contracts.MethodMintage{},
-			cabi.MethodNameMintageCancelPledge: &contracts.MethodMintageCancelPledge{},
+			cabi.MethodNameMintage:              &contracts.MethodMintage{},
+			cabi.MethodNameMintageReIssuable:    &contracts.MethodMintageReIssuable{},
+			cabi.MethodNameMintageCancelPledge:  &contracts.MethodMintageCancelPledge{},
+			cabi.MethodNameMintageReIssue:       &contracts.MethodMintageReIssue{},
+			cabi.MethodNameMintageBurn:          &contracts.MethodMintageBurn{},
+			cabi.MethodNameMintageTransferOwner: &contracts.MethodMintageTransferOwner{},
 		},
 		cabi.ABIMintage,
 	},
+	types.AddressMultisig: {
+		map[string]contracts.PrecompiledContractMethod{
+			cabi.MethodNameCreateMultisig:  &contracts.MethodCreateMultisig{},
+			cabi.MethodNameMultisigDeposit: &contracts.MethodMultisigDeposit{},
+			cabi.MethodNameMultisigPropose: &contracts.MethodMultisigPropose{},
+			cabi.MethodNameMultisigApprove: &contracts.MethodMultisigApprove{},
+			cabi.MethodNameMultisigRevoke:  &contracts.MethodMultisigRevoke{},
+		},
+		cabi.ABIMultisig,
+	},
+	types.AddressVesting: {
+		map[string]contracts.PrecompiledContractMethod{
+			cabi.MethodNameVestingLock:  &contracts.MethodVestingLock{},
+			cabi.MethodNameVestingClaim: &contracts.MethodVestingClaim{},
+		},
+		cabi.ABIVesting,
+	},
 }
 
-func isPrecompiledContractAddress(addr types.Address) bool {
+// the contracts activated at a fork height are ordinary accounts before it
+func isPrecompiledContractAddress(db vmctxt_interface.VmDatabase, addr types.Address) bool {
 	_, ok := simpleContracts[addr]
-	return ok
+	return ok && contracts.IsContractActivated(db, addr)
 }
-func getPrecompiledContract(addr types.Address, methodSelector []byte) (contracts.PrecompiledContractMethod, bool, error) {
+func getPrecompiledContract(db vmctxt_interface.VmDatabase, addr types.Address, methodSelector []byte) (contracts.PrecompiledContractMethod, bool, error) {
 	p, ok := simpleContracts[addr]
+	if ok && !contracts.IsContractActivated(db, addr) {
+		return nil, false, nil
+	}
 	if ok {
 		if method, err := p.abi.MethodById(methodSelector); err == nil {
 			c, ok := p.m[method.Name]
@@ -19,6 +19,8 @@ var (
 		types.AddressPledge:         ABIPledge,
 		types.AddressConsensusGroup: ABIConsensusGroup,
 		types.AddressMintage:        ABIMintage,
+		types.AddressMultisig:       ABIMultisig,
+		types.AddressVesting:        ABIVesting,
 	}
 
 	errInvalidParam = errors.New("invalid param")
@@ -14,13 +14,29 @@ const (
 	jsonMintage = `
 	[
 		{"type":"function","name":"Mintage","inputs":[{"name":"tokenId","type":"tokenId"},{"name":"tokenName","type":"string"},{"name":"tokenSymbol","type":"string"},{"name":"totalSupply","type":"uint256"},{"name":"decimals","type":"uint8"}]},
+		{"type":"function","name":"MintageReIssuable","inputs":[{"name":"tokenId","type":"tokenId"},{"name":"tokenName","type":"string"},{"name":"tokenSymbol","type":"string"},{"name":"totalSupply","type":"uint256"},{"name":"decimals","type":"uint8"},{"name":"maxSupply","type":"uint256"}]},
 		{"type":"function","name":"CancelPledge","inputs":[{"name":"tokenId","type":"tokenId"}]},
-		{"type":"variable","name":"mintage","inputs":[{"name":"tokenName","type":"string"},{"name":"tokenSymbol","type":"string"},{"name":"totalSupply","type":"uint256"},{"name":"decimals","type":"uint8"},{"name":"owner","type":"address"},{"name":"pledgeAmount","type":"uint256"},{"name":"withdrawHeight","type":"uint64"}]}
+		{"type":"function","name":"ReIssue","inputs":[{"name":"tokenId","type":"tokenId"},{"name":"amount","type":"uint256"},{"name":"beneficial","type":"address"}]},
+		{"type":"function","name":"Burn","inputs":[]},
+		{"type":"function","name":"TransferOwner","inputs":[{"name":"tokenId","type":"tokenId"},{"name":"newOwner","type":"address"}]},
+		{"type":"variable","name":"mintage","inputs":[{"name":"tokenName","type":"string"},{"name":"tokenSymbol","type":"string"},{"name":"totalSupply","type":"uint256"},{"name":"decimals","type":"uint8"},{"name":"owner","type":"address"},{"name":"pledgeAmount","type":"uint256"},{"name":"withdrawHeight","type":"uint64"}]},
+		{"type":"variable","name":"reIssuable","inputs":[{"name":"maxSupply","type":"uint256"}]},
+		{"type":"event","name":"reIssue","inputs":[{"name":"tokenId","type":"tokenId","indexed":true},{"name":"amount","type":"uint256"},{"name":"beneficial","type":"address"}]},
+		{"type":"event","name":"burn","inputs":[{"name":"tokenId","type":"tokenId","indexed":true},{"name":"address","type":"address"},{"name":"amount","type":"uint256"}]},
+		{"type":"event","name":"transferOwner","inputs":[{"name":"tokenId","type":"tokenId","indexed":true},{"name":"owner","type":"address"}]}
 	]`
 
-	MethodNameMintage             = "Mintage"
-	MethodNameMintageCancelPledge = "CancelPledge"
-	VariableNameMintage           = "mintage"
+	MethodNameMintage              = "Mintage"
+	MethodNameMintageReIssuable    = "MintageReIssuable"
+	MethodNameMintageCancelPledge  = "CancelPledge"
+	MethodNameMintageReIssue       = "ReIssue"
+	MethodNameMintageBurn          = "Burn"
+	MethodNameMintageTransferOwner = "TransferOwner"
+	VariableNameMintage            = "mintage"
+	VariableNameMintageReIssuable  = "reIssuable"
+	EventNameMintageReIssue        = "reIssue"
+	EventNameMintageBurn           = "burn"
+	EventNameMintageTransferOwner  = "transferOwner"
 )
 
 var (
@@ -34,6 +50,37 @@ type ParamMintage struct {
 	TotalSupply *big.Int
 	Decimals    uint8
 }
+type ParamMintageReIssuable struct {
+	TokenId     types.TokenTypeId
+	TokenName   string
+	TokenSymbol string
+	TotalSupply *big.Int
+	Decimals    uint8
+	MaxSupply   *big.Int
+}
+
+func (p *ParamMintageReIssuable) ToParamMintage() ParamMintage {
+	return ParamMintage{
+		TokenId:     p.TokenId,
+		TokenName:   p.TokenName,
+		TokenSymbol: p.TokenSymbol,
+		TotalSupply: p.TotalSupply,
+		Decimals:    p.Decimals,
+	}
+}
+
+type ParamReIssue struct {
+	TokenId    types.TokenTypeId
+	Amount     *big.Int
+	Beneficial types.Address
+}
+type ParamTransferOwner struct {
+	TokenId  types.TokenTypeId
+	NewOwner types.Address
+}
+type VariableReIssuable struct {
+	MaxSupply *big.Int
+}
 
 func GetMintageKey(tokenId types.TokenTypeId) []byte {
 	return helper.LeftPadBytes(tokenId.Bytes(), types.HashSize)
@@ -42,6 +89,15 @@ func GetTokenIdFromMintageKey(key []byte) types.TokenTypeId {
 	tokenId, _ := types.BytesToTokenTypeId(key[types.HashSize-types.TokenTypeIdSize:])
 	return tokenId
 }
+func IsMintageKey(key []byte) bool {
+	return len(key) == types.HashSize
+}
+
+// the max supply of a reissuable token is stored apart from the token info,
+// so the token infos of fixed supply keep unchanged
+func GetReIssuableKey(tokenId types.TokenTypeId) []byte {
+	return tokenId.Bytes()
+}
 
 func NewTokenId(accountAddress types.Address, accountBlockHeight uint64, prevBlockHash types.Hash, snapshotHash types.Hash) types.TokenTypeId {
 	return types.CreateTokenTypeId(
@@ -56,6 +112,7 @@ func GetTokenById(db StorageDatabase, tokenId types.TokenTypeId) *types.TokenInf
 	if len(data) > 0 {
 		tokenInfo := new(types.TokenInfo)
 		ABIMintage.UnpackVariable(tokenInfo, VariableNameMintage, data)
+		fillReIssuable(db, tokenId, tokenInfo)
 		return tokenInfo
 	}
 	return nil
@@ -73,11 +130,25 @@ func GetTokenMap(db StorageDatabase) map[types.TokenTypeId]*types.TokenInfo {
 		if !ok {
 			break
 		}
+		if !IsMintageKey(key) {
+			continue
+		}
 		tokenId := GetTokenIdFromMintageKey(key)
 		tokenInfo := new(types.TokenInfo)
 		if err := ABIMintage.UnpackVariable(tokenInfo, VariableNameMintage, value); err == nil {
+			fillReIssuable(db, tokenId, tokenInfo)
 			tokenInfoMap[tokenId] = tokenInfo
 		}
 	}
 	return tokenInfoMap
 }
+
+func fillReIssuable(db StorageDatabase, tokenId types.TokenTypeId, tokenInfo *types.TokenInfo) {
+	reIssuable := new(VariableReIssuable)
+	if err := ABIMintage.UnpackVariable(reIssuable, VariableNameMintageReIssuable, db.GetStorageBySnapshotHash(&types.AddressMintage, GetReIssuableKey(tokenId), nil)); err == nil {
+		tokenInfo.IsReIssuable = true
+		tokenInfo.MaxSupply = reIssuable.MaxSupply
+	} else if tokenInfo.TotalSupply != nil {
+		tokenInfo.MaxSupply = new(big.Int).Set(tokenInfo.TotalSupply)
+	}
+}
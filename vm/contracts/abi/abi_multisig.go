@@ -0,0 +1,191 @@
+package abi
+
+import (
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/monitor"
+	"github.com/vitelabs/go-vite/vm/abi"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	jsonMultisig = `
+	[
+		{"type":"function","name":"CreateMultisig","inputs":[{"name":"owners","type":"address[]"},{"name":"threshold","type":"uint8"}]},
+		{"type":"function","name":"Deposit","inputs":[{"name":"walletId","type":"bytes32"}]},
+		{"type":"function","name":"Propose","inputs":[{"name":"walletId","type":"bytes32"},{"name":"to","type":"address"},{"name":"tokenId","type":"tokenId"},{"name":"amount","type":"uint256"}]},
+		{"type":"function","name":"Approve","inputs":[{"name":"walletId","type":"bytes32"},{"name":"proposalId","type":"bytes32"}]},
+		{"type":"function","name":"Revoke","inputs":[{"name":"walletId","type":"bytes32"},{"name":"proposalId","type":"bytes32"}]},
+		{"type":"variable","name":"multisigWallet","inputs":[{"name":"owners","type":"address[]"},{"name":"threshold","type":"uint8"}]},
+		{"type":"variable","name":"multisigBalance","inputs":[{"name":"amount","type":"uint256"}]},
+		{"type":"variable","name":"multisigProposal","inputs":[{"name":"to","type":"address"},{"name":"tokenId","type":"tokenId"},{"name":"amount","type":"uint256"},{"name":"approvers","type":"address[]"}]}
+	]`
+
+	MethodNameCreateMultisig     = "CreateMultisig"
+	MethodNameMultisigDeposit    = "Deposit"
+	MethodNameMultisigPropose    = "Propose"
+	MethodNameMultisigApprove    = "Approve"
+	MethodNameMultisigRevoke     = "Revoke"
+	VariableNameMultisigWallet   = "multisigWallet"
+	VariableNameMultisigBalance  = "multisigBalance"
+	VariableNameMultisigProposal = "multisigProposal"
+)
+
+var (
+	ABIMultisig, _ = abi.JSONToABIContract(strings.NewReader(jsonMultisig))
+)
+
+type ParamCreateMultisig struct {
+	Owners    []types.Address
+	Threshold uint8
+}
+type ParamMultisigPropose struct {
+	WalletId types.Hash
+	To       types.Address
+	TokenId  types.TokenTypeId
+	Amount   *big.Int
+}
+type ParamMultisigProposal struct {
+	WalletId   types.Hash
+	ProposalId types.Hash
+}
+
+type MultisigWallet struct {
+	Owners    []types.Address
+	Threshold uint8
+}
+
+func (w *MultisigWallet) IsOwner(addr types.Address) bool {
+	for _, owner := range w.Owners {
+		if owner == addr {
+			return true
+		}
+	}
+	return false
+}
+
+type VariableMultisigBalance struct {
+	Amount *big.Int
+}
+
+type MultisigProposal struct {
+	To         types.Address
+	TokenId    types.TokenTypeId
+	Amount     *big.Int
+	Approvers  []types.Address
+	ProposalId types.Hash
+}
+
+func (p *MultisigProposal) IsApprovedBy(addr types.Address) bool {
+	for _, approver := range p.Approvers {
+		if approver == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// the wallet id is the hash of the send block which creates the wallet,
+// the balances and the proposals of a wallet are stored with the wallet id as prefix
+func GetMultisigWalletKey(walletId types.Hash) []byte {
+	return walletId.Bytes()
+}
+func GetMultisigBalanceKey(walletId types.Hash, tokenId types.TokenTypeId) []byte {
+	return append(walletId.Bytes(), tokenId.Bytes()...)
+}
+func GetMultisigProposalKey(walletId types.Hash, proposalId types.Hash) []byte {
+	return append(walletId.Bytes(), proposalId.Bytes()...)
+}
+func IsMultisigBalanceKey(key []byte) bool {
+	return len(key) == types.HashSize+types.TokenTypeIdSize
+}
+func IsMultisigProposalKey(key []byte) bool {
+	return len(key) == 2*types.HashSize
+}
+func GetTokenIdFromMultisigBalanceKey(key []byte) types.TokenTypeId {
+	tokenId, _ := types.BytesToTokenTypeId(key[types.HashSize:])
+	return tokenId
+}
+func GetProposalIdFromMultisigProposalKey(key []byte) types.Hash {
+	proposalId, _ := types.BytesToHash(key[types.HashSize:])
+	return proposalId
+}
+
+func GetMultisigWallet(db StorageDatabase, walletId types.Hash) *MultisigWallet {
+	defer monitor.LogTime("vm", "GetMultisigWallet", time.Now())
+	data := db.GetStorageBySnapshotHash(&types.AddressMultisig, GetMultisigWalletKey(walletId), nil)
+	if len(data) > 0 {
+		wallet := new(MultisigWallet)
+		if err := ABIMultisig.UnpackVariable(wallet, VariableNameMultisigWallet, data); err == nil {
+			return wallet
+		}
+	}
+	return nil
+}
+
+func GetMultisigBalance(db StorageDatabase, walletId types.Hash, tokenId types.TokenTypeId) *big.Int {
+	balance := new(VariableMultisigBalance)
+	if err := ABIMultisig.UnpackVariable(balance, VariableNameMultisigBalance, db.GetStorageBySnapshotHash(&types.AddressMultisig, GetMultisigBalanceKey(walletId, tokenId), nil)); err == nil {
+		return balance.Amount
+	}
+	return big.NewInt(0)
+}
+
+func GetMultisigBalances(db StorageDatabase, walletId types.Hash) map[types.TokenTypeId]*big.Int {
+	defer monitor.LogTime("vm", "GetMultisigBalances", time.Now())
+	balances := make(map[types.TokenTypeId]*big.Int)
+	iterator := db.NewStorageIteratorBySnapshotHash(&types.AddressMultisig, walletId.Bytes(), nil)
+	if iterator == nil {
+		return balances
+	}
+	for {
+		key, value, ok := iterator.Next()
+		if !ok {
+			break
+		}
+		if IsMultisigBalanceKey(key) {
+			balance := new(VariableMultisigBalance)
+			if err := ABIMultisig.UnpackVariable(balance, VariableNameMultisigBalance, value); err == nil && balance.Amount.Sign() > 0 {
+				balances[GetTokenIdFromMultisigBalanceKey(key)] = balance.Amount
+			}
+		}
+	}
+	return balances
+}
+
+func GetMultisigProposal(db StorageDatabase, walletId types.Hash, proposalId types.Hash) *MultisigProposal {
+	data := db.GetStorageBySnapshotHash(&types.AddressMultisig, GetMultisigProposalKey(walletId, proposalId), nil)
+	if len(data) > 0 {
+		proposal := new(MultisigProposal)
+		if err := ABIMultisig.UnpackVariable(proposal, VariableNameMultisigProposal, data); err == nil {
+			proposal.ProposalId = proposalId
+			return proposal
+		}
+	}
+	return nil
+}
+
+// GetMultisigProposalList returns the pending proposals of the wallet, the executed proposals are removed
+func GetMultisigProposalList(db StorageDatabase, walletId types.Hash) []*MultisigProposal {
+	defer monitor.LogTime("vm", "GetMultisigProposalList", time.Now())
+	proposalList := make([]*MultisigProposal, 0)
+	iterator := db.NewStorageIteratorBySnapshotHash(&types.AddressMultisig, walletId.Bytes(), nil)
+	if iterator == nil {
+		return proposalList
+	}
+	for {
+		key, value, ok := iterator.Next()
+		if !ok {
+			break
+		}
+		if IsMultisigProposalKey(key) {
+			proposal := new(MultisigProposal)
+			if err := ABIMultisig.UnpackVariable(proposal, VariableNameMultisigProposal, value); err == nil {
+				proposal.ProposalId = GetProposalIdFromMultisigProposalKey(key)
+				proposalList = append(proposalList, proposal)
+			}
+		}
+	}
+	return proposalList
+}
@@ -12,14 +12,20 @@ const (
 	[
 		{"type":"function","name":"Pledge", "inputs":[{"name":"beneficial","type":"address"}]},
 		{"type":"function","name":"CancelPledge","inputs":[{"name":"beneficial","type":"address"},{"name":"amount","type":"uint256"}]},
+		{"type":"function","name":"AgentPledge","inputs":[{"name":"beneficial","type":"address"},{"name":"pledgeHeight","type":"uint64"}]},
+		{"type":"function","name":"CancelAgentPledge","inputs":[{"name":"beneficialList","type":"address[]"}]},
 		{"type":"variable","name":"pledgeInfo","inputs":[{"name":"amount","type":"uint256"},{"name":"withdrawHeight","type":"uint64"}]},
 		{"type":"variable","name":"pledgeBeneficial","inputs":[{"name":"amount","type":"uint256"}]}
 	]`
 
 	MethodNamePledge             = "Pledge"
 	MethodNameCancelPledge       = "CancelPledge"
+	MethodNameAgentPledge        = "AgentPledge"
+	MethodNameCancelAgentPledge  = "CancelAgentPledge"
 	VariableNamePledgeInfo       = "pledgeInfo"
 	VariableNamePledgeBeneficial = "pledgeBeneficial"
+
+	agentPledgeKeyPrefix byte = 1
 )
 
 var (
@@ -39,12 +45,32 @@ type PledgeInfo struct {
 	BeneficialAddr types.Address
 }
 
+// the pledge height of an agent pledge is counted in snapshot blocks from the receive,
+// the withdraw height is the expiry height since which the agent can cancel the pledge
+type ParamAgentPledge struct {
+	Beneficial   types.Address
+	PledgeHeight uint64
+}
+
 func GetPledgeBeneficialKey(beneficial types.Address) []byte {
 	return beneficial.Bytes()
 }
 func GetPledgeKey(addr types.Address, pledgeBeneficialKey []byte) []byte {
 	return append(addr.Bytes(), pledgeBeneficialKey...)
 }
+
+// the agent pledges are stored apart from the pledges of the agent itself, the info is saved as pledgeInfo
+func GetAgentPledgeKey(agent types.Address, beneficial types.Address) []byte {
+	return append(append([]byte{agentPledgeKeyPrefix}, agent.Bytes()...), beneficial.Bytes()...)
+}
+func IsAgentPledgeKey(key []byte) bool {
+	return len(key) == 2*types.AddressSize+1 && key[0] == agentPledgeKeyPrefix
+}
+func GetBeneficialFromAgentPledgeKey(key []byte) types.Address {
+	address, _ := types.BytesToAddress(key[1+types.AddressSize:])
+	return address
+}
+
 func IsPledgeKey(key []byte) bool {
 	return len(key) == 2*types.AddressSize
 }
@@ -85,3 +111,27 @@ func GetPledgeInfoList(db StorageDatabase, addr types.Address) ([]*PledgeInfo, *
 	}
 	return pledgeInfoList, pledgeAmount
 }
+
+func GetAgentPledgeInfoList(db StorageDatabase, agent types.Address) ([]*PledgeInfo, *big.Int) {
+	pledgeAmount := big.NewInt(0)
+	iterator := db.NewStorageIteratorBySnapshotHash(&types.AddressPledge, append([]byte{agentPledgeKeyPrefix}, agent.Bytes()...), nil)
+	pledgeInfoList := make([]*PledgeInfo, 0)
+	if iterator == nil {
+		return pledgeInfoList, pledgeAmount
+	}
+	for {
+		key, value, ok := iterator.Next()
+		if !ok {
+			break
+		}
+		if IsAgentPledgeKey(key) {
+			pledgeInfo := new(PledgeInfo)
+			if err := ABIPledge.UnpackVariable(pledgeInfo, VariableNamePledgeInfo, value); err == nil && pledgeInfo.Amount != nil && pledgeInfo.Amount.Sign() > 0 {
+				pledgeInfo.BeneficialAddr = GetBeneficialFromAgentPledgeKey(key)
+				pledgeInfoList = append(pledgeInfoList, pledgeInfo)
+				pledgeAmount.Add(pledgeAmount, pledgeInfo.Amount)
+			}
+		}
+	}
+	return pledgeInfoList, pledgeAmount
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/vitelabs/go-vite/monitor"
 	"github.com/vitelabs/go-vite/vm/abi"
 	"github.com/vitelabs/go-vite/vm_context/vmctxt_interface"
+	"math/big"
 	"strings"
 	"time"
 )
@@ -16,16 +17,20 @@ const (
 		{"type":"function","name":"UpdateRegistration", "inputs":[{"name":"gid","type":"gid"},{"Name":"name","type":"string"},{"name":"nodeAddr","type":"address"}]},
 		{"type":"function","name":"CancelRegister","inputs":[{"name":"gid","type":"gid"}, {"name":"name","type":"string"}]},
 		{"type":"function","name":"Reward","inputs":[{"name":"gid","type":"gid"},{"name":"name","type":"string"},{"name":"beneficialAddr","type":"address"}]},
+		{"type":"function","name":"WithdrawVoterReward","inputs":[{"name":"beneficialAddr","type":"address"}]},
 		{"type":"variable","name":"registration","inputs":[{"name":"name","type":"string"},{"name":"nodeAddr","type":"address"},{"name":"pledgeAddr","type":"address"},{"name":"amount","type":"uint256"},{"name":"withdrawHeight","type":"uint64"},{"name":"rewardIndex","type":"uint64"},{"name":"cancelHeight","type":"uint64"},{"name":"hisAddrList","type":"address[]"}]},
-		{"type":"variable","name":"hisName","inputs":[{"name":"name","type":"string"}]}
+		{"type":"variable","name":"hisName","inputs":[{"name":"name","type":"string"}]},
+		{"type":"variable","name":"voterReward","inputs":[{"name":"amount","type":"uint256"}]}
 	]`
 
-	MethodNameRegister           = "Register"
-	MethodNameCancelRegister     = "CancelRegister"
-	MethodNameReward             = "Reward"
-	MethodNameUpdateRegistration = "UpdateRegistration"
-	VariableNameRegistration     = "registration"
-	VariableNameHisName          = "hisName"
+	MethodNameRegister            = "Register"
+	MethodNameCancelRegister      = "CancelRegister"
+	MethodNameReward              = "Reward"
+	MethodNameUpdateRegistration  = "UpdateRegistration"
+	MethodNameWithdrawVoterReward = "WithdrawVoterReward"
+	VariableNameRegistration      = "registration"
+	VariableNameHisName           = "hisName"
+	VariableNameVoterReward       = "voterReward"
 )
 
 var (
@@ -46,6 +51,9 @@ type ParamReward struct {
 	Name           string
 	BeneficialAddr types.Address
 }
+type VariableVoterReward struct {
+	Amount *big.Int
+}
 
 func GetRegisterKey(name string, gid types.Gid) []byte {
 	return append(gid.Bytes(), types.DataHash([]byte(name)).Bytes()[types.GidSize:]...)
@@ -59,6 +67,11 @@ func IsRegisterKey(key []byte) bool {
 	return len(key) == types.HashSize
 }
 
+// the voter reward is the share of snapshot block reward not withdrawn by the voter yet
+func GetVoterRewardKey(addr types.Address) []byte {
+	return addr.Bytes()
+}
+
 func IsActiveRegistration(db StorageDatabase, name string, gid types.Gid) bool {
 	if value := db.GetStorageBySnapshotHash(&types.AddressRegister, GetRegisterKey(name, gid), nil); len(value) > 0 {
 		registration := new(types.Registration)
@@ -132,3 +145,12 @@ func GetRegistration(db StorageDatabase, gid types.Gid, name string) *types.Regi
 	}
 	return nil
 }
+
+func GetVoterReward(db StorageDatabase, addr types.Address) *big.Int {
+	value := db.GetStorageBySnapshotHash(&types.AddressRegister, GetVoterRewardKey(addr), nil)
+	voterReward := new(VariableVoterReward)
+	if err := ABIRegister.UnpackVariable(voterReward, VariableNameVoterReward, value); err == nil {
+		return voterReward.Amount
+	}
+	return big.NewInt(0)
+}
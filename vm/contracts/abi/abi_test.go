@@ -11,7 +11,7 @@ import (
 )
 
 func TestContractsABIInit(t *testing.T) {
-	tests := []string{jsonRegister, jsonVote, jsonPledge, jsonConsensusGroup, jsonMintage}
+	tests := []string{jsonRegister, jsonVote, jsonPledge, jsonConsensusGroup, jsonMintage, jsonMultisig, jsonVesting}
 	for _, data := range tests {
 		if _, err := abi.JSONToABIContract(strings.NewReader(jsonRegister)); err != nil {
 			t.Fatalf("json to abi failed, %v, %v", data, err)
@@ -0,0 +1,116 @@
+package abi
+
+import (
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/monitor"
+	"github.com/vitelabs/go-vite/vm/abi"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	jsonVesting = `
+	[
+		{"type":"function","name":"Lock","inputs":[{"name":"beneficial","type":"address"},{"name":"cliffHeight","type":"uint64"},{"name":"vestingHeight","type":"uint64"}]},
+		{"type":"function","name":"Claim","inputs":[{"name":"vestingId","type":"bytes32"}]},
+		{"type":"variable","name":"vestingInfo","inputs":[{"name":"locker","type":"address"},{"name":"tokenId","type":"tokenId"},{"name":"amount","type":"uint256"},{"name":"claimed","type":"uint256"},{"name":"startHeight","type":"uint64"},{"name":"cliffHeight","type":"uint64"},{"name":"endHeight","type":"uint64"}]}
+	]`
+
+	MethodNameVestingLock   = "Lock"
+	MethodNameVestingClaim  = "Claim"
+	VariableNameVestingInfo = "vestingInfo"
+)
+
+var (
+	ABIVesting, _ = abi.JSONToABIContract(strings.NewReader(jsonVesting))
+)
+
+// the cliff height and the vesting height are counted in snapshot blocks from the receive of the lock
+type ParamVestingLock struct {
+	Beneficial    types.Address
+	CliffHeight   uint64
+	VestingHeight uint64
+}
+
+// VestingInfo is a deposit released linearly from the start height to the end height,
+// nothing can be claimed before the cliff height.
+type VestingInfo struct {
+	Locker      types.Address
+	TokenId     types.TokenTypeId
+	Amount      *big.Int
+	Claimed     *big.Int
+	StartHeight uint64
+	CliffHeight uint64
+	EndHeight   uint64
+	VestingId   types.Hash
+}
+
+// Released returns the released amount at the snapshot height, including the claimed amount
+func (v *VestingInfo) Released(height uint64) *big.Int {
+	if height < v.CliffHeight {
+		return big.NewInt(0)
+	}
+	if height >= v.EndHeight {
+		return new(big.Int).Set(v.Amount)
+	}
+	released := new(big.Int).Mul(v.Amount, new(big.Int).SetUint64(height-v.StartHeight))
+	return released.Div(released, new(big.Int).SetUint64(v.EndHeight-v.StartHeight))
+}
+
+// Claimable returns the released amount at the snapshot height which is not claimed yet
+func (v *VestingInfo) Claimable(height uint64) *big.Int {
+	return new(big.Int).Sub(v.Released(height), v.Claimed)
+}
+
+// Remaining returns the amount which is not claimed yet
+func (v *VestingInfo) Remaining() *big.Int {
+	return new(big.Int).Sub(v.Amount, v.Claimed)
+}
+
+// the vesting id is the hash of the send block which locks the deposit
+func GetVestingKey(beneficial types.Address, vestingId types.Hash) []byte {
+	return append(beneficial.Bytes(), vestingId.Bytes()...)
+}
+func IsVestingKey(key []byte) bool {
+	return len(key) == types.AddressSize+types.HashSize
+}
+func GetVestingIdFromVestingKey(key []byte) types.Hash {
+	vestingId, _ := types.BytesToHash(key[types.AddressSize:])
+	return vestingId
+}
+
+func GetVestingInfo(db StorageDatabase, beneficial types.Address, vestingId types.Hash) *VestingInfo {
+	data := db.GetStorageBySnapshotHash(&types.AddressVesting, GetVestingKey(beneficial, vestingId), nil)
+	if len(data) > 0 {
+		vestingInfo := new(VestingInfo)
+		if err := ABIVesting.UnpackVariable(vestingInfo, VariableNameVestingInfo, data); err == nil {
+			vestingInfo.VestingId = vestingId
+			return vestingInfo
+		}
+	}
+	return nil
+}
+
+func GetVestingInfoList(db StorageDatabase, beneficial types.Address) []*VestingInfo {
+	defer monitor.LogTime("vm", "GetVestingInfoList", time.Now())
+	vestingInfoList := make([]*VestingInfo, 0)
+	iterator := db.NewStorageIteratorBySnapshotHash(&types.AddressVesting, beneficial.Bytes(), nil)
+	if iterator == nil {
+		return vestingInfoList
+	}
+	for {
+		key, value, ok := iterator.Next()
+		if !ok {
+			break
+		}
+		if IsVestingKey(key) {
+			vestingInfo := new(VestingInfo)
+			if err := ABIVesting.UnpackVariable(vestingInfo, VariableNameVestingInfo, value); err == nil {
+				vestingInfo.VestingId = GetVestingIdFromVestingKey(key)
+				vestingInfoList = append(vestingInfoList, vestingInfo)
+			}
+		}
+	}
+	return vestingInfoList
+}
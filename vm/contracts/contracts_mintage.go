@@ -73,6 +73,10 @@ func CheckToken(param cabi.ParamMintage) error {
 func (p *MethodMintage) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
 	param := new(cabi.ParamMintage)
 	cabi.ABIMintage.UnpackMethod(param, cabi.MethodNameMintage, sendBlock.Data)
+	return receiveMintage(db, block, sendBlock, *param)
+}
+
+func receiveMintage(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock, param cabi.ParamMintage) ([]*SendBlock, error) {
 	key := cabi.GetMintageKey(param.TokenId)
 	if len(db.GetStorage(&block.AccountAddress, key)) > 0 {
 		return nil, util.ErrIdCollision
@@ -145,7 +149,7 @@ func (p *MethodMintageCancelPledge) DoReceive(db vmctxt_interface.VmDatabase, bl
 
 	if tokenInfo.Owner != sendBlock.AccountAddress ||
 		tokenInfo.PledgeAmount.Sign() == 0 ||
-		tokenInfo.WithdrawHeight > db.CurrentSnapshotBlock().Height {
+		!isHeightReached(db, tokenInfo.WithdrawHeight) {
 		return nil, errors.New("cannot withdraw mintage pledge, status error")
 	}
 
@@ -173,3 +177,246 @@ func (p *MethodMintageCancelPledge) DoReceive(db vmctxt_interface.VmDatabase, bl
 	}
 	return nil, nil
 }
+
+type MethodMintageReIssuable struct{}
+
+func (p *MethodMintageReIssuable) GetFee(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock) (*big.Int, error) {
+	return (&MethodMintage{}).GetFee(db, block)
+}
+
+func (p *MethodMintageReIssuable) GetRefundData() []byte {
+	return []byte{3}
+}
+
+// mintage a token which can be reissued by the owner until the max supply
+func (p *MethodMintageReIssuable) DoSend(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, quotaLeft uint64) (uint64, error) {
+	quotaLeft, err := util.UseQuota(quotaLeft, MintageGas)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if !isReIssueActivated(db) {
+		return quotaLeft, errors.New("reissue not activated")
+	}
+	param := new(cabi.ParamMintageReIssuable)
+	err = cabi.ABIMintage.UnpackMethod(param, cabi.MethodNameMintageReIssuable, block.Data)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if err = CheckToken(param.ToParamMintage()); err != nil {
+		return quotaLeft, err
+	}
+	if param.MaxSupply.Cmp(param.TotalSupply) < 0 || param.MaxSupply.Cmp(helper.Tt256m1) > 0 {
+		return quotaLeft, errors.New("invalid max supply")
+	}
+	tokenId := cabi.NewTokenId(block.AccountAddress, block.Height, block.PrevHash, block.SnapshotHash)
+	if cabi.GetTokenById(db, tokenId) != nil {
+		return quotaLeft, util.ErrIdCollision
+	}
+	block.Data, _ = cabi.ABIMintage.PackMethod(
+		cabi.MethodNameMintageReIssuable,
+		tokenId,
+		param.TokenName,
+		param.TokenSymbol,
+		param.TotalSupply,
+		param.Decimals,
+		param.MaxSupply)
+	return quotaLeft, nil
+}
+func (p *MethodMintageReIssuable) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
+	param := new(cabi.ParamMintageReIssuable)
+	cabi.ABIMintage.UnpackMethod(param, cabi.MethodNameMintageReIssuable, sendBlock.Data)
+	sendBlockList, err := receiveMintage(db, block, sendBlock, param.ToParamMintage())
+	if err != nil {
+		return nil, err
+	}
+	reIssuable, _ := cabi.ABIMintage.PackVariable(cabi.VariableNameMintageReIssuable, param.MaxSupply)
+	db.SetStorage(cabi.GetReIssuableKey(param.TokenId), reIssuable)
+	return sendBlockList, nil
+}
+
+type MethodMintageReIssue struct{}
+
+func (p *MethodMintageReIssue) GetFee(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (p *MethodMintageReIssue) GetRefundData() []byte {
+	return []byte{4}
+}
+
+// issue more supply of a reissuable token to the beneficial, only the owner can reissue
+func (p *MethodMintageReIssue) DoSend(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, quotaLeft uint64) (uint64, error) {
+	quotaLeft, err := util.UseQuota(quotaLeft, MintageReIssueGas)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if !isReIssueActivated(db) {
+		return quotaLeft, errors.New("reissue not activated")
+	}
+	if block.Amount.Sign() > 0 {
+		return quotaLeft, errors.New("invalid block data")
+	}
+	param := new(cabi.ParamReIssue)
+	if err = cabi.ABIMintage.UnpackMethod(param, cabi.MethodNameMintageReIssue, block.Data); err != nil {
+		return quotaLeft, util.ErrInvalidMethodParam
+	}
+	if param.Amount.Sign() <= 0 {
+		return quotaLeft, errors.New("invalid reissue amount")
+	}
+	return quotaLeft, nil
+}
+func (p *MethodMintageReIssue) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
+	param := new(cabi.ParamReIssue)
+	cabi.ABIMintage.UnpackMethod(param, cabi.MethodNameMintageReIssue, sendBlock.Data)
+	tokenInfo, err := getOwnedReIssuableToken(db, block.AccountAddress, param.TokenId, sendBlock.AccountAddress)
+	if err != nil {
+		return nil, err
+	}
+	tokenInfo.TotalSupply.Add(tokenInfo.TotalSupply, param.Amount)
+	if tokenInfo.TotalSupply.Cmp(tokenInfo.MaxSupply) > 0 {
+		return nil, errors.New("reissue amount exceeds max supply")
+	}
+	setTokenInfo(db, param.TokenId, tokenInfo)
+	addMintageLog(db, cabi.EventNameMintageReIssue, param.TokenId, param.Amount, param.Beneficial)
+	return []*SendBlock{
+		{
+			block,
+			param.Beneficial,
+			ledger.BlockTypeSendReward,
+			param.Amount,
+			param.TokenId,
+			[]byte{},
+		},
+	}, nil
+}
+
+type MethodMintageBurn struct{}
+
+func (p *MethodMintageBurn) GetFee(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (p *MethodMintageBurn) GetRefundData() []byte {
+	return []byte{5}
+}
+
+// burn the reissuable token sent to the contract, only the owner can burn
+func (p *MethodMintageBurn) DoSend(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, quotaLeft uint64) (uint64, error) {
+	quotaLeft, err := util.UseQuota(quotaLeft, MintageBurnGas)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if !isReIssueActivated(db) {
+		return quotaLeft, errors.New("reissue not activated")
+	}
+	if block.Amount.Sign() <= 0 {
+		return quotaLeft, errors.New("invalid block data")
+	}
+	return quotaLeft, nil
+}
+func (p *MethodMintageBurn) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
+	tokenInfo, err := getOwnedReIssuableToken(db, block.AccountAddress, sendBlock.TokenId, sendBlock.AccountAddress)
+	if err != nil {
+		return nil, err
+	}
+	if tokenInfo.TotalSupply.Cmp(sendBlock.Amount) < 0 {
+		return nil, errors.New("burn amount exceeds total supply")
+	}
+	tokenInfo.TotalSupply.Sub(tokenInfo.TotalSupply, sendBlock.Amount)
+	setTokenInfo(db, sendBlock.TokenId, tokenInfo)
+	db.SubBalance(&sendBlock.TokenId, sendBlock.Amount)
+	addMintageLog(db, cabi.EventNameMintageBurn, sendBlock.TokenId, sendBlock.AccountAddress, sendBlock.Amount)
+	return nil, nil
+}
+
+type MethodMintageTransferOwner struct{}
+
+func (p *MethodMintageTransferOwner) GetFee(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (p *MethodMintageTransferOwner) GetRefundData() []byte {
+	return []byte{6}
+}
+
+// transfer the owner of a token, the mintage pledge must be cancelled before
+func (p *MethodMintageTransferOwner) DoSend(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, quotaLeft uint64) (uint64, error) {
+	quotaLeft, err := util.UseQuota(quotaLeft, MintageTransferOwnerGas)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if !isReIssueActivated(db) {
+		return quotaLeft, errors.New("reissue not activated")
+	}
+	if block.Amount.Sign() > 0 {
+		return quotaLeft, errors.New("invalid block data")
+	}
+	param := new(cabi.ParamTransferOwner)
+	if err = cabi.ABIMintage.UnpackMethod(param, cabi.MethodNameMintageTransferOwner, block.Data); err != nil {
+		return quotaLeft, util.ErrInvalidMethodParam
+	}
+	return quotaLeft, nil
+}
+func (p *MethodMintageTransferOwner) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
+	param := new(cabi.ParamTransferOwner)
+	cabi.ABIMintage.UnpackMethod(param, cabi.MethodNameMintageTransferOwner, sendBlock.Data)
+	tokenInfo, err := getTokenInfo(db, block.AccountAddress, param.TokenId)
+	if err != nil {
+		return nil, err
+	}
+	if tokenInfo.Owner != sendBlock.AccountAddress {
+		return nil, errors.New("not token owner")
+	}
+	if tokenInfo.PledgeAmount.Sign() > 0 {
+		return nil, errors.New("cannot transfer owner, mintage pledge not cancelled")
+	}
+	tokenInfo.Owner = param.NewOwner
+	setTokenInfo(db, param.TokenId, tokenInfo)
+	addMintageLog(db, cabi.EventNameMintageTransferOwner, param.TokenId, param.NewOwner)
+	return nil, nil
+}
+
+func getTokenInfo(db vmctxt_interface.VmDatabase, contractAddr types.Address, tokenId types.TokenTypeId) (*types.TokenInfo, error) {
+	tokenInfo := new(types.TokenInfo)
+	if err := cabi.ABIMintage.UnpackVariable(tokenInfo, cabi.VariableNameMintage, db.GetStorage(&contractAddr, cabi.GetMintageKey(tokenId))); err != nil {
+		return nil, errors.New("token not exist")
+	}
+	reIssuable := new(cabi.VariableReIssuable)
+	if err := cabi.ABIMintage.UnpackVariable(reIssuable, cabi.VariableNameMintageReIssuable, db.GetStorage(&contractAddr, cabi.GetReIssuableKey(tokenId))); err == nil {
+		tokenInfo.IsReIssuable = true
+		tokenInfo.MaxSupply = reIssuable.MaxSupply
+	}
+	return tokenInfo, nil
+}
+
+func getOwnedReIssuableToken(db vmctxt_interface.VmDatabase, contractAddr types.Address, tokenId types.TokenTypeId, owner types.Address) (*types.TokenInfo, error) {
+	tokenInfo, err := getTokenInfo(db, contractAddr, tokenId)
+	if err != nil {
+		return nil, err
+	}
+	if tokenInfo.Owner != owner {
+		return nil, errors.New("not token owner")
+	}
+	if !tokenInfo.IsReIssuable {
+		return nil, errors.New("token not reissuable")
+	}
+	return tokenInfo, nil
+}
+
+func setTokenInfo(db vmctxt_interface.VmDatabase, tokenId types.TokenTypeId, tokenInfo *types.TokenInfo) {
+	newTokenInfo, _ := cabi.ABIMintage.PackVariable(
+		cabi.VariableNameMintage,
+		tokenInfo.TokenName,
+		tokenInfo.TokenSymbol,
+		tokenInfo.TotalSupply,
+		tokenInfo.Decimals,
+		tokenInfo.Owner,
+		tokenInfo.PledgeAmount,
+		tokenInfo.WithdrawHeight)
+	db.SetStorage(cabi.GetMintageKey(tokenId), newTokenInfo)
+}
+
+func addMintageLog(db vmctxt_interface.VmDatabase, name string, args ...interface{}) {
+	topics, data, _ := cabi.ABIMintage.PackEvent(name, args...)
+	db.AddLog(&ledger.VmLog{Topics: topics, Data: data})
+}
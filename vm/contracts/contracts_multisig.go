@@ -0,0 +1,321 @@
+package contracts
+
+import (
+	"errors"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	cabi "github.com/vitelabs/go-vite/vm/contracts/abi"
+	"github.com/vitelabs/go-vite/vm/util"
+	"github.com/vitelabs/go-vite/vm_context/vmctxt_interface"
+	"math/big"
+)
+
+type MethodCreateMultisig struct{}
+
+func (p *MethodCreateMultisig) GetFee(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (p *MethodCreateMultisig) GetRefundData() []byte {
+	return []byte{1}
+}
+
+// create a multisig wallet of m-of-n owners, the amount of the send block is deposited to the wallet
+func (p *MethodCreateMultisig) DoSend(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, quotaLeft uint64) (uint64, error) {
+	quotaLeft, err := util.UseQuota(quotaLeft, CreateMultisigGas)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if !IsUserAccount(db, block.AccountAddress) {
+		return quotaLeft, errors.New("invalid block data")
+	}
+	param := new(cabi.ParamCreateMultisig)
+	if err = cabi.ABIMultisig.UnpackMethod(param, cabi.MethodNameCreateMultisig, block.Data); err != nil {
+		return quotaLeft, util.ErrInvalidMethodParam
+	}
+	if err = checkMultisigOwners(param.Owners, param.Threshold); err != nil {
+		return quotaLeft, err
+	}
+	return quotaLeft, nil
+}
+
+func checkMultisigOwners(owners []types.Address, threshold uint8) error {
+	if len(owners) == 0 || len(owners) > multisigOwnerCountMax ||
+		threshold == 0 || int(threshold) > len(owners) {
+		return errors.New("invalid multisig threshold")
+	}
+	ownerSet := make(map[types.Address]struct{}, len(owners))
+	for _, owner := range owners {
+		if _, ok := ownerSet[owner]; ok {
+			return errors.New("duplicate multisig owner")
+		}
+		ownerSet[owner] = struct{}{}
+	}
+	return nil
+}
+
+func (p *MethodCreateMultisig) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
+	param := new(cabi.ParamCreateMultisig)
+	cabi.ABIMultisig.UnpackMethod(param, cabi.MethodNameCreateMultisig, sendBlock.Data)
+	walletId := sendBlock.Hash
+	key := cabi.GetMultisigWalletKey(walletId)
+	if len(db.GetStorage(&block.AccountAddress, key)) > 0 {
+		return nil, util.ErrIdCollision
+	}
+	wallet, _ := cabi.ABIMultisig.PackVariable(cabi.VariableNameMultisigWallet, param.Owners, param.Threshold)
+	db.SetStorage(key, wallet)
+	if sendBlock.Amount.Sign() > 0 {
+		addMultisigBalance(db, block.AccountAddress, walletId, sendBlock.TokenId, sendBlock.Amount)
+	}
+	return nil, nil
+}
+
+type MethodMultisigDeposit struct{}
+
+func (p *MethodMultisigDeposit) GetFee(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (p *MethodMultisigDeposit) GetRefundData() []byte {
+	return []byte{2}
+}
+
+// deposit the amount of the send block to a multisig wallet, anyone can deposit
+func (p *MethodMultisigDeposit) DoSend(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, quotaLeft uint64) (uint64, error) {
+	quotaLeft, err := util.UseQuota(quotaLeft, MultisigDepositGas)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if block.Amount.Sign() <= 0 {
+		return quotaLeft, errors.New("invalid block data")
+	}
+	walletId := new(types.Hash)
+	if err = cabi.ABIMultisig.UnpackMethod(walletId, cabi.MethodNameMultisigDeposit, block.Data); err != nil {
+		return quotaLeft, util.ErrInvalidMethodParam
+	}
+	return quotaLeft, nil
+}
+
+func (p *MethodMultisigDeposit) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
+	walletId := new(types.Hash)
+	cabi.ABIMultisig.UnpackMethod(walletId, cabi.MethodNameMultisigDeposit, sendBlock.Data)
+	if _, err := getMultisigWallet(db, block.AccountAddress, *walletId); err != nil {
+		return nil, err
+	}
+	addMultisigBalance(db, block.AccountAddress, *walletId, sendBlock.TokenId, sendBlock.Amount)
+	return nil, nil
+}
+
+type MethodMultisigPropose struct{}
+
+func (p *MethodMultisigPropose) GetFee(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (p *MethodMultisigPropose) GetRefundData() []byte {
+	return []byte{3}
+}
+
+// propose a transfer from a multisig wallet, the proposal is approved by the proposer
+func (p *MethodMultisigPropose) DoSend(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, quotaLeft uint64) (uint64, error) {
+	quotaLeft, err := util.UseQuota(quotaLeft, MultisigProposeGas)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if block.Amount.Sign() > 0 {
+		return quotaLeft, errors.New("invalid block data")
+	}
+	param := new(cabi.ParamMultisigPropose)
+	if err = cabi.ABIMultisig.UnpackMethod(param, cabi.MethodNameMultisigPropose, block.Data); err != nil {
+		return quotaLeft, util.ErrInvalidMethodParam
+	}
+	if param.Amount.Sign() <= 0 {
+		return quotaLeft, errors.New("invalid proposal amount")
+	}
+	return quotaLeft, nil
+}
+
+func (p *MethodMultisigPropose) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
+	param := new(cabi.ParamMultisigPropose)
+	cabi.ABIMultisig.UnpackMethod(param, cabi.MethodNameMultisigPropose, sendBlock.Data)
+	wallet, err := getMultisigWallet(db, block.AccountAddress, param.WalletId)
+	if err != nil {
+		return nil, err
+	}
+	if !wallet.IsOwner(sendBlock.AccountAddress) {
+		return nil, errors.New("not multisig owner")
+	}
+	proposal := &cabi.MultisigProposal{
+		To:        param.To,
+		TokenId:   param.TokenId,
+		Amount:    param.Amount,
+		Approvers: []types.Address{sendBlock.AccountAddress},
+	}
+	return approveMultisigProposal(db, block, param.WalletId, sendBlock.Hash, wallet, proposal)
+}
+
+type MethodMultisigApprove struct{}
+
+func (p *MethodMultisigApprove) GetFee(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (p *MethodMultisigApprove) GetRefundData() []byte {
+	return []byte{4}
+}
+
+// approve a proposal of a multisig wallet, the transfer is sent once the approvals reach the threshold
+func (p *MethodMultisigApprove) DoSend(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, quotaLeft uint64) (uint64, error) {
+	quotaLeft, err := util.UseQuota(quotaLeft, MultisigApproveGas)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if block.Amount.Sign() > 0 {
+		return quotaLeft, errors.New("invalid block data")
+	}
+	param := new(cabi.ParamMultisigProposal)
+	if err = cabi.ABIMultisig.UnpackMethod(param, cabi.MethodNameMultisigApprove, block.Data); err != nil {
+		return quotaLeft, util.ErrInvalidMethodParam
+	}
+	return quotaLeft, nil
+}
+
+func (p *MethodMultisigApprove) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
+	param := new(cabi.ParamMultisigProposal)
+	cabi.ABIMultisig.UnpackMethod(param, cabi.MethodNameMultisigApprove, sendBlock.Data)
+	wallet, err := getMultisigWallet(db, block.AccountAddress, param.WalletId)
+	if err != nil {
+		return nil, err
+	}
+	if !wallet.IsOwner(sendBlock.AccountAddress) {
+		return nil, errors.New("not multisig owner")
+	}
+	proposal, err := getMultisigProposal(db, block.AccountAddress, param.WalletId, param.ProposalId)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.IsApprovedBy(sendBlock.AccountAddress) {
+		return nil, errors.New("proposal already approved")
+	}
+	proposal.Approvers = append(proposal.Approvers, sendBlock.AccountAddress)
+	return approveMultisigProposal(db, block, param.WalletId, param.ProposalId, wallet, proposal)
+}
+
+type MethodMultisigRevoke struct{}
+
+func (p *MethodMultisigRevoke) GetFee(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (p *MethodMultisigRevoke) GetRefundData() []byte {
+	return []byte{5}
+}
+
+// revoke the approval of a pending proposal, the proposal is removed when no approval is left
+func (p *MethodMultisigRevoke) DoSend(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, quotaLeft uint64) (uint64, error) {
+	quotaLeft, err := util.UseQuota(quotaLeft, MultisigRevokeGas)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if block.Amount.Sign() > 0 {
+		return quotaLeft, errors.New("invalid block data")
+	}
+	param := new(cabi.ParamMultisigProposal)
+	if err = cabi.ABIMultisig.UnpackMethod(param, cabi.MethodNameMultisigRevoke, block.Data); err != nil {
+		return quotaLeft, util.ErrInvalidMethodParam
+	}
+	return quotaLeft, nil
+}
+
+func (p *MethodMultisigRevoke) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
+	param := new(cabi.ParamMultisigProposal)
+	cabi.ABIMultisig.UnpackMethod(param, cabi.MethodNameMultisigRevoke, sendBlock.Data)
+	proposal, err := getMultisigProposal(db, block.AccountAddress, param.WalletId, param.ProposalId)
+	if err != nil {
+		return nil, err
+	}
+	if !proposal.IsApprovedBy(sendBlock.AccountAddress) {
+		return nil, errors.New("proposal not approved")
+	}
+	approvers := make([]types.Address, 0, len(proposal.Approvers)-1)
+	for _, approver := range proposal.Approvers {
+		if approver != sendBlock.AccountAddress {
+			approvers = append(approvers, approver)
+		}
+	}
+	key := cabi.GetMultisigProposalKey(param.WalletId, param.ProposalId)
+	if len(approvers) == 0 {
+		db.SetStorage(key, nil)
+	} else {
+		proposalData, _ := cabi.ABIMultisig.PackVariable(cabi.VariableNameMultisigProposal, proposal.To, proposal.TokenId, proposal.Amount, approvers)
+		db.SetStorage(key, proposalData)
+	}
+	return nil, nil
+}
+
+func getMultisigWallet(db vmctxt_interface.VmDatabase, contractAddr types.Address, walletId types.Hash) (*cabi.MultisigWallet, error) {
+	wallet := new(cabi.MultisigWallet)
+	if err := cabi.ABIMultisig.UnpackVariable(wallet, cabi.VariableNameMultisigWallet, db.GetStorage(&contractAddr, cabi.GetMultisigWalletKey(walletId))); err != nil {
+		return nil, errors.New("multisig wallet not exist")
+	}
+	return wallet, nil
+}
+
+func getMultisigProposal(db vmctxt_interface.VmDatabase, contractAddr types.Address, walletId types.Hash, proposalId types.Hash) (*cabi.MultisigProposal, error) {
+	proposal := new(cabi.MultisigProposal)
+	if err := cabi.ABIMultisig.UnpackVariable(proposal, cabi.VariableNameMultisigProposal, db.GetStorage(&contractAddr, cabi.GetMultisigProposalKey(walletId, proposalId))); err != nil {
+		return nil, errors.New("multisig proposal not exist")
+	}
+	return proposal, nil
+}
+
+func getMultisigBalance(db vmctxt_interface.VmDatabase, contractAddr types.Address, walletId types.Hash, tokenId types.TokenTypeId) *big.Int {
+	balance := new(cabi.VariableMultisigBalance)
+	if err := cabi.ABIMultisig.UnpackVariable(balance, cabi.VariableNameMultisigBalance, db.GetStorage(&contractAddr, cabi.GetMultisigBalanceKey(walletId, tokenId))); err == nil {
+		return balance.Amount
+	}
+	return big.NewInt(0)
+}
+
+func addMultisigBalance(db vmctxt_interface.VmDatabase, contractAddr types.Address, walletId types.Hash, tokenId types.TokenTypeId, amount *big.Int) {
+	balance := getMultisigBalance(db, contractAddr, walletId, tokenId)
+	balance.Add(balance, amount)
+	balanceData, _ := cabi.ABIMultisig.PackVariable(cabi.VariableNameMultisigBalance, balance)
+	db.SetStorage(cabi.GetMultisigBalanceKey(walletId, tokenId), balanceData)
+}
+
+// approveMultisigProposal saves the proposal while the approvals are below the threshold,
+// otherwise removes the proposal and sends the transfer from the wallet balance.
+// An approval reaching the threshold fails if the wallet balance is not enough, it can be sent again after deposit.
+func approveMultisigProposal(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, walletId types.Hash, proposalId types.Hash, wallet *cabi.MultisigWallet, proposal *cabi.MultisigProposal) ([]*SendBlock, error) {
+	proposalKey := cabi.GetMultisigProposalKey(walletId, proposalId)
+	if len(proposal.Approvers) < int(wallet.Threshold) {
+		proposalData, _ := cabi.ABIMultisig.PackVariable(cabi.VariableNameMultisigProposal, proposal.To, proposal.TokenId, proposal.Amount, proposal.Approvers)
+		db.SetStorage(proposalKey, proposalData)
+		return nil, nil
+	}
+
+	balance := getMultisigBalance(db, block.AccountAddress, walletId, proposal.TokenId)
+	if balance.Cmp(proposal.Amount) < 0 {
+		return nil, errors.New("insufficient multisig balance")
+	}
+	balance.Sub(balance, proposal.Amount)
+	balanceKey := cabi.GetMultisigBalanceKey(walletId, proposal.TokenId)
+	if balance.Sign() == 0 {
+		db.SetStorage(balanceKey, nil)
+	} else {
+		balanceData, _ := cabi.ABIMultisig.PackVariable(cabi.VariableNameMultisigBalance, balance)
+		db.SetStorage(balanceKey, balanceData)
+	}
+	db.SetStorage(proposalKey, nil)
+	return []*SendBlock{
+		{
+			block,
+			proposal.To,
+			ledger.BlockTypeSendCall,
+			proposal.Amount,
+			proposal.TokenId,
+			[]byte{},
+		},
+	}, nil
+}
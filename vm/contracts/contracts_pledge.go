@@ -104,7 +104,7 @@ func (p *MethodCancelPledge) DoReceive(db vmctxt_interface.VmDatabase, block *le
 	pledgeKey := cabi.GetPledgeKey(sendBlock.AccountAddress, beneficialKey)
 	oldPledge := new(cabi.PledgeInfo)
 	err := cabi.ABIPledge.UnpackVariable(oldPledge, cabi.VariableNamePledgeInfo, db.GetStorage(&block.AccountAddress, pledgeKey))
-	if err != nil || oldPledge.WithdrawHeight > db.CurrentSnapshotBlock().Height || oldPledge.Amount.Cmp(param.Amount) < 0 {
+	if err != nil || !isHeightReached(db, oldPledge.WithdrawHeight) || oldPledge.Amount.Cmp(param.Amount) < 0 {
 		return nil, errors.New("pledge not yet due")
 	}
 	oldPledge.Amount.Sub(oldPledge.Amount, param.Amount)
@@ -139,3 +139,151 @@ func (p *MethodCancelPledge) DoReceive(db vmctxt_interface.VmDatabase, block *le
 		},
 	}, nil
 }
+
+type MethodAgentPledge struct{}
+
+func (p *MethodAgentPledge) GetFee(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (p *MethodAgentPledge) GetRefundData() []byte {
+	return []byte{3}
+}
+
+// pledge ViteToken as an agent for a beneficial, only the agent can cancel it after the expiry height
+func (p *MethodAgentPledge) DoSend(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, quotaLeft uint64) (uint64, error) {
+	quotaLeft, err := util.UseQuota(quotaLeft, AgentPledgeGas)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if !isAgentPledgeActivated(db) {
+		return quotaLeft, errors.New("agent pledge not activated")
+	}
+	if block.Amount.Cmp(pledgeAmountMin) < 0 ||
+		!util.IsViteToken(block.TokenId) ||
+		!IsUserAccount(db, block.AccountAddress) {
+		return quotaLeft, errors.New("invalid block data")
+	}
+	param := new(cabi.ParamAgentPledge)
+	if err = cabi.ABIPledge.UnpackMethod(param, cabi.MethodNameAgentPledge, block.Data); err != nil {
+		return quotaLeft, util.ErrInvalidMethodParam
+	}
+	if param.PledgeHeight < nodeConfig.params.MinPledgeHeight || param.PledgeHeight > agentPledgeHeightMax {
+		return quotaLeft, errors.New("invalid pledge height")
+	}
+	return quotaLeft, nil
+}
+func (p *MethodAgentPledge) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
+	param := new(cabi.ParamAgentPledge)
+	cabi.ABIPledge.UnpackMethod(param, cabi.MethodNameAgentPledge, sendBlock.Data)
+	pledgeKey := cabi.GetAgentPledgeKey(sendBlock.AccountAddress, param.Beneficial)
+	amount := big.NewInt(0)
+	withdrawHeight := db.CurrentSnapshotBlock().Height + param.PledgeHeight
+	oldPledge := new(cabi.PledgeInfo)
+	if err := cabi.ABIPledge.UnpackVariable(oldPledge, cabi.VariableNamePledgeInfo, db.GetStorage(&block.AccountAddress, pledgeKey)); err == nil {
+		amount = oldPledge.Amount
+		if oldPledge.WithdrawHeight > withdrawHeight {
+			withdrawHeight = oldPledge.WithdrawHeight
+		}
+	}
+	amount.Add(amount, sendBlock.Amount)
+	pledgeInfo, _ := cabi.ABIPledge.PackVariable(cabi.VariableNamePledgeInfo, amount, withdrawHeight)
+	db.SetStorage(pledgeKey, pledgeInfo)
+
+	beneficialKey := cabi.GetPledgeBeneficialKey(param.Beneficial)
+	beneficialAmount := big.NewInt(0)
+	oldBeneficial := new(cabi.VariablePledgeBeneficial)
+	if err := cabi.ABIPledge.UnpackVariable(oldBeneficial, cabi.VariableNamePledgeBeneficial, db.GetStorage(&block.AccountAddress, beneficialKey)); err == nil {
+		beneficialAmount = oldBeneficial.Amount
+	}
+	beneficialAmount.Add(beneficialAmount, sendBlock.Amount)
+	beneficialData, _ := cabi.ABIPledge.PackVariable(cabi.VariableNamePledgeBeneficial, beneficialAmount)
+	db.SetStorage(beneficialKey, beneficialData)
+	return nil, nil
+}
+
+type MethodCancelAgentPledge struct{}
+
+func (p *MethodCancelAgentPledge) GetFee(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (p *MethodCancelAgentPledge) GetRefundData() []byte {
+	return []byte{4}
+}
+
+// cancel the expired agent pledges of the beneficial list in bulk, the total amount is refunded to the agent
+func (p *MethodCancelAgentPledge) DoSend(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, quotaLeft uint64) (uint64, error) {
+	quotaLeft, err := util.UseQuota(quotaLeft, CancelAgentPledgeGas)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if !isAgentPledgeActivated(db) {
+		return quotaLeft, errors.New("agent pledge not activated")
+	}
+	if block.Amount.Sign() > 0 ||
+		!IsUserAccount(db, block.AccountAddress) {
+		return quotaLeft, errors.New("invalid block data")
+	}
+	beneficialList := new([]types.Address)
+	if err = cabi.ABIPledge.UnpackMethod(beneficialList, cabi.MethodNameCancelAgentPledge, block.Data); err != nil {
+		return quotaLeft, util.ErrInvalidMethodParam
+	}
+	if len(*beneficialList) == 0 || len(*beneficialList) > agentPledgeCancelCountMax {
+		return quotaLeft, errors.New("invalid beneficial list")
+	}
+	beneficialMap := make(map[types.Address]bool, len(*beneficialList))
+	for _, beneficial := range *beneficialList {
+		if beneficialMap[beneficial] {
+			return quotaLeft, errors.New("duplicate beneficial")
+		}
+		beneficialMap[beneficial] = true
+	}
+	// every agent pledge costs extra gas to cancel
+	return util.UseQuota(quotaLeft, CancelAgentPledgeGas*uint64(len(*beneficialList)-1))
+}
+
+func (p *MethodCancelAgentPledge) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
+	beneficialList := new([]types.Address)
+	cabi.ABIPledge.UnpackMethod(beneficialList, cabi.MethodNameCancelAgentPledge, sendBlock.Data)
+	// check all the pledges before cancelling any of them
+	pledgeList := make([]*cabi.PledgeInfo, len(*beneficialList))
+	for i, beneficial := range *beneficialList {
+		pledge := new(cabi.PledgeInfo)
+		err := cabi.ABIPledge.UnpackVariable(pledge, cabi.VariableNamePledgeInfo, db.GetStorage(&block.AccountAddress, cabi.GetAgentPledgeKey(sendBlock.AccountAddress, beneficial)))
+		if err != nil || !isHeightReached(db, pledge.WithdrawHeight) {
+			return nil, errors.New("agent pledge not yet due")
+		}
+		pledgeList[i] = pledge
+	}
+	refundAmount := big.NewInt(0)
+	for i, beneficial := range *beneficialList {
+		pledge := pledgeList[i]
+		beneficialKey := cabi.GetPledgeBeneficialKey(beneficial)
+		oldBeneficial := new(cabi.VariablePledgeBeneficial)
+		err := cabi.ABIPledge.UnpackVariable(oldBeneficial, cabi.VariableNamePledgeBeneficial, db.GetStorage(&block.AccountAddress, beneficialKey))
+		if err != nil || oldBeneficial.Amount.Cmp(pledge.Amount) < 0 {
+			return nil, errors.New("invalid pledge amount")
+		}
+		oldBeneficial.Amount.Sub(oldBeneficial.Amount, pledge.Amount)
+
+		db.SetStorage(cabi.GetAgentPledgeKey(sendBlock.AccountAddress, beneficial), nil)
+		if oldBeneficial.Amount.Sign() == 0 {
+			db.SetStorage(beneficialKey, nil)
+		} else {
+			pledgeBeneficial, _ := cabi.ABIPledge.PackVariable(cabi.VariableNamePledgeBeneficial, oldBeneficial.Amount)
+			db.SetStorage(beneficialKey, pledgeBeneficial)
+		}
+		refundAmount.Add(refundAmount, pledge.Amount)
+	}
+	return []*SendBlock{
+		{
+			block,
+			sendBlock.AccountAddress,
+			ledger.BlockTypeSendCall,
+			refundAmount,
+			ledger.ViteTokenId,
+			[]byte{},
+		},
+	}, nil
+}
@@ -1,6 +1,7 @@
 package contracts
 
 import (
+	"bytes"
 	"errors"
 	"github.com/vitelabs/go-vite/common/helper"
 	"github.com/vitelabs/go-vite/common/types"
@@ -10,6 +11,7 @@ import (
 	"github.com/vitelabs/go-vite/vm/util"
 	"github.com/vitelabs/go-vite/vm_context/vmctxt_interface"
 	"math/big"
+	"sort"
 	"time"
 )
 
@@ -214,6 +216,9 @@ func (p *MethodReward) DoSend(db vmctxt_interface.VmDatabase, block *ledger.Acco
 	if err != nil {
 		return quotaLeft, err
 	}
+	if !isRewardActivated(db) {
+		return quotaLeft, errors.New("reward not activated")
+	}
 	if block.Amount.Sign() != 0 ||
 		!IsUserAccount(db, block.AccountAddress) {
 		return quotaLeft, errors.New("invalid block data")
@@ -236,10 +241,11 @@ func (p *MethodReward) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.A
 	if err != nil || sendBlock.AccountAddress != old.PledgeAddr {
 		return nil, errors.New("invalid owner")
 	}
-	_, endIndex, reward, _, err := CalcReward(db, old, param.Gid)
+	_, endIndex, rewardList, _, err := CalcRewardByDay(db, old, param.Gid)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	reward := SumRewardByDay(rewardList)
 	if endIndex != old.RewardIndex {
 		registerInfo, _ := cabi.ABIRegister.PackVariable(
 			cabi.VariableNameRegistration,
@@ -253,6 +259,9 @@ func (p *MethodReward) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.A
 			old.HisAddrList)
 		db.SetStorage(key, registerInfo)
 
+		if reward != nil && reward.Sign() > 0 {
+			reward = shareRewardWithVoters(db, rewardList, reward)
+		}
 		if reward != nil && reward.Sign() > 0 {
 			return []*SendBlock{
 				{
@@ -269,6 +278,134 @@ func (p *MethodReward) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.A
 	return nil, nil
 }
 
+// share part of the reward with the voters of the registration, the rest is returned to the registration
+func shareRewardWithVoters(db vmctxt_interface.VmDatabase, rewardList []*RewardOfDay, reward *big.Int) *big.Int {
+	shareMap, left := CalcVoterRewardShares(rewardList, reward)
+	voterList := make([]types.Address, 0, len(shareMap))
+	for voter := range shareMap {
+		voterList = append(voterList, voter)
+	}
+	sort.Slice(voterList, func(i, j int) bool {
+		return bytes.Compare(voterList[i].Bytes(), voterList[j].Bytes()) < 0
+	})
+	for _, voter := range voterList {
+		addVoterReward(db, voter, shareMap[voter])
+	}
+	return left
+}
+
+// CalcVoterRewardShares splits the voter part of a reward among the voters of the registration. The voter part
+// of each day is weighted by the reward of the day, and shared by the periods of the day in proportion to the
+// balances of the voters at the vote time of the period. Returns the shares and the amount left to the registration
+func CalcVoterRewardShares(rewardList []*RewardOfDay, reward *big.Int) (map[types.Address]*big.Int, *big.Int) {
+	shareMap := make(map[types.Address]*big.Int)
+	rewardSum := big.NewInt(0)
+	for _, rewardOfDay := range rewardList {
+		rewardSum.Add(rewardSum, rewardOfDay.Reward)
+	}
+	if reward.Sign() <= 0 || rewardSum.Sign() <= 0 {
+		return shareMap, reward
+	}
+	voterReward := new(big.Int).Mul(reward, new(big.Int).SetUint64(rewardVoterSharePercent))
+	voterReward.Quo(voterReward, big.NewInt(100))
+	left := new(big.Int).Set(reward)
+	for _, rewardOfDay := range rewardList {
+		if rewardOfDay.Reward.Sign() <= 0 {
+			continue
+		}
+		// share = voterReward * dayReward / rewardSum / periodCount * balance / totalBalance
+		dayReward := new(big.Int).Mul(voterReward, rewardOfDay.Reward)
+		periodCount := new(big.Int).SetUint64(rewardOfDay.EndIndex - rewardOfDay.StartIndex + 1)
+		for _, voterMap := range rewardOfDay.PeriodVoterMap {
+			totalBalance := big.NewInt(0)
+			for _, balance := range voterMap {
+				totalBalance.Add(totalBalance, balance)
+			}
+			if totalBalance.Sign() == 0 {
+				continue
+			}
+			divisor := new(big.Int).Mul(rewardSum, periodCount)
+			divisor.Mul(divisor, totalBalance)
+			for voter, balance := range voterMap {
+				share := new(big.Int).Mul(dayReward, balance)
+				share.Quo(share, divisor)
+				if share.Sign() == 0 {
+					continue
+				}
+				left.Sub(left, share)
+				if old, ok := shareMap[voter]; ok {
+					old.Add(old, share)
+				} else {
+					shareMap[voter] = share
+				}
+			}
+		}
+	}
+	return shareMap, left
+}
+
+func addVoterReward(db vmctxt_interface.VmDatabase, voter types.Address, amount *big.Int) {
+	key := cabi.GetVoterRewardKey(voter)
+	old := new(cabi.VariableVoterReward)
+	if err := cabi.ABIRegister.UnpackVariable(old, cabi.VariableNameVoterReward, db.GetStorage(&types.AddressRegister, key)); err == nil {
+		amount = new(big.Int).Add(old.Amount, amount)
+	}
+	voterReward, _ := cabi.ABIRegister.PackVariable(cabi.VariableNameVoterReward, amount)
+	db.SetStorage(key, voterReward)
+}
+
+type MethodWithdrawVoterReward struct {
+}
+
+func (p *MethodWithdrawVoterReward) GetFee(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (p *MethodWithdrawVoterReward) GetRefundData() []byte {
+	return []byte{5}
+}
+
+// withdraw the snapshot block reward shared with a voter
+func (p *MethodWithdrawVoterReward) DoSend(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, quotaLeft uint64) (uint64, error) {
+	quotaLeft, err := util.UseQuota(quotaLeft, WithdrawVoterRewardGas)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if !isRewardActivated(db) {
+		return quotaLeft, errors.New("reward not activated")
+	}
+	if block.Amount.Sign() != 0 ||
+		!IsUserAccount(db, block.AccountAddress) {
+		return quotaLeft, errors.New("invalid block data")
+	}
+	beneficialAddr := new(types.Address)
+	if err = cabi.ABIRegister.UnpackMethod(beneficialAddr, cabi.MethodNameWithdrawVoterReward, block.Data); err != nil {
+		return quotaLeft, util.ErrInvalidMethodParam
+	}
+	return quotaLeft, nil
+}
+func (p *MethodWithdrawVoterReward) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
+	beneficialAddr := new(types.Address)
+	cabi.ABIRegister.UnpackMethod(beneficialAddr, cabi.MethodNameWithdrawVoterReward, sendBlock.Data)
+	key := cabi.GetVoterRewardKey(sendBlock.AccountAddress)
+	voterReward := new(cabi.VariableVoterReward)
+	err := cabi.ABIRegister.UnpackVariable(voterReward, cabi.VariableNameVoterReward, db.GetStorage(&block.AccountAddress, key))
+	if err != nil || voterReward.Amount.Sign() <= 0 {
+		return nil, errors.New("no voter reward")
+	}
+	db.SetStorage(key, nil)
+	return []*SendBlock{
+		{
+			block,
+			*beneficialAddr,
+			ledger.BlockTypeSendReward,
+			voterReward.Amount,
+			ledger.ViteTokenId,
+			[]byte{},
+		},
+	}, nil
+}
+
 func IndexToTime(index uint64, genesisTime int64, periodTime uint64) int64 {
 	return genesisTime + int64(periodTime*index)
 }
@@ -279,38 +416,84 @@ func CalcMinRewardTime(registration *types.Registration, genesisTime int64, peri
 }
 
 func CalcReward(db vmctxt_interface.VmDatabase, old *types.Registration, gid types.Gid) (uint64, uint64, *big.Int, uint64, error) {
+	startIndex, endIndex, rewardList, periodTime, err := CalcRewardByDay(db, old, gid)
+	if err != nil {
+		return startIndex, endIndex, big.NewInt(0), periodTime, err
+	}
+	return startIndex, endIndex, SumRewardByDay(rewardList), periodTime, nil
+}
+
+// SumRewardByDay sums the reward of days before converting to the reward amount, same as CalcReward
+func SumRewardByDay(rewardList []*RewardOfDay) *big.Int {
+	if len(rewardList) == 0 {
+		return big.NewInt(0)
+	}
+	rewardF := new(big.Float).SetPrec(rewardPrecForFloat).SetInt64(0)
+	for _, rewardOfDay := range rewardList {
+		rewardF.Add(rewardF, rewardOfDay.rewardF)
+	}
+	return rewardFloatToInt(rewardF)
+}
+
+// nextRewardIndex is the first period index of the next reward withdrawn by the registration
+func nextRewardIndex(old *types.Registration) uint64 {
+	return old.RewardIndex + 1
+}
+
+// RewardIndexRange returns the first and the last period index of the reward withdrawn by the registration,
+// the end index is the one returned by CalcRewardByDay, both are the reward index if nothing can be withdrawn
+func RewardIndexRange(old *types.Registration, endIndex uint64) (uint64, uint64) {
+	if endIndex == old.RewardIndex {
+		return old.RewardIndex, endIndex
+	}
+	return nextRewardIndex(old), endIndex
+}
+
+// RewardOfDay is the snapshot block reward of a registration in a reward time unit,
+// the plan and actual block counts are summed over all the periods of the day
+type RewardOfDay struct {
+	StartIndex     uint64
+	EndIndex       uint64
+	PlanNum        uint64
+	ActualNum      uint64
+	Reward         *big.Int
+	PeriodVoterMap map[uint64]map[types.Address]*big.Int // balances of the voters of the registration in each period
+	rewardF        *big.Float
+}
+
+// CalcRewardByDay splits the reward which can be withdrawn by the registration into reward time units
+func CalcRewardByDay(db vmctxt_interface.VmDatabase, old *types.Registration, gid types.Gid) (uint64, uint64, []*RewardOfDay, uint64, error) {
 	currentSnapshotBlock := db.CurrentSnapshotBlock()
 	genesisTime := db.GetGenesisSnapshotBlock().Timestamp
 	groupInfo := cabi.GetConsensusGroup(db, gid)
 	if groupInfo == nil {
-		return old.RewardIndex, old.RewardIndex, big.NewInt(0), 0, errors.New("consensus group info not exist")
+		return old.RewardIndex, old.RewardIndex, nil, 0, errors.New("consensus group info not exist")
 	}
 	reader := core.NewReader(*genesisTime, groupInfo)
 	periodTime, err := reader.PeriodTime()
 	if err != nil {
-		return old.RewardIndex, old.RewardIndex, big.NewInt(0), 0, err
+		return old.RewardIndex, old.RewardIndex, nil, 0, err
 	}
 
 	if uint64(currentSnapshotBlock.Timestamp.Unix()) < periodTime+nodeConfig.params.RewardEndTimeLimit ||
 		old.RewardIndex == 0 {
-		return old.RewardIndex, old.RewardIndex, big.NewInt(0), periodTime, nil
+		return old.RewardIndex, old.RewardIndex, nil, periodTime, nil
 	}
 	var cancelIndex = uint64(0)
 	if !old.IsActive() {
 		cancelSnapsotBlock, _ := db.GetSnapshotBlockByHeight(old.CancelHeight)
 		cancelIndex, err = reader.TimeToIndex(*cancelSnapsotBlock.Timestamp)
 		if err != nil {
-			return old.RewardIndex, old.RewardIndex, big.NewInt(0), periodTime, err
+			return old.RewardIndex, old.RewardIndex, nil, periodTime, err
 		}
 		if old.RewardIndex >= cancelIndex {
-			return old.RewardIndex, old.RewardIndex, big.NewInt(0), periodTime, nil
+			return old.RewardIndex, old.RewardIndex, nil, periodTime, nil
 		}
 	}
 
 	indexPerDay := nodeConfig.params.RewardTimeUnit / periodTime
 
-	startIndex := old.RewardIndex
-	startIndex = startIndex + 1
+	startIndex := nextRewardIndex(old)
 
 	endIndex := uint64(0)
 	if !old.IsActive() {
@@ -319,7 +502,7 @@ func CalcReward(db vmctxt_interface.VmDatabase, old *types.Registration, gid typ
 		endTime := uint64(db.CurrentSnapshotBlock().Timestamp.Unix()) - nodeConfig.params.RewardEndTimeLimit
 		endIndex, err = reader.TimeToIndex(time.Unix(int64(endTime), 0))
 		if err != nil {
-			return old.RewardIndex, old.RewardIndex, big.NewInt(0), periodTime, err
+			return old.RewardIndex, old.RewardIndex, nil, periodTime, err
 		}
 	}
 
@@ -335,10 +518,10 @@ func CalcReward(db vmctxt_interface.VmDatabase, old *types.Registration, gid typ
 	indexCount := endIndex - startIndex + 1
 
 	if endIndex < startIndex {
-		return old.RewardIndex, old.RewardIndex, big.NewInt(0), periodTime, nil
+		return old.RewardIndex, old.RewardIndex, nil, periodTime, nil
 	}
 
-	rewardF := new(big.Float).SetPrec(rewardPrecForFloat).SetInt64(0)
+	rewardList := make([]*RewardOfDay, 0)
 	tmp1 := new(big.Float).SetPrec(rewardPrecForFloat).SetInt64(0)
 	tmp2 := new(big.Float).SetPrec(rewardPrecForFloat).SetInt64(0)
 	tmp3 := new(big.Float).SetPrec(rewardPrecForFloat).SetInt64(0)
@@ -346,10 +529,26 @@ func CalcReward(db vmctxt_interface.VmDatabase, old *types.Registration, gid typ
 		var dayInfo *core.Detail
 		periodEndIndex, count := getPeriodIndex(startIndex, endIndex, indexPerDay, startDayIndex)
 		dayInfo, err = reader.VoteDetails(startIndex, periodEndIndex, old, db)
+		if err != nil {
+			return old.RewardIndex, old.RewardIndex, nil, periodTime, err
+		}
+		rewardOfDay := &RewardOfDay{
+			StartIndex:     startIndex,
+			EndIndex:       periodEndIndex,
+			PlanNum:        dayInfo.PlanNum,
+			ActualNum:      dayInfo.ActualNum,
+			Reward:         big.NewInt(0),
+			PeriodVoterMap: make(map[uint64]map[types.Address]*big.Int, len(dayInfo.PeriodM)),
+			rewardF:        new(big.Float).SetPrec(rewardPrecForFloat).SetInt64(0),
+		}
+		for index, periodInfo := range dayInfo.PeriodM {
+			rewardOfDay.PeriodVoterMap[index] = periodInfo.VoterMap
+		}
 		indexCount = indexCount - count
 		startIndex = startIndex + count
 
 		if dayInfo.ActualNum == 0 {
+			rewardList = append(rewardList, rewardOfDay)
 			continue
 		}
 
@@ -373,16 +572,22 @@ func CalcReward(db vmctxt_interface.VmDatabase, old *types.Registration, gid typ
 		tmp1.Mul(tmp1, tmp3)
 		tmp1.Add(tmp1, float1)
 		tmp1.Mul(tmp1, tmp2)
-		rewardF.Add(rewardF, tmp1)
+		rewardOfDay.rewardF.Set(tmp1)
+		rewardOfDay.Reward = rewardFloatToInt(tmp1)
+		rewardList = append(rewardList, rewardOfDay)
 
 		tmp3.SetUint64(0)
 	}
+	return old.RewardIndex, endIndex, rewardList, periodTime, nil
+}
+
+func rewardFloatToInt(rewardF *big.Float) *big.Int {
 	reward, _ := new(big.Int).SetString(rewardF.Text('f', 0), 10)
 	if reward.Sign() > 0 {
 		reward.Mul(reward, rewardPerBlock)
 		reward.Quo(reward, helper.Big2)
 	}
-	return old.RewardIndex, endIndex, reward, periodTime, nil
+	return reward
 }
 
 func getPeriodIndex(startIndex, endIndex, indexPerDay, startDayIndex uint64) (periodEndIndex, count uint64) {
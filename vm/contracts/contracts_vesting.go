@@ -0,0 +1,131 @@
+package contracts
+
+import (
+	"errors"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	cabi "github.com/vitelabs/go-vite/vm/contracts/abi"
+	"github.com/vitelabs/go-vite/vm/util"
+	"github.com/vitelabs/go-vite/vm_context/vmctxt_interface"
+	"math/big"
+)
+
+type MethodVestingLock struct{}
+
+func (p *MethodVestingLock) GetFee(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (p *MethodVestingLock) GetRefundData() []byte {
+	return []byte{1}
+}
+
+// lock the amount of the send block for a beneficial, released linearly after the cliff
+func (p *MethodVestingLock) DoSend(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, quotaLeft uint64) (uint64, error) {
+	quotaLeft, err := util.UseQuota(quotaLeft, VestingLockGas)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if block.Amount.Sign() <= 0 ||
+		!IsUserAccount(db, block.AccountAddress) {
+		return quotaLeft, errors.New("invalid block data")
+	}
+	param := new(cabi.ParamVestingLock)
+	if err = cabi.ABIVesting.UnpackMethod(param, cabi.MethodNameVestingLock, block.Data); err != nil {
+		return quotaLeft, util.ErrInvalidMethodParam
+	}
+	if param.VestingHeight == 0 || param.VestingHeight > vestingHeightMax ||
+		param.CliffHeight > param.VestingHeight {
+		return quotaLeft, errors.New("invalid vesting schedule")
+	}
+	return quotaLeft, nil
+}
+
+func (p *MethodVestingLock) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
+	param := new(cabi.ParamVestingLock)
+	cabi.ABIVesting.UnpackMethod(param, cabi.MethodNameVestingLock, sendBlock.Data)
+	key := cabi.GetVestingKey(param.Beneficial, sendBlock.Hash)
+	if len(db.GetStorage(&block.AccountAddress, key)) > 0 {
+		return nil, util.ErrIdCollision
+	}
+	startHeight := db.CurrentSnapshotBlock().Height
+	vestingInfo, _ := cabi.ABIVesting.PackVariable(
+		cabi.VariableNameVestingInfo,
+		sendBlock.AccountAddress,
+		sendBlock.TokenId,
+		sendBlock.Amount,
+		big.NewInt(0),
+		startHeight,
+		startHeight+param.CliffHeight,
+		startHeight+param.VestingHeight)
+	db.SetStorage(key, vestingInfo)
+	return nil, nil
+}
+
+type MethodVestingClaim struct{}
+
+func (p *MethodVestingClaim) GetFee(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (p *MethodVestingClaim) GetRefundData() []byte {
+	return []byte{2}
+}
+
+// claim the released amount of a vesting, only the beneficial can claim
+func (p *MethodVestingClaim) DoSend(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, quotaLeft uint64) (uint64, error) {
+	quotaLeft, err := util.UseQuota(quotaLeft, VestingClaimGas)
+	if err != nil {
+		return quotaLeft, err
+	}
+	if block.Amount.Sign() > 0 {
+		return quotaLeft, errors.New("invalid block data")
+	}
+	vestingId := new(types.Hash)
+	if err = cabi.ABIVesting.UnpackMethod(vestingId, cabi.MethodNameVestingClaim, block.Data); err != nil {
+		return quotaLeft, util.ErrInvalidMethodParam
+	}
+	return quotaLeft, nil
+}
+
+func (p *MethodVestingClaim) DoReceive(db vmctxt_interface.VmDatabase, block *ledger.AccountBlock, sendBlock *ledger.AccountBlock) ([]*SendBlock, error) {
+	vestingId := new(types.Hash)
+	cabi.ABIVesting.UnpackMethod(vestingId, cabi.MethodNameVestingClaim, sendBlock.Data)
+	key := cabi.GetVestingKey(sendBlock.AccountAddress, *vestingId)
+	vestingInfo := new(cabi.VestingInfo)
+	if err := cabi.ABIVesting.UnpackVariable(vestingInfo, cabi.VariableNameVestingInfo, db.GetStorage(&block.AccountAddress, key)); err != nil {
+		return nil, errors.New("vesting not exist")
+	}
+	if !isHeightReached(db, vestingInfo.CliffHeight) {
+		return nil, errors.New("vesting cliff not yet due")
+	}
+	claimable := vestingInfo.Claimable(db.CurrentSnapshotBlock().Height)
+	if claimable.Sign() <= 0 {
+		return nil, errors.New("nothing to claim")
+	}
+	vestingInfo.Claimed.Add(vestingInfo.Claimed, claimable)
+	if vestingInfo.Remaining().Sign() == 0 {
+		db.SetStorage(key, nil)
+	} else {
+		newVestingInfo, _ := cabi.ABIVesting.PackVariable(
+			cabi.VariableNameVestingInfo,
+			vestingInfo.Locker,
+			vestingInfo.TokenId,
+			vestingInfo.Amount,
+			vestingInfo.Claimed,
+			vestingInfo.StartHeight,
+			vestingInfo.CliffHeight,
+			vestingInfo.EndHeight)
+		db.SetStorage(key, newVestingInfo)
+	}
+	return []*SendBlock{
+		{
+			block,
+			sendBlock.AccountAddress,
+			ledger.BlockTypeSendCall,
+			claimable,
+			vestingInfo.TokenId,
+			[]byte{},
+		},
+	}, nil
+}
@@ -2,6 +2,7 @@ package contracts
 
 import (
 	"github.com/vitelabs/go-vite/vm/util"
+	"math"
 	"math/big"
 )
 
@@ -10,15 +11,28 @@ const (
 	UpdateRegistrationGas     uint64 = 62200
 	CancelRegisterGas         uint64 = 83200
 	RewardGas                 uint64 = 238800
+	WithdrawVoterRewardGas    uint64 = 21000
 	VoteGas                   uint64 = 62000
 	CancelVoteGas             uint64 = 62000
 	PledgeGas                 uint64 = 21000
 	CancelPledgeGas           uint64 = 21000
+	AgentPledgeGas            uint64 = 21000
+	CancelAgentPledgeGas      uint64 = 21000
 	CreateConsensusGroupGas   uint64 = 62200
 	CancelConsensusGroupGas   uint64 = 83200
 	ReCreateConsensusGroupGas uint64 = 62200
 	MintageGas                uint64 = 83200
 	MintageCancelPledgeGas    uint64 = 83200
+	MintageReIssueGas         uint64 = 83200
+	MintageBurnGas            uint64 = 62200
+	MintageTransferOwnerGas   uint64 = 62200
+	CreateMultisigGas         uint64 = 62200
+	MultisigDepositGas        uint64 = 21000
+	MultisigProposeGas        uint64 = 62200
+	MultisigApproveGas        uint64 = 62200
+	MultisigRevokeGas         uint64 = 62200
+	VestingLockGas            uint64 = 62200
+	VestingClaimGas           uint64 = 62200
 
 	cgNodeCountMin   uint8 = 3       // Minimum node count of consensus group
 	cgNodeCountMax   uint8 = 101     // Maximum node count of consensus group
@@ -29,13 +43,23 @@ const (
 	cgPerIntervalMin int64 = 1
 	cgPerIntervalMax int64 = 10 * 60
 
-	RewardDayLimit     uint64 = 90
-	rewardPrecForFloat uint   = 18
+	RewardDayLimit          uint64 = 90
+	rewardPrecForFloat      uint   = 18
+	rewardVoterSharePercent uint64 = 50 // Percent of the snapshot block reward shared with the voters of a registration
 
 	registrationNameLengthMax int = 40
 
 	tokenNameLengthMax   int = 40 // Maximum length of a token name(include)
 	tokenSymbolLengthMax int = 10 // Maximum length of a token symbol(include)
+
+	multisigOwnerCountMax int = 20 // Maximum owner count of a multisig wallet(include)
+
+	vestingHeightMax uint64 = 3600 * 24 * 365 * 10 // Maximum vesting period in snapshot blocks(include)
+
+	agentPledgeHeightMax      uint64 = 3600 * 24 * 365 // Maximum pledge period of an agent pledge in snapshot blocks(include)
+	agentPledgeCancelCountMax int    = 100             // Maximum count of agent pledges cancelled in a transaction(include)
+
+	forkHeightDisabled uint64 = math.MaxUint64 // Fork height of a feature not scheduled on the main net yet
 )
 
 var (
@@ -56,6 +80,11 @@ type ContractsParams struct {
 	MintagePledgeHeight              uint64 // Pledge height for mintage if choose to pledge instead of destroy vite token
 	RewardEndTimeLimit               uint64 // Cannot get snapshot block reward of current few blocks, for latest snapshot block could be reverted
 	RewardTimeUnit                   uint64
+	RewardForkHeight                 uint64 // Snapshot height since which the snapshot block reward can be withdrawn
+	MultisigForkHeight               uint64 // Snapshot height since which the multisig contract can be called
+	ReIssueForkHeight                uint64 // Snapshot height since which the tokens can be reissued, burned and transferred
+	VestingForkHeight                uint64 // Snapshot height since which the vesting contract can be called
+	AgentPledgeForkHeight            uint64 // Snapshot height since which the agent pledges can be made and cancelled
 }
 
 var (
@@ -65,6 +94,11 @@ var (
 		MintagePledgeHeight:              1,
 		RewardEndTimeLimit:               75,
 		RewardTimeUnit:                   75 * 2,
+		RewardForkHeight:                 1,
+		MultisigForkHeight:               1,
+		ReIssueForkHeight:                1,
+		VestingForkHeight:                1,
+		AgentPledgeForkHeight:            1,
 	}
 	ContractsParamsMainNet = ContractsParams{
 		MinPledgeHeight:                  3600 * 24 * 3,
@@ -72,5 +106,10 @@ var (
 		MintagePledgeHeight:              3600 * 24 * 30 * 3,
 		RewardEndTimeLimit:               3600 * 24,
 		RewardTimeUnit:                   1152 * 75,
+		RewardForkHeight:                 forkHeightDisabled,
+		MultisigForkHeight:               forkHeightDisabled,
+		ReIssueForkHeight:                forkHeightDisabled,
+		VestingForkHeight:                forkHeightDisabled,
+		AgentPledgeForkHeight:            forkHeightDisabled,
 	}
 )
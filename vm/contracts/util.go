@@ -11,6 +11,11 @@ func IsUserAccount(db vmctxt_interface.VmDatabase, addr types.Address) bool {
 	return len(db.GetContractCode(&addr)) == 0
 }
 
+// isHeightReached checks whether the current snapshot height reaches a lock height, such as the withdraw height of a pledge
+func isHeightReached(db vmctxt_interface.VmDatabase, height uint64) bool {
+	return height <= db.CurrentSnapshotBlock().Height
+}
+
 func IsExistGid(db vmctxt_interface.VmDatabase, gid types.Gid) bool {
 	value := db.GetStorage(&types.AddressConsensusGroup, abi.GetConsensusGroupKey(gid))
 	return len(value) > 0
@@ -33,3 +38,59 @@ func NewContractAddress(accountAddress types.Address, accountBlockHeight uint64,
 		prevBlockHash.Bytes(),
 		snapshotHash.Bytes())
 }
+
+// the snapshot block reward can only be withdrawn since the fork height
+func isRewardActivated(db vmctxt_interface.VmDatabase) bool {
+	return isHeightReached(db, nodeConfig.params.RewardForkHeight)
+}
+
+// the reissuable tokens can only be minted, reissued, burned and transferred since the fork height
+func isReIssueActivated(db vmctxt_interface.VmDatabase) bool {
+	return isHeightReached(db, nodeConfig.params.ReIssueForkHeight)
+}
+
+// the agent pledges can only be made and cancelled since the fork height
+func isAgentPledgeActivated(db vmctxt_interface.VmDatabase) bool {
+	return isHeightReached(db, nodeConfig.params.AgentPledgeForkHeight)
+}
+
+// IsContractActivated checks whether a precompiled contract can be called at the current snapshot height
+func IsContractActivated(db vmctxt_interface.VmDatabase, addr types.Address) bool {
+	return IsContractActivatedAt(addr, db.CurrentSnapshotBlock().Height)
+}
+
+// IsContractActivatedAt checks whether a precompiled contract can be called at the snapshot height
+func IsContractActivatedAt(addr types.Address, snapshotHeight uint64) bool {
+	switch addr {
+	case types.AddressMultisig:
+		return nodeConfig.params.MultisigForkHeight <= snapshotHeight
+	case types.AddressVesting:
+		return nodeConfig.params.VestingForkHeight <= snapshotHeight
+	}
+	return true
+}
+
+// IsPrecompiledContractAddress checks whether the address is a precompiled contract at the snapshot height,
+// the contracts activated at a fork height are ordinary accounts before it
+func IsPrecompiledContractAddress(addr types.Address, snapshotHeight uint64) bool {
+	if types.IsPrecompiledContractAddress(addr) {
+		return true
+	}
+	for _, cAddr := range types.ForkedContractAddressList {
+		if cAddr == addr {
+			return IsContractActivatedAt(addr, snapshotHeight)
+		}
+	}
+	return false
+}
+
+// PrecompiledContractAddressList returns the precompiled contracts at the snapshot height
+func PrecompiledContractAddressList(snapshotHeight uint64) []types.Address {
+	addrList := append([]types.Address{}, types.PrecompiledContractAddressList...)
+	for _, addr := range types.ForkedContractAddressList {
+		if IsContractActivatedAt(addr, snapshotHeight) {
+			addrList = append(addrList, addr)
+		}
+	}
+	return addrList
+}
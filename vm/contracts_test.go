@@ -1304,6 +1304,926 @@ func TestContractsMintage(t *testing.T) {
 	}
 }
 
+func TestContractsMintageReIssue(t *testing.T) {
+	// prepare db
+	viteTotalSupply := new(big.Int).Mul(big.NewInt(2e6), big.NewInt(1e18))
+	db, addr1, _, hash12, snapshot2, _ := prepareDb(viteTotalSupply)
+	blockTime := time.Now()
+	addr2 := types.AddressMintage
+	addr3, _, _ := types.CreateAddress()
+	db.accountBlockMap[addr2] = make(map[types.Hash]*ledger.AccountBlock)
+
+	// mintage reissuable token
+	totalSupply := big.NewInt(1e10)
+	maxSupply := big.NewInt(2e10)
+	block13Data, _ := abi.ABIMintage.PackMethod(abi.MethodNameMintageReIssuable, types.TokenTypeId{}, "test token", "t", totalSupply, uint8(3), maxSupply)
+	hash13 := types.DataHash([]byte{1, 3})
+	block13 := &ledger.AccountBlock{
+		Height:         3,
+		ToAddress:      addr2,
+		AccountAddress: addr1,
+		Amount:         big.NewInt(0),
+		TokenId:        ledger.ViteTokenId,
+		BlockType:      ledger.BlockTypeSendCall,
+		Fee:            big.NewInt(0),
+		PrevHash:       hash12,
+		Data:           block13Data,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm := NewVM()
+	vm.Debug = true
+	db.addr = addr1
+	if _, _, err := vm.Run(db, block13, nil); err == nil {
+		t.Fatalf("send mintage reissuable transaction before fork height should fail")
+	}
+	defer setForkHeights(func(params *contracts.ContractsParams) {
+		params.ReIssueForkHeight = 1
+	})()
+
+	vm = NewVM()
+	vm.Debug = true
+	sendMintageBlockList, isRetry, err := vm.Run(db, block13, nil)
+	if len(sendMintageBlockList) != 1 || isRetry || err != nil ||
+		sendMintageBlockList[0].AccountBlock.Quota != contracts.MintageGas {
+		t.Fatalf("send mintage reissuable transaction error")
+	}
+	db.accountBlockMap[addr1][hash13] = sendMintageBlockList[0].AccountBlock
+	tokenId, _ := types.BytesToTokenTypeId(sendMintageBlockList[0].AccountBlock.Data[26:36])
+
+	hash21 := types.DataHash([]byte{2, 1})
+	block21 := &ledger.AccountBlock{
+		Height:         1,
+		AccountAddress: addr2,
+		BlockType:      ledger.BlockTypeReceive,
+		FromBlockHash:  hash13,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr2
+	receiveMintageBlockList, isRetry, err := vm.Run(db, block21, sendMintageBlockList[0].AccountBlock)
+	if len(receiveMintageBlockList) != 2 || isRetry || err != nil ||
+		!bytes.Equal(db.storageMap[addr2][string(abi.GetReIssuableKey(tokenId))], helper.LeftPadBytes(maxSupply.Bytes(), helper.WordSize)) {
+		t.Fatalf("receive mintage reissuable transaction error")
+	}
+	db.accountBlockMap[addr2][hash21] = receiveMintageBlockList[0].AccountBlock
+	if tokenInfo := abi.GetTokenById(db, tokenId); tokenInfo == nil || !tokenInfo.IsReIssuable || tokenInfo.MaxSupply.Cmp(maxSupply) != 0 {
+		t.Fatalf("get reissuable token by id failed")
+	}
+	if tokenInfo := abi.GetTokenById(db, ledger.ViteTokenId); tokenInfo == nil || tokenInfo.IsReIssuable || tokenInfo.MaxSupply.Cmp(viteTotalSupply) != 0 {
+		t.Fatalf("get fixed supply token by id failed")
+	}
+	if tokenMap := abi.GetTokenMap(db); len(tokenMap) != 2 {
+		t.Fatalf("get token map failed")
+	}
+
+	// reissue to addr3
+	reIssueAmount := big.NewInt(1e9)
+	block14Data, _ := abi.ABIMintage.PackMethod(abi.MethodNameMintageReIssue, tokenId, reIssueAmount, addr3)
+	hash14 := types.DataHash([]byte{1, 4})
+	block14 := &ledger.AccountBlock{
+		Height:         4,
+		ToAddress:      addr2,
+		AccountAddress: addr1,
+		Amount:         big.NewInt(0),
+		TokenId:        ledger.ViteTokenId,
+		BlockType:      ledger.BlockTypeSendCall,
+		Fee:            big.NewInt(0),
+		PrevHash:       hash13,
+		Data:           block14Data,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr1
+	sendReIssueBlockList, isRetry, err := vm.Run(db, block14, nil)
+	if len(sendReIssueBlockList) != 1 || isRetry || err != nil ||
+		sendReIssueBlockList[0].AccountBlock.Quota != contracts.MintageReIssueGas {
+		t.Fatalf("send reissue transaction error")
+	}
+	db.accountBlockMap[addr1][hash14] = sendReIssueBlockList[0].AccountBlock
+
+	hash22 := types.DataHash([]byte{2, 2})
+	block22 := &ledger.AccountBlock{
+		Height:         2,
+		AccountAddress: addr2,
+		BlockType:      ledger.BlockTypeReceive,
+		PrevHash:       hash21,
+		FromBlockHash:  hash14,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr2
+	receiveReIssueBlockList, isRetry, err := vm.Run(db, block22, sendReIssueBlockList[0].AccountBlock)
+	newTotalSupply := new(big.Int).Add(totalSupply, reIssueAmount)
+	if len(receiveReIssueBlockList) != 2 || isRetry || err != nil ||
+		receiveReIssueBlockList[1].AccountBlock.BlockType != ledger.BlockTypeSendReward ||
+		receiveReIssueBlockList[1].AccountBlock.ToAddress != addr3 ||
+		receiveReIssueBlockList[1].AccountBlock.Amount.Cmp(reIssueAmount) != 0 ||
+		abi.GetTokenById(db, tokenId).TotalSupply.Cmp(newTotalSupply) != 0 ||
+		len(db.logList) != 1 || db.logList[0].Topics[0] != abi.ABIMintage.Events[abi.EventNameMintageReIssue].Id() {
+		t.Fatalf("receive reissue transaction error")
+	}
+	db.accountBlockMap[addr2][hash22] = receiveReIssueBlockList[0].AccountBlock
+
+	// reissue over max supply fails
+	block15Data, _ := abi.ABIMintage.PackMethod(abi.MethodNameMintageReIssue, tokenId, maxSupply, addr3)
+	block15 := &ledger.AccountBlock{
+		Height:         5,
+		ToAddress:      addr2,
+		AccountAddress: addr1,
+		Amount:         big.NewInt(0),
+		TokenId:        ledger.ViteTokenId,
+		BlockType:      ledger.BlockTypeSendCall,
+		Fee:            big.NewInt(0),
+		PrevHash:       hash14,
+		Data:           block15Data,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	block23 := &ledger.AccountBlock{
+		Height:         3,
+		AccountAddress: addr2,
+		BlockType:      ledger.BlockTypeReceive,
+		PrevHash:       hash22,
+		FromBlockHash:  types.DataHash([]byte{1, 5}),
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr2
+	receiveReIssueBlockList2, _, err := vm.Run(db, block23, block15)
+	if len(receiveReIssueBlockList2) != 1 || err == nil ||
+		abi.GetTokenById(db, tokenId).TotalSupply.Cmp(newTotalSupply) != 0 {
+		t.Fatalf("receive reissue over max supply transaction error")
+	}
+
+	// burn the token sent by the owner
+	burnAmount := big.NewInt(1e8)
+	db.balanceMap[addr1][tokenId] = new(big.Int).Set(totalSupply)
+	block16Data, _ := abi.ABIMintage.PackMethod(abi.MethodNameMintageBurn)
+	block16 := &ledger.AccountBlock{
+		Height:         5,
+		ToAddress:      addr2,
+		AccountAddress: addr1,
+		Amount:         burnAmount,
+		TokenId:        tokenId,
+		BlockType:      ledger.BlockTypeSendCall,
+		Fee:            big.NewInt(0),
+		PrevHash:       hash14,
+		Data:           block16Data,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr1
+	sendBurnBlockList, isRetry, err := vm.Run(db, block16, nil)
+	if len(sendBurnBlockList) != 1 || isRetry || err != nil ||
+		sendBurnBlockList[0].AccountBlock.Quota != contracts.MintageBurnGas {
+		t.Fatalf("send burn transaction error")
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr2
+	block23.FromBlockHash = types.DataHash([]byte{1, 6})
+	receiveBurnBlockList, isRetry, err := vm.Run(db, block23, sendBurnBlockList[0].AccountBlock)
+	newTotalSupply.Sub(newTotalSupply, burnAmount)
+	if len(receiveBurnBlockList) != 1 || isRetry || err != nil ||
+		abi.GetTokenById(db, tokenId).TotalSupply.Cmp(newTotalSupply) != 0 ||
+		db.balanceMap[addr2][tokenId].Sign() != 0 ||
+		len(db.logList) != 2 {
+		t.Fatalf("receive burn transaction error")
+	}
+
+	// transfer owner
+	block17Data, _ := abi.ABIMintage.PackMethod(abi.MethodNameMintageTransferOwner, tokenId, addr3)
+	block17 := &ledger.AccountBlock{
+		Height:         6,
+		ToAddress:      addr2,
+		AccountAddress: addr1,
+		Amount:         big.NewInt(0),
+		TokenId:        ledger.ViteTokenId,
+		BlockType:      ledger.BlockTypeSendCall,
+		Fee:            big.NewInt(0),
+		Data:           block17Data,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr1
+	sendTransferOwnerBlockList, isRetry, err := vm.Run(db, block17, nil)
+	if len(sendTransferOwnerBlockList) != 1 || isRetry || err != nil {
+		t.Fatalf("send transfer owner transaction error")
+	}
+	block24 := &ledger.AccountBlock{
+		Height:         4,
+		AccountAddress: addr2,
+		BlockType:      ledger.BlockTypeReceive,
+		FromBlockHash:  types.DataHash([]byte{1, 7}),
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr2
+	receiveTransferOwnerBlockList, isRetry, err := vm.Run(db, block24, sendTransferOwnerBlockList[0].AccountBlock)
+	if len(receiveTransferOwnerBlockList) != 1 || isRetry || err != nil ||
+		abi.GetTokenById(db, tokenId).Owner != addr3 ||
+		len(db.logList) != 3 {
+		t.Fatalf("receive transfer owner transaction error")
+	}
+}
+
+func TestContractsMultisig(t *testing.T) {
+	// prepare db
+	viteTotalSupply := new(big.Int).Mul(big.NewInt(2e6), big.NewInt(1e18))
+	db, addr1, _, hash12, snapshot2, _ := prepareDb(viteTotalSupply)
+	blockTime := time.Now()
+	addr2, _, _ := types.CreateAddress()
+	db.accountBlockMap[addr2] = make(map[types.Hash]*ledger.AccountBlock)
+	db.storageMap[types.AddressPledge][string(abi.GetPledgeBeneficialKey(addr2))], _ = abi.ABIPledge.PackVariable(abi.VariableNamePledgeBeneficial, new(big.Int).Mul(big.NewInt(1e9), big.NewInt(1e18)))
+	addr3, _, _ := types.CreateAddress()
+	addr4 := types.AddressMultisig
+	db.accountBlockMap[addr4] = make(map[types.Hash]*ledger.AccountBlock)
+
+	// create 2-of-2 multisig wallet with deposit
+	balance1 := new(big.Int).Set(viteTotalSupply)
+	depositAmount := new(big.Int).Mul(big.NewInt(100), util.AttovPerVite)
+	block13Data, _ := abi.ABIMultisig.PackMethod(abi.MethodNameCreateMultisig, []types.Address{addr1, addr2}, uint8(2))
+	hash13 := types.DataHash([]byte{1, 3})
+	block13 := &ledger.AccountBlock{
+		Height:         3,
+		ToAddress:      addr4,
+		AccountAddress: addr1,
+		Amount:         depositAmount,
+		TokenId:        ledger.ViteTokenId,
+		BlockType:      ledger.BlockTypeSendCall,
+		Fee:            big.NewInt(0),
+		PrevHash:       hash12,
+		Data:           block13Data,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm := NewVM()
+	vm.Debug = true
+	db.addr = addr1
+	if sendBlockList, _, err := vm.Run(db, block13, nil); len(sendBlockList) != 1 || err != nil ||
+		sendBlockList[0].AccountBlock.Quota == contracts.CreateMultisigGas {
+		t.Fatalf("send to multisig contract before fork height should be a transfer")
+	}
+	db.AddBalance(&ledger.ViteTokenId, depositAmount)
+	defer setForkHeights(func(params *contracts.ContractsParams) {
+		params.MultisigForkHeight = 1
+	})()
+
+	vm = NewVM()
+	vm.Debug = true
+	sendCreateBlockList, isRetry, err := vm.Run(db, block13, nil)
+	balance1.Sub(balance1, depositAmount)
+	if len(sendCreateBlockList) != 1 || isRetry || err != nil ||
+		db.balanceMap[addr1][ledger.ViteTokenId].Cmp(balance1) != 0 ||
+		sendCreateBlockList[0].AccountBlock.Quota != contracts.CreateMultisigGas {
+		t.Fatalf("send create multisig transaction error")
+	}
+	sendCreateBlockList[0].AccountBlock.Hash = hash13
+	db.accountBlockMap[addr1][hash13] = sendCreateBlockList[0].AccountBlock
+
+	hash41 := types.DataHash([]byte{4, 1})
+	block41 := &ledger.AccountBlock{
+		Height:         1,
+		AccountAddress: addr4,
+		BlockType:      ledger.BlockTypeReceive,
+		FromBlockHash:  hash13,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr4
+	receiveCreateBlockList, isRetry, err := vm.Run(db, block41, sendCreateBlockList[0].AccountBlock)
+	walletId := hash13
+	walletData, _ := abi.ABIMultisig.PackVariable(abi.VariableNameMultisigWallet, []types.Address{addr1, addr2}, uint8(2))
+	if len(receiveCreateBlockList) != 1 || isRetry || err != nil ||
+		!bytes.Equal(db.storageMap[addr4][string(abi.GetMultisigWalletKey(walletId))], walletData) ||
+		!bytes.Equal(db.storageMap[addr4][string(abi.GetMultisigBalanceKey(walletId, ledger.ViteTokenId))], helper.LeftPadBytes(depositAmount.Bytes(), helper.WordSize)) ||
+		db.balanceMap[addr4][ledger.ViteTokenId].Cmp(depositAmount) != 0 {
+		t.Fatalf("receive create multisig transaction error")
+	}
+	db.accountBlockMap[addr4][hash41] = receiveCreateBlockList[0].AccountBlock
+
+	// propose a transfer to addr3
+	transferAmount := new(big.Int).Mul(big.NewInt(30), util.AttovPerVite)
+	block14Data, _ := abi.ABIMultisig.PackMethod(abi.MethodNameMultisigPropose, walletId, addr3, ledger.ViteTokenId, transferAmount)
+	hash14 := types.DataHash([]byte{1, 4})
+	block14 := &ledger.AccountBlock{
+		Height:         4,
+		ToAddress:      addr4,
+		AccountAddress: addr1,
+		Amount:         big.NewInt(0),
+		TokenId:        ledger.ViteTokenId,
+		BlockType:      ledger.BlockTypeSendCall,
+		Fee:            big.NewInt(0),
+		PrevHash:       hash13,
+		Data:           block14Data,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr1
+	sendProposeBlockList, isRetry, err := vm.Run(db, block14, nil)
+	if len(sendProposeBlockList) != 1 || isRetry || err != nil ||
+		sendProposeBlockList[0].AccountBlock.Quota != contracts.MultisigProposeGas {
+		t.Fatalf("send propose transaction error")
+	}
+	sendProposeBlockList[0].AccountBlock.Hash = hash14
+	db.accountBlockMap[addr1][hash14] = sendProposeBlockList[0].AccountBlock
+
+	hash42 := types.DataHash([]byte{4, 2})
+	block42 := &ledger.AccountBlock{
+		Height:         2,
+		AccountAddress: addr4,
+		BlockType:      ledger.BlockTypeReceive,
+		PrevHash:       hash41,
+		FromBlockHash:  hash14,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr4
+	receiveProposeBlockList, isRetry, err := vm.Run(db, block42, sendProposeBlockList[0].AccountBlock)
+	proposalId := hash14
+	if len(receiveProposeBlockList) != 1 || isRetry || err != nil {
+		t.Fatalf("receive propose transaction error")
+	}
+	db.accountBlockMap[addr4][hash42] = receiveProposeBlockList[0].AccountBlock
+	if proposalList := abi.GetMultisigProposalList(db, walletId); len(proposalList) != 1 ||
+		proposalList[0].ProposalId != proposalId || len(proposalList[0].Approvers) != 1 ||
+		proposalList[0].Amount.Cmp(transferAmount) != 0 {
+		t.Fatalf("get multisig proposal list failed")
+	}
+
+	// approve by addr2, the transfer is sent
+	block21Data, _ := abi.ABIMultisig.PackMethod(abi.MethodNameMultisigApprove, walletId, proposalId)
+	hash21 := types.DataHash([]byte{2, 1})
+	block21 := &ledger.AccountBlock{
+		Height:         1,
+		ToAddress:      addr4,
+		AccountAddress: addr2,
+		Amount:         big.NewInt(0),
+		TokenId:        ledger.ViteTokenId,
+		BlockType:      ledger.BlockTypeSendCall,
+		Fee:            big.NewInt(0),
+		Data:           block21Data,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr2
+	sendApproveBlockList, isRetry, err := vm.Run(db, block21, nil)
+	if len(sendApproveBlockList) != 1 || isRetry || err != nil ||
+		sendApproveBlockList[0].AccountBlock.Quota != contracts.MultisigApproveGas {
+		t.Fatalf("send approve transaction error")
+	}
+	sendApproveBlockList[0].AccountBlock.Hash = hash21
+	db.accountBlockMap[addr2][hash21] = sendApproveBlockList[0].AccountBlock
+
+	block43 := &ledger.AccountBlock{
+		Height:         3,
+		AccountAddress: addr4,
+		BlockType:      ledger.BlockTypeReceive,
+		PrevHash:       hash42,
+		FromBlockHash:  hash21,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr4
+	receiveApproveBlockList, isRetry, err := vm.Run(db, block43, sendApproveBlockList[0].AccountBlock)
+	leftAmount := new(big.Int).Sub(depositAmount, transferAmount)
+	if len(receiveApproveBlockList) != 2 || isRetry || err != nil ||
+		receiveApproveBlockList[1].AccountBlock.ToAddress != addr3 ||
+		receiveApproveBlockList[1].AccountBlock.Amount.Cmp(transferAmount) != 0 ||
+		db.balanceMap[addr4][ledger.ViteTokenId].Cmp(leftAmount) != 0 ||
+		len(db.storageMap[addr4][string(abi.GetMultisigProposalKey(walletId, proposalId))]) != 0 {
+		t.Fatalf("receive approve transaction error")
+	}
+
+	// get contracts data
+	if wallet := abi.GetMultisigWallet(db, walletId); wallet == nil || !wallet.IsOwner(addr2) || wallet.IsOwner(addr3) {
+		t.Fatalf("get multisig wallet failed")
+	}
+	if balances := abi.GetMultisigBalances(db, walletId); len(balances) != 1 || balances[ledger.ViteTokenId].Cmp(leftAmount) != 0 {
+		t.Fatalf("get multisig balances failed")
+	}
+	if proposalList := abi.GetMultisigProposalList(db, walletId); len(proposalList) != 0 {
+		t.Fatalf("executed proposal should be removed")
+	}
+
+	// a transfer made before the fork height is refunded
+	hash43 := types.DataHash([]byte{4, 3})
+	receiveApproveBlockList[0].AccountBlock.Hash = hash43
+	db.accountBlockMap[addr4][hash43] = receiveApproveBlockList[0].AccountBlock
+	hash15 := types.DataHash([]byte{1, 5})
+	block15 := &ledger.AccountBlock{
+		Height:         5,
+		Hash:           hash15,
+		ToAddress:      addr4,
+		AccountAddress: addr1,
+		Amount:         transferAmount,
+		TokenId:        ledger.ViteTokenId,
+		BlockType:      ledger.BlockTypeSendCall,
+		Fee:            big.NewInt(0),
+		PrevHash:       hash14,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	db.accountBlockMap[addr1][hash15] = block15
+	block44 := &ledger.AccountBlock{
+		Height:         4,
+		AccountAddress: addr4,
+		BlockType:      ledger.BlockTypeReceive,
+		PrevHash:       hash43,
+		FromBlockHash:  hash15,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr4
+	receiveTransferBlockList, isRetry, err := vm.Run(db, block44, block15)
+	if len(receiveTransferBlockList) != 2 || isRetry || err != util.ErrAbiMethodNotFound ||
+		receiveTransferBlockList[1].AccountBlock.ToAddress != addr1 ||
+		receiveTransferBlockList[1].AccountBlock.Amount.Cmp(transferAmount) != 0 ||
+		len(receiveTransferBlockList[1].AccountBlock.Data) != 0 {
+		t.Fatalf("receive transfer made before fork height error")
+	}
+}
+
+func TestContractsVesting(t *testing.T) {
+	// prepare db
+	viteTotalSupply := new(big.Int).Mul(big.NewInt(2e6), big.NewInt(1e18))
+	db, addr1, _, hash12, snapshot2, timestamp := prepareDb(viteTotalSupply)
+	blockTime := time.Now()
+	addr2, _, _ := types.CreateAddress()
+	db.accountBlockMap[addr2] = make(map[types.Hash]*ledger.AccountBlock)
+	db.storageMap[types.AddressPledge][string(abi.GetPledgeBeneficialKey(addr2))], _ = abi.ABIPledge.PackVariable(abi.VariableNamePledgeBeneficial, new(big.Int).Mul(big.NewInt(1e9), big.NewInt(1e18)))
+	addr3 := types.AddressVesting
+	db.accountBlockMap[addr3] = make(map[types.Hash]*ledger.AccountBlock)
+
+	// lock for addr2, cliff after 10 snapshot blocks, released in 100 snapshot blocks
+	lockAmount := new(big.Int).Mul(big.NewInt(100), util.AttovPerVite)
+	block13Data, _ := abi.ABIVesting.PackMethod(abi.MethodNameVestingLock, addr2, uint64(10), uint64(100))
+	hash13 := types.DataHash([]byte{1, 3})
+	block13 := &ledger.AccountBlock{
+		Height:         3,
+		ToAddress:      addr3,
+		AccountAddress: addr1,
+		Amount:         lockAmount,
+		TokenId:        ledger.ViteTokenId,
+		BlockType:      ledger.BlockTypeSendCall,
+		Fee:            big.NewInt(0),
+		PrevHash:       hash12,
+		Data:           block13Data,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm := NewVM()
+	vm.Debug = true
+	db.addr = addr1
+	if sendBlockList, _, err := vm.Run(db, block13, nil); len(sendBlockList) != 1 || err != nil ||
+		sendBlockList[0].AccountBlock.Quota == contracts.VestingLockGas {
+		t.Fatalf("send to vesting contract before fork height should be a transfer")
+	}
+	db.AddBalance(&ledger.ViteTokenId, lockAmount)
+	defer setForkHeights(func(params *contracts.ContractsParams) {
+		params.VestingForkHeight = 1
+	})()
+
+	vm = NewVM()
+	vm.Debug = true
+	sendLockBlockList, isRetry, err := vm.Run(db, block13, nil)
+	if len(sendLockBlockList) != 1 || isRetry || err != nil ||
+		sendLockBlockList[0].AccountBlock.Quota != contracts.VestingLockGas {
+		t.Fatalf("send vesting lock transaction error")
+	}
+	sendLockBlockList[0].AccountBlock.Hash = hash13
+	db.accountBlockMap[addr1][hash13] = sendLockBlockList[0].AccountBlock
+
+	hash31 := types.DataHash([]byte{3, 1})
+	block31 := &ledger.AccountBlock{
+		Height:         1,
+		AccountAddress: addr3,
+		BlockType:      ledger.BlockTypeReceive,
+		FromBlockHash:  hash13,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr3
+	receiveLockBlockList, isRetry, err := vm.Run(db, block31, sendLockBlockList[0].AccountBlock)
+	vestingId := hash13
+	vestingData, _ := abi.ABIVesting.PackVariable(abi.VariableNameVestingInfo, addr1, ledger.ViteTokenId, lockAmount, big.NewInt(0), snapshot2.Height, snapshot2.Height+10, snapshot2.Height+100)
+	if len(receiveLockBlockList) != 1 || isRetry || err != nil ||
+		!bytes.Equal(db.storageMap[addr3][string(abi.GetVestingKey(addr2, vestingId))], vestingData) ||
+		db.balanceMap[addr3][ledger.ViteTokenId].Cmp(lockAmount) != 0 {
+		t.Fatalf("receive vesting lock transaction error")
+	}
+	db.accountBlockMap[addr3][hash31] = receiveLockBlockList[0].AccountBlock
+
+	claim := func(height uint64) ([]*vm_context.VmAccountBlock, error) {
+		block21Data, _ := abi.ABIVesting.PackMethod(abi.MethodNameVestingClaim, vestingId)
+		block21 := &ledger.AccountBlock{
+			Height:         height,
+			ToAddress:      addr3,
+			AccountAddress: addr2,
+			Amount:         big.NewInt(0),
+			TokenId:        ledger.ViteTokenId,
+			BlockType:      ledger.BlockTypeSendCall,
+			Fee:            big.NewInt(0),
+			Data:           block21Data,
+			SnapshotHash:   snapshot2.Hash,
+			Timestamp:      &blockTime,
+		}
+		vm := NewVM()
+		vm.Debug = true
+		db.addr = addr2
+		sendClaimBlockList, isRetry, err := vm.Run(db, block21, nil)
+		if len(sendClaimBlockList) != 1 || isRetry || err != nil ||
+			sendClaimBlockList[0].AccountBlock.Quota != contracts.VestingClaimGas {
+			t.Fatalf("send vesting claim transaction error")
+		}
+		block32 := &ledger.AccountBlock{
+			Height:         height + 1,
+			AccountAddress: addr3,
+			BlockType:      ledger.BlockTypeReceive,
+			FromBlockHash:  types.DataHash([]byte{2, byte(height)}),
+			SnapshotHash:   snapshot2.Hash,
+			Timestamp:      &blockTime,
+		}
+		vm = NewVM()
+		vm.Debug = true
+		db.addr = addr3
+		receiveClaimBlockList, _, err := vm.Run(db, block32, sendClaimBlockList[0].AccountBlock)
+		return receiveClaimBlockList, err
+	}
+	addSnapshotBlocks := func(count uint64) {
+		for i := uint64(1); i <= count; i++ {
+			height := db.CurrentSnapshotBlock().Height + 1
+			timei := time.Unix(timestamp+int64(height), 0)
+			db.snapshotBlockList = append(db.snapshotBlockList, &ledger.SnapshotBlock{Height: height, Timestamp: &timei, Hash: types.DataHash([]byte{10, byte(height)})})
+		}
+	}
+
+	// claim before cliff
+	addSnapshotBlocks(9)
+	if _, err := claim(1); err == nil {
+		t.Fatalf("claim before cliff should fail")
+	}
+
+	// claim half after half the vesting period
+	addSnapshotBlocks(41)
+	receiveClaimBlockList, err := claim(1)
+	half := new(big.Int).Div(lockAmount, big.NewInt(2))
+	if len(receiveClaimBlockList) != 2 || err != nil ||
+		receiveClaimBlockList[1].AccountBlock.ToAddress != addr2 ||
+		receiveClaimBlockList[1].AccountBlock.Amount.Cmp(half) != 0 {
+		t.Fatalf("receive vesting claim transaction error")
+	}
+	db.addr = addr3
+	if info := abi.GetVestingInfo(db, addr2, vestingId); info == nil ||
+		info.Remaining().Cmp(half) != 0 || info.Claimable(db.CurrentSnapshotBlock().Height).Sign() != 0 {
+		t.Fatalf("get vesting info failed")
+	}
+
+	// claim the rest after the end
+	addSnapshotBlocks(60)
+	receiveClaimBlockList, err = claim(2)
+	if len(receiveClaimBlockList) != 2 || err != nil ||
+		receiveClaimBlockList[1].AccountBlock.Amount.Cmp(half) != 0 ||
+		len(db.storageMap[addr3][string(abi.GetVestingKey(addr2, vestingId))]) != 0 {
+		t.Fatalf("receive vesting claim rest transaction error")
+	}
+	db.addr = addr3
+	if list := abi.GetVestingInfoList(db, addr2); len(list) != 0 {
+		t.Fatalf("claimed vesting should be removed")
+	}
+}
+
+func TestContractsAgentPledge(t *testing.T) {
+	// prepare db
+	viteTotalSupply := new(big.Int).Mul(big.NewInt(2e6), big.NewInt(1e18))
+	db, addr1, _, hash12, snapshot2, timestamp := prepareDb(viteTotalSupply)
+	blockTime := time.Now()
+	addr2, _, _ := types.CreateAddress()
+	addr3 := types.AddressPledge
+	addr4, _, _ := types.CreateAddress()
+
+	// agent pledge for addr2, expires after 3 days
+	pledgeAmount := new(big.Int).Mul(big.NewInt(10), util.AttovPerVite)
+	pledgeHeight := uint64(3600 * 24 * 3)
+	block13Data, _ := abi.ABIPledge.PackMethod(abi.MethodNameAgentPledge, addr2, pledgeHeight)
+	hash13 := types.DataHash([]byte{1, 3})
+	block13 := &ledger.AccountBlock{
+		Height:         3,
+		ToAddress:      addr3,
+		AccountAddress: addr1,
+		Amount:         pledgeAmount,
+		TokenId:        ledger.ViteTokenId,
+		BlockType:      ledger.BlockTypeSendCall,
+		Fee:            big.NewInt(0),
+		PrevHash:       hash12,
+		Data:           block13Data,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm := NewVM()
+	vm.Debug = true
+	db.addr = addr1
+	if _, _, err := vm.Run(db, block13, nil); err == nil {
+		t.Fatalf("send agent pledge transaction before fork height should fail")
+	}
+	defer setForkHeights(func(params *contracts.ContractsParams) {
+		params.AgentPledgeForkHeight = 1
+	})()
+
+	vm = NewVM()
+	vm.Debug = true
+	sendPledgeBlockList, isRetry, err := vm.Run(db, block13, nil)
+	if len(sendPledgeBlockList) != 1 || isRetry || err != nil ||
+		sendPledgeBlockList[0].AccountBlock.Quota != contracts.AgentPledgeGas {
+		t.Fatalf("send agent pledge transaction error")
+	}
+	sendPledgeBlockList[0].AccountBlock.Hash = hash13
+	db.accountBlockMap[addr1][hash13] = sendPledgeBlockList[0].AccountBlock
+
+	hash31 := types.DataHash([]byte{3, 1})
+	block31 := &ledger.AccountBlock{
+		Height:         1,
+		AccountAddress: addr3,
+		BlockType:      ledger.BlockTypeReceive,
+		FromBlockHash:  hash13,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm = NewVM()
+	vm.Debug = true
+	db.addr = addr3
+	receivePledgeBlockList, isRetry, err := vm.Run(db, block31, sendPledgeBlockList[0].AccountBlock)
+	pledgeData, _ := abi.ABIPledge.PackVariable(abi.VariableNamePledgeInfo, pledgeAmount, snapshot2.Height+pledgeHeight)
+	beneficialData, _ := abi.ABIPledge.PackVariable(abi.VariableNamePledgeBeneficial, pledgeAmount)
+	if len(receivePledgeBlockList) != 1 || isRetry || err != nil ||
+		!bytes.Equal(db.storageMap[addr3][string(abi.GetAgentPledgeKey(addr1, addr2))], pledgeData) ||
+		!bytes.Equal(db.storageMap[addr3][string(abi.GetPledgeBeneficialKey(addr2))], beneficialData) ||
+		len(db.storageMap[addr3][string(abi.GetPledgeKey(addr1, abi.GetPledgeBeneficialKey(addr2)))]) != 0 {
+		t.Fatalf("receive agent pledge transaction error")
+	}
+	db.accountBlockMap[addr3] = make(map[types.Hash]*ledger.AccountBlock)
+	db.accountBlockMap[addr3][hash31] = receivePledgeBlockList[0].AccountBlock
+
+	if list, amount := abi.GetAgentPledgeInfoList(db, addr1); len(list) != 1 ||
+		list[0].BeneficialAddr != addr2 || amount.Cmp(pledgeAmount) != 0 {
+		t.Fatalf("get agent pledge list failed")
+	}
+	if list, _ := abi.GetPledgeInfoList(db, addr1); len(list) != 0 {
+		t.Fatalf("agent pledges should not be listed as pledges of the agent")
+	}
+
+	cancel := func(height uint64, beneficialList []types.Address) ([]*vm_context.VmAccountBlock, error) {
+		block14Data, _ := abi.ABIPledge.PackMethod(abi.MethodNameCancelAgentPledge, beneficialList)
+		block14 := &ledger.AccountBlock{
+			Height:         height,
+			ToAddress:      addr3,
+			AccountAddress: addr1,
+			Amount:         big.NewInt(0),
+			TokenId:        ledger.ViteTokenId,
+			BlockType:      ledger.BlockTypeSendCall,
+			Fee:            big.NewInt(0),
+			Data:           block14Data,
+			SnapshotHash:   snapshot2.Hash,
+			Timestamp:      &blockTime,
+		}
+		vm := NewVM()
+		vm.Debug = true
+		db.addr = addr1
+		sendCancelBlockList, isRetry, err := vm.Run(db, block14, nil)
+		if len(sendCancelBlockList) != 1 || isRetry || err != nil ||
+			sendCancelBlockList[0].AccountBlock.Quota != contracts.CancelAgentPledgeGas*uint64(len(beneficialList)) {
+			t.Fatalf("send cancel agent pledge transaction error")
+		}
+		block32 := &ledger.AccountBlock{
+			Height:         height,
+			AccountAddress: addr3,
+			BlockType:      ledger.BlockTypeReceive,
+			FromBlockHash:  types.DataHash([]byte{1, byte(height)}),
+			SnapshotHash:   snapshot2.Hash,
+			Timestamp:      &blockTime,
+		}
+		vm = NewVM()
+		vm.Debug = true
+		db.addr = addr3
+		receiveCancelBlockList, _, err := vm.Run(db, block32, sendCancelBlockList[0].AccountBlock)
+		return receiveCancelBlockList, err
+	}
+	addSnapshotBlock := func(height uint64) {
+		timei := time.Unix(timestamp+int64(height), 0)
+		db.snapshotBlockList = append(db.snapshotBlockList, &ledger.SnapshotBlock{Height: height, Timestamp: &timei, Hash: types.DataHash([]byte{10, byte(height)})})
+	}
+
+	// cancel before expiry
+	addSnapshotBlock(snapshot2.Height + pledgeHeight - 1)
+	if _, err := cancel(4, []types.Address{addr2}); err == nil {
+		t.Fatalf("cancel agent pledge before expiry should fail")
+	}
+
+	// cancel a beneficial without agent pledge
+	addSnapshotBlock(snapshot2.Height + pledgeHeight)
+	if _, err := cancel(4, []types.Address{addr2, addr4}); err == nil ||
+		!bytes.Equal(db.storageMap[addr3][string(abi.GetAgentPledgeKey(addr1, addr2))], pledgeData) {
+		t.Fatalf("cancel agent pledge of unknown beneficial should fail")
+	}
+
+	// cancel after expiry
+	receiveCancelBlockList, err := cancel(4, []types.Address{addr2})
+	if len(receiveCancelBlockList) != 2 || err != nil ||
+		receiveCancelBlockList[1].AccountBlock.ToAddress != addr1 ||
+		receiveCancelBlockList[1].AccountBlock.Amount.Cmp(pledgeAmount) != 0 ||
+		len(db.storageMap[addr3][string(abi.GetAgentPledgeKey(addr1, addr2))]) != 0 ||
+		len(db.storageMap[addr3][string(abi.GetPledgeBeneficialKey(addr2))]) != 0 {
+		t.Fatalf("receive cancel agent pledge transaction error")
+	}
+}
+
+func TestContractsVoterReward(t *testing.T) {
+	// prepare db
+	viteTotalSupply := new(big.Int).Mul(big.NewInt(2e6), big.NewInt(1e18))
+	db, addr1, _, hash12, snapshot2, timestamp := prepareDb(viteTotalSupply)
+	blockTime := time.Now()
+	addr2, _, _ := types.CreateAddress()
+	addr3, _, _ := types.CreateAddress()
+	addr4, _, _ := types.CreateAddress()
+	addr5 := types.AddressRegister
+	db.accountBlockMap[addr5] = make(map[types.Hash]*ledger.AccountBlock)
+	db.storageMap[addr5] = make(map[string][]byte)
+	nodeName := "s1"
+
+	// reward before fork height
+	block13Data, _ := abi.ABIRegister.PackMethod(abi.MethodNameReward, types.SNAPSHOT_GID, nodeName, addr1)
+	block13 := &ledger.AccountBlock{
+		Height:         3,
+		ToAddress:      addr5,
+		AccountAddress: addr1,
+		Amount:         big.NewInt(0),
+		TokenId:        ledger.ViteTokenId,
+		BlockType:      ledger.BlockTypeSendCall,
+		Fee:            big.NewInt(0),
+		PrevHash:       hash12,
+		Data:           block13Data,
+		SnapshotHash:   snapshot2.Hash,
+		Timestamp:      &blockTime,
+	}
+	vm := NewVM()
+	vm.Debug = true
+	db.addr = addr1
+	if _, _, err := vm.Run(db, block13, nil); err == nil {
+		t.Fatalf("send reward transaction before fork height should fail")
+	}
+
+	// share reward with voters in proportion to their balance at the vote time of each period
+	rewardList := []*contracts.RewardOfDay{
+		{
+			StartIndex: 1,
+			EndIndex:   2,
+			Reward:     big.NewInt(600),
+			PeriodVoterMap: map[uint64]map[types.Address]*big.Int{
+				1: {addr2: big.NewInt(3000), addr3: big.NewInt(1000)},
+				2: {addr2: big.NewInt(3000), addr3: big.NewInt(1000)},
+			},
+		},
+		{
+			StartIndex: 3,
+			EndIndex:   4,
+			Reward:     big.NewInt(400),
+			PeriodVoterMap: map[uint64]map[types.Address]*big.Int{
+				3: {addr3: big.NewInt(1000)},
+				4: nil,
+			},
+		},
+	}
+	shareMap, left := contracts.CalcVoterRewardShares(rewardList, big.NewInt(1000))
+	if len(shareMap) != 2 || shareMap[addr2].Cmp(big.NewInt(224)) != 0 || shareMap[addr3].Cmp(big.NewInt(174)) != 0 ||
+		left.Cmp(big.NewInt(602)) != 0 {
+		t.Fatalf("calc voter reward shares failed")
+	}
+
+	// withdraw voter reward after fork height
+	forkHeight := uint64(100)
+	defer setForkHeights(func(params *contracts.ContractsParams) {
+		params.RewardForkHeight = forkHeight
+	})()
+	timeFork := time.Unix(timestamp+int64(forkHeight), 0)
+	snapshotFork := &ledger.SnapshotBlock{Height: forkHeight, Timestamp: &timeFork, Hash: types.DataHash([]byte{10, 3})}
+	db.snapshotBlockList = append(db.snapshotBlockList, snapshotFork)
+	voterReward := big.NewInt(375)
+	db.storageMap[addr5][string(abi.GetVoterRewardKey(addr1))], _ = abi.ABIRegister.PackVariable(abi.VariableNameVoterReward, voterReward)
+	withdraw := func(height uint64) ([]*vm_context.VmAccountBlock, error) {
+		block14Data, _ := abi.ABIRegister.PackMethod(abi.MethodNameWithdrawVoterReward, addr4)
+		block14 := &ledger.AccountBlock{
+			Height:         height,
+			ToAddress:      addr5,
+			AccountAddress: addr1,
+			Amount:         big.NewInt(0),
+			TokenId:        ledger.ViteTokenId,
+			BlockType:      ledger.BlockTypeSendCall,
+			Fee:            big.NewInt(0),
+			Data:           block14Data,
+			SnapshotHash:   snapshotFork.Hash,
+			Timestamp:      &blockTime,
+		}
+		vm := NewVM()
+		vm.Debug = true
+		db.addr = addr1
+		sendWithdrawBlockList, isRetry, err := vm.Run(db, block14, nil)
+		if len(sendWithdrawBlockList) != 1 || isRetry || err != nil ||
+			sendWithdrawBlockList[0].AccountBlock.Quota != contracts.WithdrawVoterRewardGas {
+			t.Fatalf("send withdraw voter reward transaction error")
+		}
+		block51 := &ledger.AccountBlock{
+			Height:         height,
+			AccountAddress: addr5,
+			BlockType:      ledger.BlockTypeReceive,
+			FromBlockHash:  types.DataHash([]byte{1, byte(height)}),
+			SnapshotHash:   snapshotFork.Hash,
+			Timestamp:      &blockTime,
+		}
+		vm = NewVM()
+		vm.Debug = true
+		db.addr = addr5
+		receiveWithdrawBlockList, _, err := vm.Run(db, block51, sendWithdrawBlockList[0].AccountBlock)
+		return receiveWithdrawBlockList, err
+	}
+	receiveWithdrawBlockList, err := withdraw(3)
+	if len(receiveWithdrawBlockList) != 2 || err != nil ||
+		receiveWithdrawBlockList[1].AccountBlock.BlockType != ledger.BlockTypeSendReward ||
+		receiveWithdrawBlockList[1].AccountBlock.ToAddress != addr4 ||
+		receiveWithdrawBlockList[1].AccountBlock.Amount.Cmp(voterReward) != 0 ||
+		len(db.storageMap[addr5][string(abi.GetVoterRewardKey(addr1))]) != 0 {
+		t.Fatalf("receive withdraw voter reward transaction error")
+	}
+	if _, err := withdraw(4); err == nil {
+		t.Fatalf("withdraw voter reward twice should fail")
+	}
+}
+
+// setForkHeights changes the fork heights of the main net params used by the tests, the returned function restores them
+func setForkHeights(set func(params *contracts.ContractsParams)) func() {
+	params := contracts.ContractsParamsMainNet
+	set(&contracts.ContractsParamsMainNet)
+	contracts.InitContractsConfig(false)
+	return func() {
+		contracts.ContractsParamsMainNet = params
+		contracts.InitContractsConfig(false)
+	}
+}
+
+func TestRewardIndexRange(t *testing.T) {
+	old := &types.Registration{RewardIndex: 10}
+	if start, end := contracts.RewardIndexRange(old, 10); start != 10 || end != 10 {
+		t.Fatalf("unexpected range %v-%v without reward", start, end)
+	}
+	if start, end := contracts.RewardIndexRange(old, 20); start != 11 || end != 20 {
+		t.Fatalf("unexpected range %v-%v", start, end)
+	}
+}
+
+func TestPrecompiledContractAddress(t *testing.T) {
+	defer setForkHeights(func(params *contracts.ContractsParams) {
+		params.MultisigForkHeight = 10
+	})()
+	if contracts.IsPrecompiledContractAddress(types.AddressMultisig, 9) || !contracts.IsPrecompiledContractAddress(types.AddressMultisig, 10) {
+		t.Fatalf("multisig contract should be activated at the fork height")
+	}
+	if contracts.IsPrecompiledContractAddress(types.AddressVesting, 10) || !contracts.IsPrecompiledContractAddress(types.AddressMintage, 1) {
+		t.Fatalf("unexpected precompiled contract address")
+	}
+	if len(contracts.PrecompiledContractAddressList(9)) != len(types.PrecompiledContractAddressList) ||
+		len(contracts.PrecompiledContractAddressList(10)) != len(types.PrecompiledContractAddressList)+1 {
+		t.Fatalf("unexpected precompiled contract address list")
+	}
+}
+
 func TestCheckCreateConsensusGroupData(t *testing.T) {
 	tests := []struct {
 		data string
@@ -180,7 +180,7 @@ func (db *testDatabase) NewStorageIterator(addr *types.Address, prefix []byte) v
 	items := make([]testIteratorItem, 0)
 	for key, value := range storageMap {
 		if len(prefix) > 0 {
-			if bytes.Equal([]byte(key)[:len(prefix)], prefix) {
+			if bytes.HasPrefix([]byte(key), prefix) {
 				items = append(items, testIteratorItem{[]byte(key), value})
 			}
 		} else {
@@ -235,7 +235,11 @@ func (db *testDatabase) GetVoteMap(snapshotHash types.Hash, gid types.Gid) ([]*t
 func (db *testDatabase) GetBalanceList(snapshotHash types.Hash, tokenTypeId types.TokenTypeId, addressList []types.Address) (map[types.Address]*big.Int, error) {
 	balanceList := make(map[types.Address]*big.Int)
 	for _, addr := range addressList {
-		balanceList[addr] = db.GetBalance(&addr, &tokenTypeId)
+		if balance, ok := db.balanceMap[addr][tokenTypeId]; ok {
+			balanceList[addr] = new(big.Int).Set(balance)
+		} else {
+			balanceList[addr] = big.NewInt(0)
+		}
 	}
 	return balanceList, nil
 }
@@ -55,6 +55,7 @@ func (i *Interpreter) Run(vm *VM, c *contract) (ret []byte, err error) {
 		if err != nil {
 			return nil, err
 		}
+		quotaLeft := c.quotaLeft
 		c.quotaLeft, err = util.UseQuota(c.quotaLeft, cost)
 		if err != nil {
 			return nil, err
@@ -64,6 +65,10 @@ func (i *Interpreter) Run(vm *VM, c *contract) (ret []byte, err error) {
 			mem.resize(memorySize)
 		}
 
+		if vm.Tracer != nil {
+			vm.captureState(currentPc, op, quotaLeft, cost, st, mem)
+		}
+
 		res, err := operation.execute(&pc, vm, c, mem, st)
 
 		if vm.Debug {
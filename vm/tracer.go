@@ -0,0 +1,204 @@
+package vm
+
+import (
+	"math/big"
+
+	"github.com/vitelabs/go-vite/common/types"
+)
+
+// Tracer is notified when the interpreter enters or leaves a contract code
+// and before each opcode is executed. The first enter is the receive of the
+// send block, nested ones are delegate calls.
+type Tracer interface {
+	CaptureEnter(caller, addr, codeAddr types.Address, input []byte, quota uint64, depth int)
+	CaptureState(step *TraceStep)
+	CaptureExit(output []byte, quotaUsed uint64, err error, depth int)
+}
+
+// StorageWrite is the key and value written by a SSTORE.
+type StorageWrite struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// TraceStep is the state of the interpreter before an opcode is executed,
+// stack and memory are shared with the interpreter, copy them to keep.
+type TraceStep struct {
+	Pc        uint64
+	Op        string
+	QuotaLeft uint64
+	Cost      uint64
+	Depth     int
+	Stack     []*big.Int
+	Memory    []byte
+	Storage   *StorageWrite
+}
+
+// StructLog is the json form of a TraceStep.
+type StructLog struct {
+	Pc        uint64        `json:"pc"`
+	Op        string        `json:"op"`
+	QuotaLeft uint64        `json:"quotaLeft"`
+	Cost      uint64        `json:"cost"`
+	Depth     int           `json:"depth"`
+	Stack     []string      `json:"stack"`
+	Memory    []byte        `json:"memory"`
+	Storage   *StorageWrite `json:"storage,omitempty"`
+}
+
+// StructLoggerConfig limits what the StructLogger records, a zero Limit records every step.
+type StructLoggerConfig struct {
+	DisableStack  bool
+	DisableMemory bool
+	Limit         int
+}
+
+// StructLogger records every step of the execution.
+type StructLogger struct {
+	cfg       StructLoggerConfig
+	logs      []*StructLog
+	truncated bool
+	output    []byte
+	err       error
+}
+
+func NewStructLogger(cfg *StructLoggerConfig) *StructLogger {
+	l := &StructLogger{logs: make([]*StructLog, 0)}
+	if cfg != nil {
+		l.cfg = *cfg
+	}
+	return l
+}
+
+func (l *StructLogger) CaptureEnter(caller, addr, codeAddr types.Address, input []byte, quota uint64, depth int) {
+}
+
+func (l *StructLogger) CaptureState(step *TraceStep) {
+	if l.cfg.Limit > 0 && len(l.logs) >= l.cfg.Limit {
+		l.truncated = true
+		return
+	}
+	log := &StructLog{
+		Pc:        step.Pc,
+		Op:        step.Op,
+		QuotaLeft: step.QuotaLeft,
+		Cost:      step.Cost,
+		Depth:     step.Depth,
+		Storage:   step.Storage,
+	}
+	if !l.cfg.DisableStack {
+		log.Stack = make([]string, len(step.Stack))
+		for i, item := range step.Stack {
+			log.Stack[i] = item.String()
+		}
+	}
+	if !l.cfg.DisableMemory {
+		log.Memory = append([]byte(nil), step.Memory...)
+	}
+	l.logs = append(l.logs, log)
+}
+
+func (l *StructLogger) CaptureExit(output []byte, quotaUsed uint64, err error, depth int) {
+	if depth == 1 {
+		l.output, l.err = output, err
+	}
+}
+
+func (l *StructLogger) StructLogs() []*StructLog {
+	return l.logs
+}
+
+// Truncated reports whether steps were dropped after the limit was reached.
+func (l *StructLogger) Truncated() bool {
+	return l.truncated
+}
+
+func (l *StructLogger) Output() []byte {
+	return l.output
+}
+
+func (l *StructLogger) Error() error {
+	return l.err
+}
+
+// CallFrame is a node of the call tree, Calls are the delegate calls made by the frame.
+type CallFrame struct {
+	Caller    types.Address   `json:"caller"`
+	Address   types.Address   `json:"address"`
+	CodeAddr  types.Address   `json:"codeAddress"`
+	Input     []byte          `json:"input"`
+	Output    []byte          `json:"output"`
+	Quota     uint64          `json:"quota"`
+	QuotaUsed uint64          `json:"quotaUsed"`
+	Error     string          `json:"error,omitempty"`
+	Storage   []*StorageWrite `json:"storage,omitempty"`
+	Calls     []*CallFrame    `json:"calls,omitempty"`
+}
+
+// CallTracer records the call tree of the execution with the storage writes of each frame.
+type CallTracer struct {
+	root  *CallFrame
+	stack []*CallFrame
+}
+
+func NewCallTracer() *CallTracer {
+	return &CallTracer{}
+}
+
+func (t *CallTracer) CaptureEnter(caller, addr, codeAddr types.Address, input []byte, quota uint64, depth int) {
+	frame := &CallFrame{
+		Caller:   caller,
+		Address:  addr,
+		CodeAddr: codeAddr,
+		Input:    input,
+		Quota:    quota,
+	}
+	if len(t.stack) == 0 {
+		t.root = frame
+	} else {
+		parent := t.stack[len(t.stack)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+	t.stack = append(t.stack, frame)
+}
+
+func (t *CallTracer) CaptureState(step *TraceStep) {
+	if step.Storage != nil && len(t.stack) > 0 {
+		frame := t.stack[len(t.stack)-1]
+		frame.Storage = append(frame.Storage, step.Storage)
+	}
+}
+
+func (t *CallTracer) CaptureExit(output []byte, quotaUsed uint64, err error, depth int) {
+	if len(t.stack) == 0 {
+		return
+	}
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	frame.Output = output
+	frame.QuotaUsed = quotaUsed
+	if err != nil {
+		frame.Error = err.Error()
+	}
+}
+
+func (t *CallTracer) Result() *CallFrame {
+	return t.root
+}
+
+func (vm *VM) captureState(pc uint64, op opCode, quotaLeft, cost uint64, st *stack, mem *memory) {
+	step := &TraceStep{
+		Pc:        pc,
+		Op:        op.String(),
+		QuotaLeft: quotaLeft,
+		Cost:      cost,
+		Depth:     vm.depth,
+		Stack:     st.data,
+		Memory:    mem.store,
+	}
+	if op == SSTORE && st.len() >= 2 {
+		locHash, _ := types.BigToHash(st.back(0))
+		step.Storage = &StorageWrite{Key: locHash.Bytes(), Value: st.back(1).Bytes()}
+	}
+	vm.Tracer.CaptureState(step)
+}
@@ -0,0 +1,87 @@
+package vm
+
+import (
+	"testing"
+
+	"github.com/vitelabs/go-vite/common/types"
+)
+
+func prepareTraceDb() (*testDatabase, types.Address) {
+	db := NewNoDatabase()
+	addr, _, _ := types.CreateAddress()
+	libAddr, _, _ := types.CreateAddress()
+	db.addr = addr
+	// sstore 1 at 0
+	db.codeMap[libAddr] = []byte{byte(PUSH1), 1, byte(PUSH1), 0, byte(SSTORE), byte(STOP)}
+	// delegate call the lib contract
+	code := []byte{byte(PUSH1), 0, byte(PUSH1), 0, byte(PUSH1), 0, byte(PUSH1), 0, byte(PUSH20)}
+	code = append(code, libAddr.Bytes()...)
+	code = append(code, byte(DELEGATECALL), byte(STOP))
+	db.codeMap[addr] = code
+	return db, addr
+}
+
+func TestStructLogger(t *testing.T) {
+	db, addr := prepareTraceDb()
+	vm := NewVM()
+	tracer := NewStructLogger(nil)
+	vm.Tracer = tracer
+	if _, err := vm.RunOffChain(db, addr, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := tracer.StructLogs()
+	if len(logs) != 11 {
+		t.Fatalf("expected 11 steps, got %v", len(logs))
+	}
+	if logs[5].Op != "DELEGATECALL" || logs[5].Depth != 1 || len(logs[5].Stack) != 5 {
+		t.Fatalf("unexpected delegate call step %v", logs[5])
+	}
+	sstore := logs[8]
+	if sstore.Op != "SSTORE" || sstore.Depth != 2 || sstore.Storage == nil || sstore.Storage.Value[0] != 1 {
+		t.Fatalf("unexpected sstore step %v", sstore)
+	}
+	if sstore.QuotaLeft-sstore.Cost != logs[9].QuotaLeft {
+		t.Fatalf("quota left of steps mismatch")
+	}
+}
+
+func TestStructLoggerConfig(t *testing.T) {
+	db, addr := prepareTraceDb()
+	vm := NewVM()
+	tracer := NewStructLogger(&StructLoggerConfig{DisableStack: true, DisableMemory: true, Limit: 6})
+	vm.Tracer = tracer
+	if _, err := vm.RunOffChain(db, addr, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := tracer.StructLogs()
+	if len(logs) != 6 || !tracer.Truncated() {
+		t.Fatalf("expected 6 steps and truncated, got %v, %v", len(logs), tracer.Truncated())
+	}
+	if logs[5].Op != "DELEGATECALL" || logs[5].Stack != nil || logs[5].Memory != nil {
+		t.Fatalf("unexpected delegate call step %v", logs[5])
+	}
+}
+
+func TestCallTracer(t *testing.T) {
+	db, addr := prepareTraceDb()
+	vm := NewVM()
+	tracer := NewCallTracer()
+	vm.Tracer = tracer
+	if _, err := vm.RunOffChain(db, addr, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	root := tracer.Result()
+	if root == nil || root.CodeAddr != addr || len(root.Calls) != 1 || len(root.Storage) != 0 {
+		t.Fatalf("unexpected root frame %v", root)
+	}
+	call := root.Calls[0]
+	if call.Address != addr || call.CodeAddr == addr || len(call.Storage) != 1 || call.Error != "" {
+		t.Fatalf("unexpected delegate call frame %v", call)
+	}
+	if call.QuotaUsed == 0 || root.QuotaUsed <= call.QuotaUsed {
+		t.Fatalf("unexpected quota used, root %v, call %v", root.QuotaUsed, call.QuotaUsed)
+	}
+}
@@ -13,4 +13,5 @@ var (
 	ErrReturnDataOutOfBounds       = errors.New("evm: return data out of bounds")
 	ErrCalcPoWTwice                = errors.New("calc PoW twice referring to one snapshot block")
 	ErrAbiMethodNotFound           = errors.New("abi: method not found")
+	ErrContractCodeNotExist        = errors.New("contract code not exist")
 )
@@ -23,7 +23,10 @@ import (
 )
 
 type VMConfig struct {
-	Debug bool
+	Debug  bool
+	Tracer Tracer
+	// UnlimitedQuota skips the quota of the account, it is only used to simulate blocks
+	UnlimitedQuota bool
 }
 
 type NodeConfig struct {
@@ -63,7 +66,8 @@ type VM struct {
 	VMConfig
 	abort int32
 	VmContext
-	i *Interpreter
+	i     *Interpreter
+	depth int
 }
 
 func NewVM() *VM {
@@ -86,7 +90,7 @@ func (vm *VM) Run(database vmctxt_interface.VmDatabase, block *ledger.AccountBlo
 		}
 	/* TODO not support create contract this version
 	case ledger.BlockTypeSendCreate:
-		quotaTotal, quotaAddition, err := nodeConfig.calcQuota(
+		quotaTotal, quotaAddition, err := vm.calcQuota(
 			database,
 			block.AccountAddress,
 			contracts.GetPledgeBeneficialAmount(database, block.AccountAddress),
@@ -101,7 +105,7 @@ func (vm *VM) Run(database vmctxt_interface.VmDatabase, block *ledger.AccountBlo
 			return []*vm_context.VmAccountBlock{blockContext}, NoRetry, nil
 		}*/
 	case ledger.BlockTypeSendCall:
-		quotaTotal, quotaAddition, err := nodeConfig.calcQuota(
+		quotaTotal, quotaAddition, err := vm.calcQuota(
 			database,
 			block.AccountAddress,
 			abi.GetPledgeBeneficialAmount(database, block.AccountAddress),
@@ -119,10 +123,47 @@ func (vm *VM) Run(database vmctxt_interface.VmDatabase, block *ledger.AccountBlo
 	return nil, NoRetry, errors.New("transaction type not supported")
 }
 
+func (vm *VM) calcQuota(db vmctxt_interface.VmDatabase, addr types.Address, pledgeAmount *big.Int, difficulty *big.Int) (quotaTotal uint64, quotaAddition uint64, err error) {
+	if vm.UnlimitedQuota {
+		return simulateQuotaLimit, 0, nil
+	}
+	return nodeConfig.calcQuota(db, addr, pledgeAmount, difficulty)
+}
+
 func (vm *VM) Cancel() {
 	atomic.StoreInt32(&vm.abort, 1)
 }
 
+// RunOffChain executes the code of the contract with data as calldata and returns the output,
+// no block is generated, database should be a frozen copy so that nothing is written.
+func (vm *VM) RunOffChain(database vmctxt_interface.VmDatabase, addr types.Address, data []byte) ([]byte, error) {
+	defer monitor.LogTime("vm", "RunOffChain", time.Now())
+	code := database.GetContractCode(&addr)
+	if len(code) == 0 {
+		return nil, util.ErrContractCodeNotExist
+	}
+	sendBlock := &ledger.AccountBlock{
+		BlockType:      ledger.BlockTypeSendCall,
+		AccountAddress: addr,
+		ToAddress:      addr,
+		Amount:         big.NewInt(0),
+		TokenId:        ledger.ViteTokenId,
+		Fee:            big.NewInt(0),
+		Data:           data,
+	}
+	block := &vm_context.VmAccountBlock{
+		&ledger.AccountBlock{
+			BlockType:      ledger.BlockTypeReceive,
+			AccountAddress: addr,
+			FromBlockHash:  sendBlock.Hash,
+		},
+		database}
+	vm.blockList = []*vm_context.VmAccountBlock{block}
+	c := newContract(sendBlock.AccountAddress, addr, block, sendBlock, offChainQuotaLimit, 0)
+	c.setCallCode(addr, code)
+	return c.run(vm)
+}
+
 // send contract create transaction, create address, sub balance and service fee
 func (vm *VM) sendCreate(block *vm_context.VmAccountBlock, quotaTotal, quotaAddition uint64) (*vm_context.VmAccountBlock, error) {
 	defer monitor.LogTime("vm", "SendCreate", time.Now())
@@ -222,7 +263,7 @@ func (vm *VM) sendCall(block *vm_context.VmAccountBlock, quotaTotal, quotaAdditi
 	defer monitor.LogTime("vm", "SendCall", time.Now())
 	// check can make transaction
 	quotaLeft := quotaTotal
-	if p, ok, err := getPrecompiledContract(block.AccountBlock.ToAddress, block.AccountBlock.Data); ok {
+	if p, ok, err := getPrecompiledContract(block.VmContext, block.AccountBlock.ToAddress, block.AccountBlock.Data); ok {
 		if err != nil {
 			return nil, err
 		}
@@ -255,7 +296,7 @@ func (vm *VM) sendCall(block *vm_context.VmAccountBlock, quotaTotal, quotaAdditi
 		block.VmContext.SubBalance(&block.AccountBlock.TokenId, block.AccountBlock.Amount)
 	}
 	var quotaUsed uint64
-	if isPrecompiledContractAddress(block.AccountBlock.AccountAddress) {
+	if isPrecompiledContractAddress(block.VmContext, block.AccountBlock.AccountAddress) {
 		quotaUsed = 0
 	} else {
 		quotaUsed = util.CalcQuotaUsed(quotaTotal, quotaAddition, quotaLeft, 0, nil)
@@ -280,10 +321,15 @@ func getReceiveCallData(db vmctxt_interface.VmDatabase, err error) []byte {
 
 func (vm *VM) receiveCall(block *vm_context.VmAccountBlock, sendBlock *ledger.AccountBlock) (blockList []*vm_context.VmAccountBlock, isRetry bool, err error) {
 	defer monitor.LogTime("vm", "ReceiveCall", time.Now())
-	if p, ok, _ := getPrecompiledContract(block.AccountBlock.AccountAddress, sendBlock.Data); ok {
+	if p, ok, err := getPrecompiledContract(block.VmContext, block.AccountBlock.AccountAddress, sendBlock.Data); ok {
 		vm.blockList = []*vm_context.VmAccountBlock{block}
 		block.VmContext.AddBalance(&sendBlock.TokenId, sendBlock.Amount)
-		blockListToSend, err := p.DoReceive(block.VmContext, block.AccountBlock, sendBlock)
+		// the send block can not be decoded to a method of the contract, such as a transfer made before
+		// the contract is activated, it's refunded
+		var blockListToSend []*contracts.SendBlock
+		if err == nil {
+			blockListToSend, err = p.DoReceive(block.VmContext, block.AccountBlock, sendBlock)
+		}
 		if err == nil {
 			block.AccountBlock.Data = getReceiveCallData(block.VmContext, err)
 			vm.updateBlock(block, err, 0)
@@ -308,6 +354,10 @@ func (vm *VM) receiveCall(block *vm_context.VmAccountBlock, sendBlock *ledger.Ac
 		vm.revert(block)
 
 		// precompiled contract receive error, if amount or fee is not zero, refund
+		var refundData []byte
+		if p != nil {
+			refundData = p.GetRefundData()
+		}
 		refundFlag := false
 		if sendBlock.Amount.Sign() > 0 && sendBlock.Fee.Sign() > 0 && sendBlock.TokenId == ledger.ViteTokenId {
 			refundAmount := new(big.Int).Add(sendBlock.Amount, sendBlock.Fee)
@@ -320,7 +370,7 @@ func (vm *VM) receiveCall(block *vm_context.VmAccountBlock, sendBlock *ledger.Ac
 						refundAmount,
 						ledger.ViteTokenId,
 						vm.VmContext.GetNewBlockHeight(block),
-						p.GetRefundData()),
+						refundData),
 					nil})
 			block.VmContext.AddBalance(&ledger.ViteTokenId, refundAmount)
 			refundFlag = true
@@ -335,7 +385,7 @@ func (vm *VM) receiveCall(block *vm_context.VmAccountBlock, sendBlock *ledger.Ac
 							new(big.Int).Set(sendBlock.Amount),
 							sendBlock.TokenId,
 							vm.VmContext.GetNewBlockHeight(block),
-							p.GetRefundData()),
+							refundData),
 						nil})
 				block.VmContext.AddBalance(&sendBlock.TokenId, sendBlock.Amount)
 				refundFlag = true
@@ -350,7 +400,7 @@ func (vm *VM) receiveCall(block *vm_context.VmAccountBlock, sendBlock *ledger.Ac
 							new(big.Int).Set(sendBlock.Fee),
 							ledger.ViteTokenId,
 							vm.VmContext.GetNewBlockHeight(block),
-							p.GetRefundData()),
+							refundData),
 						nil})
 				block.VmContext.AddBalance(&ledger.ViteTokenId, sendBlock.Fee)
 				refundFlag = true
@@ -372,7 +422,7 @@ func (vm *VM) receiveCall(block *vm_context.VmAccountBlock, sendBlock *ledger.Ac
 		return vm.blockList, NoRetry, err
 	} else {
 		// check can make transaction
-		quotaTotal, quotaAddition, err := nodeConfig.calcQuota(
+		quotaTotal, quotaAddition, err := vm.calcQuota(
 			block.VmContext,
 			block.AccountBlock.AccountAddress,
 			abi.GetPledgeBeneficialAmount(block.VmContext, block.AccountBlock.AccountAddress),
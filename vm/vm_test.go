@@ -563,3 +563,36 @@ func TestVmForTest(t *testing.T) {
 		t.Fatalf("init test vm config failed")
 	}
 }
+
+func TestVmRunUnlimitedQuota(t *testing.T) {
+	InitVmConfig(false, false)
+	viteTotalSupply := new(big.Int).Mul(big.NewInt(1e9), util.AttovPerVite)
+	db, addr1, _, _, snapshot, _ := prepareDb(viteTotalSupply)
+	blockTime := time.Now()
+
+	// an account without pledge
+	addr3, _, _ := types.CreateAddress()
+	db.addr = addr3
+	block := &ledger.AccountBlock{
+		Height:         1,
+		AccountAddress: addr3,
+		ToAddress:      addr1,
+		BlockType:      ledger.BlockTypeSendCall,
+		Fee:            big.NewInt(0),
+		Amount:         big.NewInt(0),
+		TokenId:        ledger.ViteTokenId,
+		SnapshotHash:   snapshot.Hash,
+		Timestamp:      &blockTime,
+		Difficulty:     big.NewInt(0),
+	}
+	if _, _, err := NewVM().Run(db, block, nil); err != util.ErrOutOfQuota {
+		t.Fatalf("expected out of quota, got %v", err)
+	}
+
+	vm := NewVM()
+	vm.UnlimitedQuota = true
+	blockList, _, err := vm.Run(db, block, nil)
+	if err != nil || len(blockList) != 1 || blockList[0].AccountBlock.Quota != 21000 {
+		t.Fatalf("unexpected result of unlimited quota, err %v", err)
+	}
+}
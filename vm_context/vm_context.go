@@ -114,6 +114,17 @@ func NewVmContext(chain Chain, snapshotBlockHash *types.Hash, prevAccountBlockHa
 	return vmContext, nil
 }
 
+// NewVmContextWithEmptyState returns a vm context of addr which has no previous account block,
+// it is used to re-execute the first block of an account that is already in the chain.
+func NewVmContextWithEmptyState(chain Chain, snapshotBlockHash *types.Hash, addr *types.Address) (vmctxt_interface.VmDatabase, error) {
+	vmContext, err := NewVmContext(chain, snapshotBlockHash, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	vmContext.(*VmContext).address = addr
+	return vmContext, nil
+}
+
 func (context *VmContext) CopyAndFreeze() vmctxt_interface.VmDatabase {
 	copyTrie := context.unsavedCache.Trie().Copy()
 	context.frozen = true
@@ -0,0 +1,80 @@
+package watchdog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const notifyTimeout = 10 * time.Second
+
+type notifier interface {
+	Notify(alert *Alert) error
+}
+
+// webhookNotifier posts every change of an alert to the url as a json object
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func newWebhookNotifier(url string) *webhookNotifier {
+	return &webhookNotifier{url: url, client: &http.Client{Timeout: notifyTimeout}}
+}
+
+func (n *webhookNotifier) Notify(alert *Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded with status %d", n.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// commandNotifier runs the local command for every change of an alert, the alert is passed
+// as a json object on the stdin and as the environment variables VITE_ALERT_*.
+// The command is run without a shell, the program and its arguments are separated by spaces
+type commandNotifier struct {
+	args []string
+}
+
+func newCommandNotifier(command string) *commandNotifier {
+	return &commandNotifier{args: strings.Fields(command)}
+}
+
+func (n *commandNotifier) Notify(alert *Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, n.args[0], n.args[1:]...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"VITE_ALERT_NAME="+alert.Name,
+		"VITE_ALERT_STATE="+alert.State,
+		"VITE_ALERT_MESSAGE="+alert.Message)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("command %s failed: %v, output: %s", strings.Join(n.args, " "), err, output)
+	}
+	return nil
+}
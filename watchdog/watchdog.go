@@ -0,0 +1,322 @@
+package watchdog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vitelabs/go-vite/common"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/config"
+	"github.com/vitelabs/go-vite/consensus"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/vite/net"
+)
+
+// Package watchdog checks the node periodically and notifies the operators when a watched producer
+// misses its slots, the synchronization stalls or the head of the chain gets old
+
+const (
+	AlertMissedSlots = "missedSlots"
+	AlertSyncStall   = "syncStall"
+	AlertHeadAge     = "headAge"
+
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+)
+
+const (
+	defaultCheckInterval = 10 * time.Second
+	defaultMissedSlots   = 1
+	defaultHeadAge       = 60 * time.Second
+	defaultSyncTimeout   = 300 * time.Second
+
+	// the periods checked at most at once, the slots before are skipped after a long pause
+	maxCheckPeriods = 10
+)
+
+type Chain interface {
+	GetLatestSnapshotBlock() *ledger.SnapshotBlock
+	GetSnapshotBlockHeadByHeight(height uint64) (*ledger.SnapshotBlock, error)
+}
+
+type Consensus interface {
+	ReadByIndex(gid types.Gid, index uint64) ([]*consensus.Event, uint64, error)
+	VoteTimeToIndex(gid types.Gid, t2 time.Time) (uint64, error)
+	VoteIndexToTime(gid types.Gid, i uint64) (*time.Time, *time.Time, error)
+}
+
+type Net interface {
+	SyncState() net.SyncState
+}
+
+// Alert is notified when it starts firing and when it is resolved, the times are unix seconds
+type Alert struct {
+	Name     string         `json:"name"`
+	Producer *types.Address `json:"producer,omitempty"`
+	State    string         `json:"state"`
+	Message  string         `json:"message"`
+	Since    int64          `json:"since"`
+	Updated  int64          `json:"updated"`
+}
+
+func alertKey(name string, producer *types.Address) string {
+	if producer == nil {
+		return name
+	}
+	return name + "_" + producer.String()
+}
+
+type Watchdog struct {
+	chain     Chain
+	cs        Consensus
+	net       Net
+	producers map[types.Address]bool
+	notifiers []notifier
+
+	checkInterval time.Duration
+	missedSlots   int
+	headAge       time.Duration
+	syncTimeout   time.Duration
+
+	mu           sync.Mutex
+	alerts       map[string]*Alert
+	lastSlotTime time.Time
+	consecutive  map[types.Address]int
+	syncSince    time.Time
+	lastCheck    time.Time
+
+	closed chan struct{}
+	wg     sync.WaitGroup
+	log    log15.Logger
+}
+
+// New watches the slots of the producers, the zero values of the config fall back to the defaults
+func New(cfg *config.Watchdog, producers []types.Address, chain Chain, cs Consensus, net Net) *Watchdog {
+	w := &Watchdog{
+		chain:         chain,
+		cs:            cs,
+		net:           net,
+		producers:     make(map[types.Address]bool),
+		checkInterval: defaultCheckInterval,
+		missedSlots:   defaultMissedSlots,
+		headAge:       defaultHeadAge,
+		syncTimeout:   defaultSyncTimeout,
+		alerts:        make(map[string]*Alert),
+		consecutive:   make(map[types.Address]int),
+		log:           log15.New("module", "watchdog"),
+	}
+	for _, addr := range producers {
+		w.producers[addr] = true
+	}
+	for _, url := range cfg.Webhooks {
+		w.notifiers = append(w.notifiers, newWebhookNotifier(url))
+	}
+	if strings.TrimSpace(cfg.Command) != "" {
+		w.notifiers = append(w.notifiers, newCommandNotifier(cfg.Command))
+	}
+	if cfg.CheckInterval > 0 {
+		w.checkInterval = time.Duration(cfg.CheckInterval) * time.Second
+	}
+	if cfg.MissedSlots > 0 {
+		w.missedSlots = cfg.MissedSlots
+	}
+	if cfg.HeadAge > 0 {
+		w.headAge = time.Duration(cfg.HeadAge) * time.Second
+	}
+	if cfg.SyncTimeout > 0 {
+		w.syncTimeout = time.Duration(cfg.SyncTimeout) * time.Second
+	}
+	return w
+}
+
+func (w *Watchdog) Start() {
+	w.closed = make(chan struct{})
+	w.lastSlotTime = *w.chain.GetLatestSnapshotBlock().Timestamp
+
+	w.wg.Add(1)
+	common.Go(func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.checkInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.closed:
+				return
+			case now := <-ticker.C:
+				w.check(now)
+			}
+		}
+	})
+	w.log.Info("watchdog started.", "producers", len(w.producers), "notifiers", len(w.notifiers))
+}
+
+func (w *Watchdog) Stop() {
+	if w.closed == nil {
+		return
+	}
+	close(w.closed)
+	w.wg.Wait()
+	w.log.Info("watchdog stopped.")
+}
+
+// Alerts returns the firing alerts and the resolved ones
+func (w *Watchdog) Alerts() []*Alert {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	alerts := make([]*Alert, 0, len(w.alerts))
+	for _, alert := range w.alerts {
+		a := *alert
+		alerts = append(alerts, &a)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alertKey(alerts[i].Name, alerts[i].Producer) < alertKey(alerts[j].Name, alerts[j].Producer)
+	})
+	return alerts
+}
+
+// LastCheck returns the time of the last check, it's zero if the watchdog has not checked yet
+func (w *Watchdog) LastCheck() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastCheck
+}
+
+func (w *Watchdog) check(now time.Time) {
+	head := w.chain.GetLatestSnapshotBlock()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lastCheck = now
+	w.checkHeadAge(now, head)
+	w.checkSync(now)
+	if err := w.checkSlots(now, head); err != nil {
+		w.log.Error("check slots fail.", "err", err)
+	}
+}
+
+func (w *Watchdog) checkHeadAge(now time.Time, head *ledger.SnapshotBlock) {
+	age := now.Sub(*head.Timestamp)
+	if age >= w.headAge {
+		w.fire(now, AlertHeadAge, nil, fmt.Sprintf("the latest snapshot block %d is %s old", head.Height, age/time.Second*time.Second))
+	} else {
+		w.resolve(now, AlertHeadAge, nil, fmt.Sprintf("the latest snapshot block %d is %s old", head.Height, age/time.Second*time.Second))
+	}
+}
+
+func (w *Watchdog) checkSync(now time.Time) {
+	state := w.net.SyncState()
+	if state == net.Syncdone {
+		w.syncSince = time.Time{}
+		w.resolve(now, AlertSyncStall, nil, "sync done")
+		return
+	}
+	if w.syncSince.IsZero() {
+		w.syncSince = now
+	}
+	if now.Sub(w.syncSince) >= w.syncTimeout {
+		w.fire(now, AlertSyncStall, nil, fmt.Sprintf("sync state is %s since %s", state, w.syncSince.Format(time.RFC3339)))
+	}
+}
+
+// checkSlots compares the plans of the watched producers with the snapshot blocks produced since the last check,
+// the slots after the latest snapshot block are checked next time
+func (w *Watchdog) checkSlots(now time.Time, head *ledger.SnapshotBlock) error {
+	if len(w.producers) == 0 || !head.Timestamp.After(w.lastSlotTime) {
+		return nil
+	}
+	startIndex, err := w.cs.VoteTimeToIndex(types.SNAPSHOT_GID, w.lastSlotTime)
+	if err != nil {
+		return err
+	}
+	endIndex, err := w.cs.VoteTimeToIndex(types.SNAPSHOT_GID, *head.Timestamp)
+	if err != nil {
+		return err
+	}
+	from := w.lastSlotTime
+	if endIndex-startIndex >= maxCheckPeriods {
+		startIndex = endIndex - maxCheckPeriods + 1
+		sTime, _, err := w.cs.VoteIndexToTime(types.SNAPSHOT_GID, startIndex)
+		if err != nil {
+			return err
+		}
+		from = sTime.Add(-time.Nanosecond)
+	}
+
+	produced := make(map[int64]types.Address)
+	for block := head; block != nil && block.Timestamp.After(from); {
+		produced[block.Timestamp.Unix()] = block.Producer()
+		if block.Height <= types.GenesisHeight {
+			break
+		}
+		if block, err = w.chain.GetSnapshotBlockHeadByHeight(block.Height - 1); err != nil {
+			return err
+		}
+	}
+
+	for i := startIndex; i <= endIndex; i++ {
+		events, _, err := w.cs.ReadByIndex(types.SNAPSHOT_GID, i)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			if !w.producers[e.Address] || !e.Timestamp.After(from) || e.Timestamp.After(*head.Timestamp) {
+				continue
+			}
+			addr := e.Address
+			if producer, ok := produced[e.Timestamp.Unix()]; ok && producer == addr {
+				w.consecutive[addr] = 0
+				w.resolve(now, AlertMissedSlots, &addr, fmt.Sprintf("produced the slot at %s", e.Timestamp.Format(time.RFC3339)))
+				continue
+			}
+			w.consecutive[addr]++
+			w.log.Warn("producer missed a slot.", "producer", addr, "slot", e.Timestamp, "consecutive", w.consecutive[addr])
+			if w.consecutive[addr] >= w.missedSlots {
+				w.fire(now, AlertMissedSlots, &addr, fmt.Sprintf("missed %d consecutive slots, the last at %s", w.consecutive[addr], e.Timestamp.Format(time.RFC3339)))
+			}
+		}
+	}
+	w.lastSlotTime = *head.Timestamp
+	return nil
+}
+
+func (w *Watchdog) fire(now time.Time, name string, producer *types.Address, message string) {
+	key := alertKey(name, producer)
+	alert, ok := w.alerts[key]
+	if ok && alert.State == StateFiring {
+		alert.Message = message
+		alert.Updated = now.Unix()
+		return
+	}
+	alert = &Alert{Name: name, Producer: producer, State: StateFiring, Message: message, Since: now.Unix(), Updated: now.Unix()}
+	w.alerts[key] = alert
+	w.log.Error("alert fired.", "name", name, "producer", producer, "message", message)
+	w.notify(alert)
+}
+
+func (w *Watchdog) resolve(now time.Time, name string, producer *types.Address, message string) {
+	alert, ok := w.alerts[alertKey(name, producer)]
+	if !ok || alert.State != StateFiring {
+		return
+	}
+	alert.State = StateResolved
+	alert.Message = message
+	alert.Updated = now.Unix()
+	w.log.Info("alert resolved.", "name", name, "producer", producer, "message", message)
+	w.notify(alert)
+}
+
+func (w *Watchdog) notify(alert *Alert) {
+	a := *alert
+	for _, n := range w.notifiers {
+		tmpN := n
+		common.Go(func() {
+			if err := tmpN.Notify(&a); err != nil {
+				w.log.Error("notify alert fail.", "name", a.Name, "err", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package watchdog
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/config"
+	"github.com/vitelabs/go-vite/consensus"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/vite/net"
+)
+
+// every period has 4 slots of 1 second, produced in turn by the two producers
+const testSlotsPerPeriod = 4
+
+var testGenesisTime = time.Unix(1500000000, 0)
+
+type testProducer struct {
+	addr   types.Address
+	pubkey []byte
+}
+
+func newTestProducer() *testProducer {
+	addr, priv, _ := types.CreateAddress()
+	return &testProducer{addr, priv.PubByte()}
+}
+
+type testChain struct {
+	blocks []*ledger.SnapshotBlock
+}
+
+func (c *testChain) GetLatestSnapshotBlock() *ledger.SnapshotBlock {
+	return c.blocks[len(c.blocks)-1]
+}
+
+func (c *testChain) GetSnapshotBlockHeadByHeight(height uint64) (*ledger.SnapshotBlock, error) {
+	if height == 0 || height > uint64(len(c.blocks)) {
+		return nil, nil
+	}
+	return c.blocks[height-1], nil
+}
+
+func (c *testChain) produce(slot int, p *testProducer) {
+	t := testGenesisTime.Add(time.Duration(slot) * time.Second)
+	c.blocks = append(c.blocks, &ledger.SnapshotBlock{
+		Height:    uint64(len(c.blocks) + 1),
+		Timestamp: &t,
+		PublicKey: p.pubkey,
+	})
+}
+
+type testConsensus struct {
+	producers []*testProducer
+}
+
+func (cs *testConsensus) ReadByIndex(gid types.Gid, index uint64) ([]*consensus.Event, uint64, error) {
+	var events []*consensus.Event
+	for i := 0; i < testSlotsPerPeriod; i++ {
+		slot := int(index)*testSlotsPerPeriod + i
+		stime := testGenesisTime.Add(time.Duration(slot) * time.Second)
+		events = append(events, &consensus.Event{
+			Gid:       gid,
+			Address:   cs.producers[slot%len(cs.producers)].addr,
+			Stime:     stime,
+			Etime:     stime.Add(time.Second),
+			Index:     index,
+			Timestamp: stime,
+		})
+	}
+	return events, index, nil
+}
+
+func (cs *testConsensus) VoteTimeToIndex(gid types.Gid, t2 time.Time) (uint64, error) {
+	return uint64(t2.Sub(testGenesisTime)/time.Second) / testSlotsPerPeriod, nil
+}
+
+func (cs *testConsensus) VoteIndexToTime(gid types.Gid, i uint64) (*time.Time, *time.Time, error) {
+	sTime := testGenesisTime.Add(time.Duration(i*testSlotsPerPeriod) * time.Second)
+	eTime := sTime.Add(testSlotsPerPeriod * time.Second)
+	return &sTime, &eTime, nil
+}
+
+type testNet struct {
+	state net.SyncState
+}
+
+func (n *testNet) SyncState() net.SyncState {
+	return n.state
+}
+
+func slotTime(slot int) time.Time {
+	return testGenesisTime.Add(time.Duration(slot) * time.Second)
+}
+
+func firing(w *Watchdog, name string, producer *types.Address) bool {
+	alert, ok := w.alerts[alertKey(name, producer)]
+	return ok && alert.State == StateFiring
+}
+
+func TestWatchdogMissedSlots(t *testing.T) {
+	a, b := newTestProducer(), newTestProducer()
+	chain := &testChain{}
+	chain.produce(0, a)
+
+	w := New(&config.Watchdog{MissedSlots: 2}, []types.Address{a.addr}, chain, &testConsensus{[]*testProducer{a, b}}, &testNet{net.Syncdone})
+	w.lastSlotTime = slotTime(0)
+
+	// a misses the slot 2, one missed slot is tolerated
+	chain.produce(1, b)
+	chain.produce(3, b)
+	w.check(slotTime(4))
+	if firing(w, AlertMissedSlots, &a.addr) || w.consecutive[a.addr] != 1 {
+		t.Fatalf("unexpected state after one missed slot, consecutive %d", w.consecutive[a.addr])
+	}
+
+	// a misses the slot 4 in the next period too
+	chain.produce(5, b)
+	w.check(slotTime(6))
+	if !firing(w, AlertMissedSlots, &a.addr) {
+		t.Fatal("expected the missed slots alert to fire")
+	}
+	if firing(w, AlertMissedSlots, &b.addr) {
+		t.Fatal("the slots of an unwatched producer are checked")
+	}
+
+	// a produces the slot 6
+	chain.produce(6, a)
+	w.check(slotTime(7))
+	if firing(w, AlertMissedSlots, &a.addr) || w.consecutive[a.addr] != 0 {
+		t.Fatal("expected the missed slots alert to be resolved")
+	}
+	alerts := w.Alerts()
+	if len(alerts) != 1 || alerts[0].State != StateResolved || *alerts[0].Producer != a.addr {
+		t.Fatalf("unexpected alerts %v", alerts)
+	}
+}
+
+func TestWatchdogHeadAgeAndSync(t *testing.T) {
+	a := newTestProducer()
+	chain := &testChain{}
+	chain.produce(0, a)
+	n := &testNet{net.Syncing}
+
+	w := New(&config.Watchdog{HeadAge: 30, SyncTimeout: 60}, nil, chain, &testConsensus{[]*testProducer{a}}, n)
+	w.check(slotTime(10))
+	if firing(w, AlertHeadAge, nil) || firing(w, AlertSyncStall, nil) {
+		t.Fatal("unexpected alert")
+	}
+
+	w.check(slotTime(40))
+	if !firing(w, AlertHeadAge, nil) {
+		t.Fatal("expected the head age alert to fire")
+	}
+	if firing(w, AlertSyncStall, nil) {
+		t.Fatal("sync stall alert fired before the timeout")
+	}
+
+	w.check(slotTime(70))
+	if !firing(w, AlertSyncStall, nil) {
+		t.Fatal("expected the sync stall alert to fire")
+	}
+
+	n.state = net.Syncdone
+	chain.produce(70, a)
+	w.check(slotTime(71))
+	if firing(w, AlertHeadAge, nil) || firing(w, AlertSyncStall, nil) {
+		t.Fatal("expected the alerts to be resolved")
+	}
+}
+
+func TestWatchdogWebhook(t *testing.T) {
+	received := make(chan *Alert, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		alert := new(Alert)
+		if err := json.NewDecoder(r.Body).Decode(alert); err != nil {
+			t.Error(err)
+		}
+		received <- alert
+	}))
+	defer server.Close()
+
+	a := newTestProducer()
+	chain := &testChain{}
+	chain.produce(0, a)
+	w := New(&config.Watchdog{Webhooks: []string{server.URL}, HeadAge: 30}, nil, chain, &testConsensus{[]*testProducer{a}}, &testNet{net.Syncdone})
+
+	w.check(slotTime(40))
+	// no notification while the alert keeps firing
+	w.check(slotTime(50))
+	chain.produce(50, a)
+	w.check(slotTime(51))
+
+	// the notifications are sent concurrently, so they may arrive in any order
+	states := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		select {
+		case alert := <-received:
+			if alert.Name != AlertHeadAge {
+				t.Fatalf("unexpected alert %+v", alert)
+			}
+			states[alert.State] = true
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook not called")
+		}
+	}
+	if !states[StateFiring] || !states[StateResolved] {
+		t.Fatalf("unexpected alert states %v", states)
+	}
+	select {
+	case alert := <-received:
+		t.Fatalf("unexpected notification %+v", alert)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestCommandNotifier(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watchdog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "alert.json")
+
+	// the arguments are passed to the program
+	n := newCommandNotifier("cp /dev/stdin " + file)
+	if err := n.Notify(&Alert{Name: AlertHeadAge, State: StateFiring}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alert := new(Alert)
+	if err := json.Unmarshal(data, alert); err != nil {
+		t.Fatal(err)
+	}
+	if alert.Name != AlertHeadAge || alert.State != StateFiring {
+		t.Fatalf("unexpected alert %+v", alert)
+	}
+}